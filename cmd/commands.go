@@ -0,0 +1,414 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"pln/conf"
+	"pln/models"
+	"pln/repo"
+	"pln/service"
+
+	"github.com/rs/zerolog/log"
+)
+
+// runCommand 执行命令行子命令
+func runCommand(args []string) error {
+	switch args[0] {
+	case "backup":
+		return runBackup(args[1:])
+	case "restore":
+		return runRestore(args[1:])
+	case "export":
+		return runExport(args[1:])
+	case "import":
+		return runImport(args[1:])
+	case "reindex":
+		return runReindex(args[1:])
+	case "tags":
+		return runTags(args[1:])
+	case "user":
+		return runUser(args[1:])
+	case "apikey":
+		return runAPIKey(args[1:])
+	default:
+		return fmt.Errorf("未知命令: %s（可用命令: backup, restore, export, import, reindex, tags, user, apikey）", args[0])
+	}
+}
+
+// runBackup pln backup [-o 输出文件] [-since 上次备份] [-manifest 清单输出]
+func runBackup(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	output := fs.String("o", fmt.Sprintf("pln-backup-%s.tar", time.Now().Format("20060102150405")), "备份输出文件")
+	since := fs.String("since", "", "增量备份：上一次的备份归档或清单 JSON")
+	manifestOut := fs.String("manifest", "", "额外将清单写入该文件，便于下次增量备份")
+	_ = fs.Parse(args)
+
+	var base *service.BackupManifest
+	if *since != "" {
+		m, err := loadBackupManifest(*since)
+		if err != nil {
+			return err
+		}
+		base = m
+	}
+
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("创建备份文件失败: %w", err)
+	}
+	defer out.Close()
+
+	backupService := service.NewBackupService(db, conf.Config.FileServer.StoragePath)
+	manifest, err := backupService.Backup(context.Background(), out, base)
+	if err != nil {
+		_ = os.Remove(*output)
+		return err
+	}
+
+	if *manifestOut != "" {
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*manifestOut, data, 0644); err != nil {
+			return fmt.Errorf("写入清单失败: %w", err)
+		}
+	}
+
+	log.Info().Str("output", *output).Int("files", len(manifest.Files)).Msg("备份已写入")
+	return nil
+}
+
+// runRestore pln restore <备份文件>
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("用法: restore <备份文件>")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("打开备份文件失败: %w", err)
+	}
+	defer f.Close()
+
+	manifest, err := service.RestoreBackup(f, conf.GetDSN(), conf.Config.FileServer.StoragePath)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Time("backup_created_at", manifest.CreatedAt).
+		Int("files", len(manifest.Files)).
+		Msg("恢复完成，原数据已保留为 .bak-* 文件")
+	return nil
+}
+
+// loadBackupManifest 读取清单：支持备份归档（.tar）或单独保存的清单 JSON
+func loadBackupManifest(p string) (*service.BackupManifest, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, fmt.Errorf("打开基准备份失败: %w", err)
+	}
+	defer f.Close()
+
+	var manifest service.BackupManifest
+	if err := json.NewDecoder(f).Decode(&manifest); err == nil {
+		return &manifest, nil
+	}
+
+	if _, err := f.Seek(0, 0); err != nil {
+		return nil, err
+	}
+	return service.ReadBackupManifest(f)
+}
+
+// stringList 可重复出现的命令行参数
+type stringList []string
+
+func (l *stringList) String() string     { return strings.Join(*l, ",") }
+func (l *stringList) Set(v string) error { *l = append(*l, v); return nil }
+
+// newExportService 为命令行构建导出/导入所需的服务
+func newExportService() (*service.ExportService, error) {
+	db, err := openDB()
+	if err != nil {
+		return nil, err
+	}
+
+	artworkRepo := repo.NewArtworkRepo(db)
+	artworkService := service.NewArtworkService(artworkRepo, service.NewTagService(repo.NewTagRepo(db), artworkRepo), repo.NewSmartCollectionRepo(db), conf.Config.Content)
+	fileService := service.NewFileService(conf.Config, artworkRepo, newUploader())
+	ingestService := service.NewIngestService(conf.Config, artworkService, fileService)
+
+	return service.NewExportService(artworkRepo, artworkService, fileService, ingestService), nil
+}
+
+// runExport pln export [-o 输出文件] [-tags 标签]...
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("o", fmt.Sprintf("pln-export-%s.zip", time.Now().Format("20060102150405")), "导出文件")
+	var tags stringList
+	fs.Var(&tags, "tags", "标签过滤，可重复指定")
+	_ = fs.Parse(args)
+
+	filters := make(map[string]any)
+	if len(tags) > 0 {
+		filters["tags"] = []string(tags)
+	}
+
+	exportService, err := newExportService()
+	if err != nil {
+		return err
+	}
+
+	out, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("创建导出文件失败: %w", err)
+	}
+	defer out.Close()
+
+	manifest, err := exportService.Export(context.Background(), out, filters)
+	if err != nil {
+		_ = os.Remove(*output)
+		return err
+	}
+
+	log.Info().Str("output", *output).Int("count", len(manifest.Items)).Msg("导出完成")
+	return nil
+}
+
+// runImport pln import <导出文件>
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("用法: import <导出文件>")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("打开导出文件失败: %w", err)
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	exportService, err := newExportService()
+	if err != nil {
+		return err
+	}
+
+	results, err := exportService.Import(context.Background(), f, stat.Size())
+	if err != nil {
+		return err
+	}
+
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+		if r.Status != models.IngestStatusCreated {
+			log.Info().
+				Str("file", r.File).
+				Str("status", r.Status).
+				Uint("existing_id", r.ExistingID).
+				Str("reason", r.Reason).
+				Msg("跳过")
+		}
+	}
+
+	log.Info().
+		Int("created", counts[models.IngestStatusCreated]).
+		Int("duplicate", counts[models.IngestStatusDuplicate]).
+		Int("similar", counts[models.IngestStatusSimilar]).
+		Int("rejected", counts[models.IngestStatusRejected]).
+		Msg("导入完成")
+	return nil
+}
+
+// runReindex pln reindex：重建全文索引
+func runReindex(args []string) error {
+	fs := flag.NewFlagSet("reindex", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+
+	if !repo.FTSAvailable(db) {
+		return fmt.Errorf("SQLite 未启用 FTS5，请使用 -tags sqlite_fts5 编译")
+	}
+
+	start := time.Now()
+	if err := repo.RebuildFTS(db); err != nil {
+		return fmt.Errorf("重建全文索引失败: %w", err)
+	}
+
+	log.Info().Dur("elapsed", time.Since(start)).Msg("全文索引已重建")
+	return nil
+}
+
+// runTags pln tags reapply：对已有作品重新应用标签别名与蕴含规则
+func runTags(args []string) error {
+	if len(args) == 0 || args[0] != "reapply" {
+		return fmt.Errorf("用法: tags reapply")
+	}
+
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+
+	artworkRepo := repo.NewArtworkRepo(db)
+	tagService := service.NewTagService(repo.NewTagRepo(db), artworkRepo)
+
+	result, err := tagService.ReapplyRules(service.WithActor(context.Background(), "cli"))
+	if err != nil {
+		return err
+	}
+
+	log.Info().Int("scanned", result.Scanned).Int("updated", result.Updated).Msg("标签规则已重新应用")
+	return nil
+}
+
+// runUser pln user create -username 用户名 -password 密码 [-role 角色]
+func runUser(args []string) error {
+	if len(args) == 0 || args[0] != "create" {
+		return fmt.Errorf("用法: user create -username <用户名> -password <密码> [-role 角色]")
+	}
+
+	fs := flag.NewFlagSet("user create", flag.ExitOnError)
+	username := fs.String("username", "", "用户名")
+	password := fs.String("password", "", "密码，8 到 72 字节")
+	role := fs.String("role", models.RoleViewer, "角色: "+strings.Join(models.Roles, ", "))
+	_ = fs.Parse(args[1:])
+
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+
+	userService := service.NewUserService(repo.NewUserRepo(db), conf.Config.Auth.SessionTTL)
+	user, err := userService.CreateUser(service.WithActor(context.Background(), "cli"), &models.UserCreateRequest{
+		Username: *username,
+		Password: *password,
+		Role:     *role,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info().Uint("id", user.ID).Str("username", user.Username).Str("role", user.Role).Msg("用户已创建")
+	return nil
+}
+
+// runAPIKey pln apikey create|list|rotate|revoke
+func runAPIKey(args []string) error {
+	usage := fmt.Errorf("用法: apikey create -name <名称> -scope <权限范围>... [-expires 有效期] | apikey list | apikey rotate <id> | apikey revoke <id>")
+	if len(args) == 0 {
+		return usage
+	}
+
+	db, err := openDB()
+	if err != nil {
+		return err
+	}
+	apiKeyService := service.NewAPIKeyService(repo.NewAPIKeyRepo(db))
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("apikey create", flag.ExitOnError)
+		name := fs.String("name", "", "名称")
+		expires := fs.Duration("expires", 0, "有效期，如 720h，0 表示永不过期")
+		var scopes stringList
+		fs.Var(&scopes, "scope", "权限范围，可重复指定: "+strings.Join(models.Scopes, ", "))
+		_ = fs.Parse(args[1:])
+
+		req := &models.APIKeyCreateRequest{Name: *name, Scopes: scopes}
+		if *expires > 0 {
+			t := time.Now().Add(*expires)
+			req.ExpiresAt = &t
+		}
+		created, err := apiKeyService.Create(service.WithActor(context.Background(), "cli"), req)
+		if err != nil {
+			return err
+		}
+		printAPIKey(created)
+		return nil
+
+	case "list":
+		keys, err := apiKeyService.List()
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			event := log.Info().
+				Uint("id", k.ID).
+				Str("name", k.Name).
+				Str("prefix", k.Prefix).
+				Strs("scopes", k.Scopes)
+			if k.ExpiresAt != nil {
+				event = event.Time("expires_at", *k.ExpiresAt)
+			}
+			if k.LastUsedAt != nil {
+				event = event.Time("last_used_at", *k.LastUsedAt)
+			}
+			if k.RevokedAt != nil {
+				event = event.Time("revoked_at", *k.RevokedAt)
+			}
+			event.Msg("API Key")
+		}
+		return nil
+
+	case "rotate", "revoke":
+		if len(args) != 2 {
+			return usage
+		}
+		id, err := strconv.ParseUint(args[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("无效的 API Key ID: %s", args[1])
+		}
+		ctx := service.WithActor(context.Background(), "cli")
+		if args[0] == "revoke" {
+			if _, err := apiKeyService.Revoke(ctx, uint(id)); err != nil {
+				return err
+			}
+			log.Info().Uint64("id", id).Msg("API Key 已吊销")
+			return nil
+		}
+		created, err := apiKeyService.Rotate(ctx, uint(id))
+		if err != nil {
+			return err
+		}
+		printAPIKey(created)
+		return nil
+
+	default:
+		return usage
+	}
+}
+
+// printAPIKey 输出新密钥，明文只显示这一次
+func printAPIKey(k *models.APIKeyCreated) {
+	log.Info().Uint("id", k.ID).Str("name", k.Name).Strs("scopes", k.Scopes).Msg("API Key 已生成，明文只显示这一次")
+	fmt.Println(k.Key)
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"fmt"
 	"io"
+	"net/netip"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -10,6 +12,9 @@ import (
 	"pln/conf"
 	_ "pln/docs"
 	"pln/handler"
+	"pln/health"
+	"pln/metrics"
+	"pln/middleware"
 	"pln/models"
 	"pln/repo"
 	"pln/service"
@@ -21,7 +26,6 @@ import (
 
 	"github.com/Yuelioi/gkit/log/zerologx"
 	"github.com/Yuelioi/gkit/log/zerologx/adapter/gormzerolog"
-	"github.com/Yuelioi/gkit/web/gin/middleware/apikey"
 	"github.com/Yuelioi/gkit/web/gin/middleware/log/gzero"
 	"github.com/Yuelioi/gkit/web/gin/middleware/requestid"
 
@@ -36,61 +40,224 @@ import (
 
 var configPath = "./config.yaml"
 
-func main() {
-	// 初始化日志
-	logger := zerologx.Default()
-	log.Logger = logger
-	zerolog.DefaultContextLogger = &logger
-
-	// 加载配置
-	if err := conf.LoadConfig(configPath); err != nil {
-		log.Fatal().Err(err).Msg("配置加载失败")
-	}
-
+// openDB 打开数据库并执行自动迁移
+func openDB() (*gorm.DB, error) {
 	dbPath := conf.GetDSN()
 
 	_, err := os.Stat(dbPath)
 	if os.IsNotExist(err) {
-		err = os.MkdirAll(filepath.Dir(dbPath), 0755)
-		if err != nil {
-			panic(err)
+		if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+			return nil, err
 		}
 	}
 
 	// 初始化数据库
 	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
-		Logger: gormzerolog.New(),
+		Logger: metrics.NewGormLogger(gormzerolog.New()),
 	})
 	if err != nil {
-		log.Fatal().Err(err).Msg("数据库连接失败")
+		return nil, fmt.Errorf("数据库连接失败: %w", err)
 	}
 
 	// 自动迁移
-	if err := db.AutoMigrate(models.Artwork{}); err != nil {
-		log.Fatal().Err(err).Msg("数据库迁移失败")
+	if err := db.AutoMigrate(
+		models.Artwork{},
+		models.TagAlias{}, models.TagImplication{}, models.TagNamespace{}, models.TagRevision{},
+		models.Collection{}, models.CollectionItem{}, models.SmartCollection{},
+		models.Reaction{}, models.BookmarkFolder{}, models.Comment{},
+		models.User{}, models.Session{}, models.APIKey{}, models.AuditLog{},
+	); err != nil {
+		return nil, fmt.Errorf("数据库迁移失败: %w", err)
 	}
 
-	key := conf.InitAPIKey()
+	if err := repo.SeedTagNamespaces(db); err != nil {
+		return nil, fmt.Errorf("初始化标签命名空间失败: %w", err)
+	}
 
-	// 禁用默认 Gin 输出
-	gin.DefaultWriter = io.Discard
-	gin.DefaultErrorWriter = io.Discard
+	// 全文索引
+	if ok, err := repo.SetupFTS(db); err != nil {
+		return nil, fmt.Errorf("初始化全文索引失败: %w", err)
+	} else if !ok {
+		log.Warn().Msg("SQLite 未启用 FTS5（编译时需 -tags sqlite_fts5），关键词检索退化为 LIKE 匹配")
+	}
 
-	// 初始化仓储、服务和处理器
-	artworkRepo := repo.NewArtworkRepo(db)
-	artworkService := service.NewArtworkService(artworkRepo)
+	return db, nil
+}
 
-	uploader := storage.NewLocalUploader(
+// newUploader 创建本地文件存储
+func newUploader() *storage.LocalUploader {
+	return storage.NewLocalUploader(
 		conf.Config.FileServer.StoragePath,
 		"/api/v1/files",
 		conf.Config.ThumbnailConfig,
 		conf.Config.PreviewConfig,
 	)
+}
+
+// newRateLimits 创建上传、点赞收藏、登录与评论的限流中间件，未启用时不限制
+func newRateLimits(proxies []netip.Prefix) (uploads, reactions, login, comments gin.HandlerFunc) {
+	cfg := conf.Config.RateLimit
+	if !cfg.Enabled {
+		pass := func(c *gin.Context) { c.Next() }
+		return pass, pass, pass, pass
+	}
+
+	quotas := func(q conf.RateLimitQuota) (ip, key middleware.Quota) {
+		return middleware.Quota{Requests: q.Requests, Per: q.Per, Burst: q.Burst},
+			middleware.Quota{Requests: q.KeyRequests, Per: q.Per}
+	}
+	uploadIP, uploadKey := quotas(cfg.Uploads)
+	reactionIP, reactionKey := quotas(cfg.Reactions)
+	loginIP, loginKey := quotas(cfg.Login)
+	commentIP, commentKey := quotas(cfg.Comments)
+
+	return middleware.RateLimit("uploads", uploadIP, uploadKey, proxies),
+		middleware.RateLimit("reactions", reactionIP, reactionKey, proxies),
+		middleware.RateLimit("login", loginIP, loginKey, proxies),
+		middleware.RateLimit("comments", commentIP, commentKey, proxies)
+}
+
+// startAuditCleanup 定期删除超过保留期限的审计记录，保留期限为 0 时不清理
+func startAuditCleanup(audit service.AuditService, cfg conf.AuditConfig, checker *health.Checker) {
+	if cfg.Retention <= 0 || cfg.CleanupInterval <= 0 {
+		return
+	}
+
+	prune := func() {
+		n, err := audit.Prune(cfg.Retention)
+		if err != nil {
+			log.Warn().Err(err).Msg("清理过期审计记录失败")
+			return
+		}
+		if n > 0 {
+			log.Info().Int64("deleted", n).Msg("已清理过期审计记录")
+		}
+	}
+
+	worker := checker.Worker("audit_cleanup", cfg.CleanupInterval)
+	go func() {
+		prune()
+		worker.Beat()
+		ticker := time.NewTicker(cfg.CleanupInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			prune()
+			worker.Beat()
+		}
+	}()
+}
+
+func main() {
+	// 初始化日志
+	logger := zerologx.Default()
+	log.Logger = logger
+	zerolog.DefaultContextLogger = &logger
+
+	// 加载配置
+	if err := conf.LoadConfig(configPath); err != nil {
+		log.Fatal().Err(err).Msg("配置加载失败")
+	}
+
+	// 子命令（backup / restore 等）
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1:]); err != nil {
+			log.Fatal().Err(err).Str("command", os.Args[1]).Msg("命令执行失败")
+		}
+		return
+	}
+
+	db, err := openDB()
+	if err != nil {
+		log.Fatal().Err(err).Msg("数据库初始化失败")
+	}
+
+	// 禁用默认 Gin 输出
+	gin.DefaultWriter = io.Discard
+	gin.DefaultErrorWriter = io.Discard
+
+	// 初始化仓储、服务和处理器
+	artworkRepo := repo.NewArtworkRepo(db)
+	tagService := service.NewTagService(repo.NewTagRepo(db), artworkRepo)
+	smartCollectionRepo := repo.NewSmartCollectionRepo(db)
+	artworkService := service.NewArtworkService(artworkRepo, tagService, smartCollectionRepo, conf.Config.Content)
+
+	uploader := newUploader()
 
 	uploadService := service.NewFileService(
 		conf.Config, artworkRepo, uploader,
 	)
-	artworkHandler := handler.NewArtworkHandler(artworkService, uploadService, conf.Config)
+	ingestService := service.NewIngestService(conf.Config, artworkService, uploadService)
+	artworkHandler := handler.NewArtworkHandler(artworkService, uploadService, ingestService, conf.Config)
+
+	backupService := service.NewBackupService(db, conf.Config.FileServer.StoragePath)
+	backupHandler := handler.NewBackupHandler(backupService)
+
+	exportService := service.NewExportService(artworkRepo, artworkService, uploadService, ingestService)
+	exportHandler := handler.NewExportHandler(exportService)
+
+	urlImportService := service.NewURLImportService(conf.Config.Import, ingestService)
+	urlImportHandler := handler.NewURLImportHandler(urlImportService)
+
+	tagHandler := handler.NewTagHandler(tagService)
+
+	collectionService := service.NewCollectionService(repo.NewCollectionRepo(db), artworkRepo, tagService)
+	collectionHandler := handler.NewCollectionHandler(collectionService)
+
+	smartCollectionService := service.NewSmartCollectionService(smartCollectionRepo, artworkService)
+	smartCollectionHandler := handler.NewSmartCollectionHandler(smartCollectionService)
+
+	moderationService := service.NewModerationService(artworkRepo, uploadService, tagService)
+	moderationHandler := handler.NewModerationHandler(moderationService)
+
+	reactionHandler := handler.NewReactionHandler(service.NewReactionService(artworkRepo))
+	bookmarkHandler := handler.NewBookmarkHandler(service.NewBookmarkService(repo.NewBookmarkRepo(db), artworkRepo, tagService))
+
+	proxies, err := middleware.ParseTrustedProxies(conf.Config.RateLimit.TrustedProxies)
+	if err != nil {
+		log.Fatal().Err(err).Msg("可信代理配置无效")
+	}
+
+	auditService := service.NewAuditService(repo.NewAuditRepo(db))
+	auditHandler := handler.NewAuditHandler(auditService)
+	// 就绪检查：数据库、存储目录与后台任务
+	checker := health.NewChecker()
+	checker.Add("database", health.Database(db))
+	checker.Add("storage", health.Writable(conf.Config.FileServer.StoragePath))
+	if minFree := conf.Config.Health.MinFreeDisk; minFree > 0 {
+		checker.Add("disk", health.DiskSpace(conf.Config.FileServer.StoragePath, minFree))
+	}
+
+	startAuditCleanup(auditService, conf.Config.Audit, checker)
+
+	commentHandler := handler.NewCommentHandler(service.NewCommentService(repo.NewCommentRepo(db), artworkRepo), proxies)
+
+	apiKeyService := service.NewAPIKeyService(repo.NewAPIKeyRepo(db))
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	key, err := apiKeyService.Bootstrap(conf.ApiKeyFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("初始化 API Key 失败")
+	}
+	if key != "" {
+		// 明文不经过日志，只输出到标准错误
+		log.Warn().Msg("已创建 admin 权限的 API Key，明文已输出到标准错误，只显示这一次")
+		fmt.Fprintln(os.Stderr, key)
+	}
+
+	userService := service.NewUserService(repo.NewUserRepo(db), conf.Config.Auth.SessionTTL)
+	authHandler := handler.NewAuthHandler(userService, conf.Config.Auth.SessionTTL, proxies)
+	userHandler := handler.NewUserHandler(userService)
+
+	// 单点登录，未启用时只提供配置查询
+	var oidcService service.OIDCService
+	if conf.Config.OIDC.Enabled {
+		if oidcService, err = service.NewOIDCService(conf.Config.OIDC, userService); err != nil {
+			log.Fatal().Err(err).Msg("单点登录配置无效")
+		}
+	}
+	oidcHandler := handler.NewOIDCHandler(oidcService, conf.Config.OIDC.Name, conf.Config.Auth.SessionTTL, proxies)
+	if n, err := userService.CountUsers(); err == nil && n == 0 {
+		log.Info().Msg("尚未创建用户，可使用 `pln user create -username <用户名> -password <密码> -role admin` 创建管理员")
+	}
 
 	// 自定义 CORS 配置
 	corsConfig := cors.DefaultConfig()
@@ -104,8 +271,9 @@ func main() {
 		"X-CSRF-Token",
 		"X-API-Key",
 		"Authorization",
+		middleware.ClientHeader,
 	}
-	corsConfig.ExposeHeaders = []string{"Content-Length", "X-API-Key"}
+	corsConfig.ExposeHeaders = []string{"Content-Length", "X-API-Key", middleware.ClientHeader}
 	corsConfig.AllowCredentials = true
 	corsConfig.MaxAge = 24 * time.Hour
 
@@ -135,6 +303,7 @@ func main() {
 			URL:          sf.URL,
 			ThumbnailURL: sf.ThumbnailURL,
 			PreviewURL:   sf.PreviewURL,
+			Rating:       conf.Config.Content.DefaultRating,
 		}
 		artwork.SetTags([]string{})
 		if err := db.Create(artwork).Error; err != nil {
@@ -157,26 +326,46 @@ func main() {
 	}
 	addr := ":" + strconv.Itoa(port)
 
+	// 公开写接口与登录限流
+	uploadLimit, reactionLimit, loginLimit, commentLimit := newRateLimits(proxies)
+
+	// 匿名客户端标识，用于点赞与收藏去重
+	clientSecret, err := conf.InitClientSecret()
+	if err != nil {
+		log.Fatal().Err(err).Msg("初始化客户端密钥失败")
+	}
+	clientID := middleware.ClientID(clientSecret)
+
+	// 存活、就绪检查与 Prometheus 指标，探测与抓取请求不计入请求日志与统计
+	middlewares := []gin.HandlerFunc{checker.Serve("/healthz", "/readyz")}
+	if mc := conf.Config.Metrics; mc.Enabled {
+		metrics.Registry.MustRegister(metrics.NewStatsCollector(conf.Config.FileServer.StoragePath, artworkRepo.CountByStatus))
+		middlewares = append(middlewares, metrics.Serve(mc.Path, mc.Token), metrics.Instrument())
+		if mc.Token == "" {
+			logger.Warn().Str("path", mc.Path).Msg("指标接口未设置 token，任何人都可以访问")
+		}
+	}
+
 	// 服务器配置
 	cfg := server.ServerConfig{
 		Addr:      addr,
 		Logger:    logger,
 		Mode:      os.Getenv("APP_MODE"),
 		APIPrefix: "/api/v1",
-		Middlewares: []gin.HandlerFunc{
+		Middlewares: append(middlewares,
 			gzero.Default(logger),
 			gzero.GinRecovery(logger),
-			// ratelimit.Default(),
 			cors.New(corsConfig),
 			requestid.RequestID(),
 			gzero.RequestIDMiddleware(),
-		},
+			middleware.RealIP(proxies),
+			middleware.Identify("X-API-Key", apiKeyService.Authenticate),
+			middleware.Session(userService.Authenticate),
+		),
 		EnableCORS: false,
 		SPAPath:    "./frontend/dist",
 	}
 
-	logger.Info().Msg("后台访问密码为: " + key)
-
 	// 启动服务器
 	err = server.Start(cfg, func(api *gin.RouterGroup) {
 
@@ -192,21 +381,132 @@ func main() {
 			public.GET("/artworks", artworkHandler.ListArtworks)
 			public.GET("/artworks/random", artworkHandler.RandomArtworks)
 			public.GET("/artworks/:id", artworkHandler.GetArtwork)
+			public.GET("/artworks/:id/tags/history", artworkHandler.GetTagHistory)
+
+			public.GET("/artworks/reactions", clientID, reactionHandler.ListReactions)
+			reactions := public.Group("/", reactionLimit, clientID)
+			reactions.POST("/artworks/:id/like", reactionHandler.Like)
+			reactions.POST("/artworks/:id/unlike", reactionHandler.Unlike)
+			reactions.POST("/artworks/:id/bookmark", reactionHandler.Bookmark)
+			reactions.POST("/artworks/:id/unbookmark", reactionHandler.Unbookmark)
+
+			// 当前客户端的收藏与收藏夹
+			public.GET("/me/bookmarks", clientID, bookmarkHandler.ListBookmarks)
+			public.GET("/me/bookmark-folders", clientID, bookmarkHandler.ListBookmarkFolders)
+			reactions.PUT("/me/bookmarks/:id", bookmarkHandler.MoveBookmark)
+			reactions.POST("/me/bookmarks/import", bookmarkHandler.ImportBookmarks)
+			reactions.POST("/me/bookmark-folders", bookmarkHandler.CreateBookmarkFolder)
+			reactions.PUT("/me/bookmark-folders/:id", bookmarkHandler.RenameBookmarkFolder)
+			reactions.DELETE("/me/bookmark-folders/:id", bookmarkHandler.DeleteBookmarkFolder)
+
+			public.GET("/artworks/:id/comments", clientID, commentHandler.ListComments)
+			public.POST("/artworks/:id/comments", commentLimit, clientID, commentHandler.CreateComment)
+			public.DELETE("/comments/:id", clientID, commentHandler.DeleteComment)
+
+			uploads := public.Group("/", uploadLimit)
+			uploads.POST("/artworks/upload", artworkHandler.UploadAndCreateArtwork)
+			uploads.POST("/artworks/upload/bulk", artworkHandler.BulkUploadArtworks)
+
+			public.GET("/tags", tagHandler.ListTags)
+			public.GET("/tags/autocomplete", tagHandler.AutocompleteTags)
+			public.GET("/tags/related", tagHandler.RelatedTags)
+			public.GET("/tags/aliases", tagHandler.ListAliases)
+			public.GET("/tags/implications", tagHandler.ListImplications)
+			public.GET("/tags/namespaces", tagHandler.ListNamespaces)
+
+			public.GET("/collections", collectionHandler.ListCollections)
+			public.GET("/collections/:id", collectionHandler.GetCollection)
+			public.GET("/collections/:id/artworks", collectionHandler.ListCollectionArtworks)
+
+			public.GET("/smart-collections", smartCollectionHandler.ListSmartCollections)
+			public.GET("/smart-collections/:name", smartCollectionHandler.GetSmartCollection)
+			public.GET("/smart-collections/:name/artworks", smartCollectionHandler.ListSmartCollectionArtworks)
+
+		}
 
-			public.POST("/artworks/:id/like", artworkHandler.IncrementLikes)
-			public.POST("/artworks/:id/unlike", artworkHandler.DecrementLikes)
-			public.POST("/artworks/:id/bookmark", artworkHandler.IncrementBookmarks)
-			public.POST("/artworks/:id/unbookmark", artworkHandler.DecrementBookmarks)
+		// 登录与当前身份
+		public.POST("/auth/login", loginLimit, authHandler.Login)
+		public.POST("/auth/logout", authHandler.Logout)
+		public.GET("/auth/oidc", oidcHandler.GetOIDCInfo)
+		if oidcService != nil {
+			public.GET("/auth/oidc/login", loginLimit, oidcHandler.OIDCLogin)
+			public.GET("/auth/oidc/callback", loginLimit, oidcHandler.OIDCCallback)
+		}
+
+		// 登录用户按角色检查，API Key 按权限范围检查
+		scopeRead := middleware.RequireScope(models.ScopeRead)
+		scopeUpload := middleware.RequireScope(models.ScopeUpload)
+		scopeTag := middleware.RequireScope(models.ScopeTag)
+		scopeDelete := middleware.RequireScope(models.ScopeDelete)
+		scopeAdmin := middleware.RequireScope(models.ScopeAdmin)
+
+		api.GET("/auth/me", middleware.RequireRole(models.RoleViewer), scopeRead, authHandler.Me)
 
-			public.POST("/artworks/upload", artworkHandler.UploadAndCreateArtwork)
+		// uploader：编辑作品与作品集
+		uploader := api.Group("/", middleware.RequireRole(models.RoleUploader))
+		{
+			uploader.PUT("/artworks/:id", scopeUpload, artworkHandler.UpdateArtwork)
+			uploader.PATCH("/artworks/:id/tags", scopeTag, artworkHandler.PatchArtworkTags)
+			uploader.POST("/artworks/:id/tags/revert", scopeTag, artworkHandler.RevertArtworkTags)
+			uploader.POST("/artworks/import-url", scopeUpload, urlImportHandler.ImportFromURL)
+
+			uploader.POST("/collections", scopeUpload, collectionHandler.CreateCollection)
+			uploader.PUT("/collections/:id", scopeUpload, collectionHandler.UpdateCollection)
+			uploader.DELETE("/collections/:id", scopeUpload, collectionHandler.DeleteCollection)
+			uploader.POST("/collections/:id/artworks", scopeUpload, collectionHandler.AddCollectionArtworks)
+			uploader.DELETE("/collections/:id/artworks", scopeUpload, collectionHandler.RemoveCollectionArtworks)
+			uploader.PUT("/collections/:id/order", scopeUpload, collectionHandler.ReorderCollection)
+
+			uploader.POST("/smart-collections", scopeUpload, smartCollectionHandler.CreateSmartCollection)
+			uploader.PUT("/smart-collections/:name", scopeUpload, smartCollectionHandler.UpdateSmartCollection)
+			uploader.DELETE("/smart-collections/:name", scopeUpload, smartCollectionHandler.DeleteSmartCollection)
+		}
 
+		// moderator：审核、删除作品与维护标签规则
+		moderator := api.Group("/", middleware.RequireRole(models.RoleModerator))
+		{
+			moderator.DELETE("/artworks/:id", scopeDelete, artworkHandler.DeleteArtwork)
+			moderator.POST("/artworks/tags/bulk", scopeTag, artworkHandler.BulkEditTags)
+			moderator.GET("/tags/changes", scopeRead, artworkHandler.ListTagChanges)
+
+			moderator.POST("/tags/aliases", scopeTag, tagHandler.CreateAlias)
+			moderator.DELETE("/tags/aliases/:id", scopeTag, tagHandler.DeleteAlias)
+			moderator.POST("/tags/implications", scopeTag, tagHandler.CreateImplication)
+			moderator.DELETE("/tags/implications/:id", scopeTag, tagHandler.DeleteImplication)
+			moderator.POST("/tags/reapply", scopeTag, tagHandler.ReapplyRules)
+
+			moderator.POST("/tags/namespaces", scopeTag, tagHandler.CreateNamespace)
+			moderator.PUT("/tags/namespaces/:id", scopeTag, tagHandler.UpdateNamespace)
+			moderator.DELETE("/tags/namespaces/:id", scopeTag, tagHandler.DeleteNamespace)
+
+			moderator.GET("/moderation/queue", scopeDelete, moderationHandler.ListModerationQueue)
+			moderator.POST("/moderation/:id/approve", scopeDelete, moderationHandler.ApproveArtwork)
+			moderator.POST("/moderation/:id/reject", scopeDelete, moderationHandler.RejectArtwork)
+			moderator.POST("/moderation/bulk", scopeDelete, moderationHandler.BulkModerate)
+
+			moderator.POST("/comments/:id/hide", scopeDelete, commentHandler.HideComment)
+			moderator.POST("/comments/:id/unhide", scopeDelete, commentHandler.UnhideComment)
 		}
 
-		// 需要认证的路由
-		auth := api.Group("/", apikey.NewBuilder().WithScheme("X-API-Key").WithValidator(conf.IsValidAPIKey).Handler())
+		// admin：用户管理、API Key、备份与导入导出
+		admin := api.Group("/", middleware.RequireRole(models.RoleAdmin))
 		{
-			auth.PUT("/artworks/:id", artworkHandler.UpdateArtwork)
-			auth.DELETE("/artworks/:id", artworkHandler.DeleteArtwork)
+			admin.GET("/artworks/export", scopeAdmin, exportHandler.ExportArtworks)
+			admin.POST("/artworks/import", scopeAdmin, exportHandler.ImportArtworks)
+
+			admin.GET("/admin/backup", scopeAdmin, backupHandler.CreateBackup)
+			admin.POST("/admin/backup", scopeAdmin, backupHandler.CreateIncrementalBackup)
+			admin.GET("/admin/audit", scopeAdmin, auditHandler.ListAuditLogs)
+
+			admin.GET("/users", scopeAdmin, userHandler.ListUsers)
+			admin.POST("/users", scopeAdmin, userHandler.CreateUser)
+			admin.PUT("/users/:id", scopeAdmin, userHandler.UpdateUser)
+			admin.DELETE("/users/:id", scopeAdmin, userHandler.DeleteUser)
+
+			admin.GET("/apikeys", scopeAdmin, apiKeyHandler.ListAPIKeys)
+			admin.POST("/apikeys", scopeAdmin, apiKeyHandler.CreateAPIKey)
+			admin.POST("/apikeys/:id/rotate", scopeAdmin, apiKeyHandler.RotateAPIKey)
+			admin.DELETE("/apikeys/:id", scopeAdmin, apiKeyHandler.RevokeAPIKey)
 		}
 	})
 
@@ -7,60 +7,37 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+// ApiKeyFile 旧版以明文保存的 API Key，启动时迁移到数据库后删除
 const ApiKeyFile = "./data/apikey.txt"
 
-var ApiKeys = make(map[string]bool)
+const ClientSecretFile = "./data/client_secret.txt"
 
-func AddAPIKey(key string) {
-	ApiKeys[key] = true
-}
-
-func IsValidAPIKey(key string) bool {
-	return ApiKeys[key]
-}
-
-func GenerateAPIKey() (string, error) {
-	bytes := make([]byte, 4) // 4字节 = 8位hex字符串
-	_, err := rand.Read(bytes)
-	if err != nil {
-		return "", err
-	}
-	return hex.EncodeToString(bytes), nil
-}
-
-func InitAPIKey() string {
-	// 读取已存在的 api key 文件
-	data, err := os.ReadFile(ApiKeyFile)
+// InitClientSecret 读取用于签名客户端标识的密钥，不存在时生成并保存。
+// 密钥变化后已签发的客户端标识全部失效
+func InitClientSecret() ([]byte, error) {
+	data, err := os.ReadFile(ClientSecretFile)
 	if err == nil {
-		key := string(data)
-		AddAPIKey(key)
-		return key
+		if secret, err := hex.DecodeString(strings.TrimSpace(string(data))); err == nil && len(secret) >= 32 {
+			return secret, nil
+		}
+		return nil, fmt.Errorf("客户端密钥文件 %s 无效", ClientSecretFile)
 	}
-
-	// 文件不存在则创建目录
-	if errors.Is(err, os.ErrNotExist) {
-		_ = os.MkdirAll(filepath.Dir(ApiKeyFile), 0755)
-	} else {
-		// 其他错误仍需要处理，但不 panic
-		fmt.Println("读取 API key 文件时出错:", err)
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("读取客户端密钥失败: %w", err)
 	}
 
-	// 生成新的 key
-	key, err := GenerateAPIKey()
-	if err != nil {
-		fmt.Println("生成 API key 失败:", err)
-		return ""
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("生成客户端密钥失败: %w", err)
 	}
-
-	// 写文件
-	err = os.WriteFile(ApiKeyFile, []byte(key), 0644)
-	if err != nil {
-		fmt.Println("写入 API key 文件失败:", err)
-		return ""
+	if err := os.MkdirAll(filepath.Dir(ClientSecretFile), 0755); err != nil {
+		return nil, err
 	}
-
-	AddAPIKey(key)
-	return key
+	if err := os.WriteFile(ClientSecretFile, []byte(hex.EncodeToString(secret)), 0600); err != nil {
+		return nil, fmt.Errorf("写入客户端密钥失败: %w", err)
+	}
+	return secret, nil
 }
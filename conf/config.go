@@ -2,6 +2,7 @@ package conf
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -12,6 +13,16 @@ type AppConfig struct {
 	FileServer      FileServerConfig `mapstructure:"file_server"`
 	ThumbnailConfig ThumbnailOption  `mapstructure:"thumbnail"`
 	PreviewConfig   ThumbnailOption  `mapstructure:"preview"`
+	Import          ImportConfig     `mapstructure:"import"`
+	Archive         ArchiveConfig    `mapstructure:"archive"`
+	Content         ContentConfig    `mapstructure:"content"`
+	Moderation      ModerationConfig `mapstructure:"moderation"`
+	RateLimit       RateLimitConfig  `mapstructure:"rate_limit"`
+	Auth            AuthConfig       `mapstructure:"auth"`
+	OIDC            OIDCConfig       `mapstructure:"oidc"`
+	Audit           AuditConfig      `mapstructure:"audit"`
+	Metrics         MetricsConfig    `mapstructure:"metrics"`
+	Health          HealthConfig     `mapstructure:"health"`
 }
 
 type DatabaseConfig struct {
@@ -47,6 +58,90 @@ type ThumbnailOption struct {
 	Quality int    `mapstructure:"quality"`
 }
 
+// ImportConfig 从网络地址导入图片的限制
+type ImportConfig struct {
+	MaxSize      int64         `mapstructure:"max_size"`      // 字节为单位
+	Timeout      time.Duration `mapstructure:"timeout"`       // 下载超时
+	AllowPrivate bool          `mapstructure:"allow_private"` // 是否允许访问内网 / 回环地址
+}
+
+// ArchiveConfig 单次批量上传的限制，文件数与总大小由请求中的全部文件与 zip 压缩包共用，
+// 压缩包超出剩余额度时整个压缩包被拒绝
+type ArchiveConfig struct {
+	MaxFiles       int   `mapstructure:"max_files"`        // 请求中的文件（包括压缩包）数上限
+	MaxRequestSize int64 `mapstructure:"max_request_size"` // 请求体大小上限，字节为单位
+	MaxEntries     int   `mapstructure:"max_entries"`      // 入库的文件数上限，压缩包按其中的文件计算
+	MaxEntrySize   int64 `mapstructure:"max_entry_size"`   // 单个文件（压缩包内按解压后）的大小上限，字节为单位
+	MaxTotalSize   int64 `mapstructure:"max_total_size"`   // 全部文件（压缩包内按解压后）的总大小上限，字节为单位
+}
+
+// ContentConfig 内容分级（safe、questionable、explicit）
+type ContentConfig struct {
+	DefaultRating   string `mapstructure:"default_rating"`    // 上传时未指定分级使用的默认值
+	PublicMaxRating string `mapstructure:"public_max_rating"` // 未认证且未主动选择时可见的最高分级
+}
+
+// ModerationConfig 上传审核
+type ModerationConfig struct {
+	Enabled bool `mapstructure:"enabled"` // 开启后未认证的上传需审核通过才公开
+}
+
+// AuditConfig 管理操作的审计记录
+type AuditConfig struct {
+	Retention       time.Duration `mapstructure:"retention"`        // 保留期限，为 0 时永久保留
+	CleanupInterval time.Duration `mapstructure:"cleanup_interval"` // 清理过期记录的间隔
+}
+
+// RateLimitConfig 公开写接口的限流，上传与点赞收藏分别计算
+type RateLimitConfig struct {
+	Enabled        bool           `mapstructure:"enabled"`
+	TrustedProxies []string       `mapstructure:"trusted_proxies"` // 可信代理的 IP 或 CIDR，只采用这些代理转发的 X-Forwarded-For
+	Uploads        RateLimitQuota `mapstructure:"uploads"`         // 单个与批量上传，批量上传按入库的文件数计算
+	Reactions      RateLimitQuota `mapstructure:"reactions"`       // 点赞、取消点赞、收藏、取消收藏
+	Login          RateLimitQuota `mapstructure:"login"`           // 登录，按 IP 计算
+	Comments       RateLimitQuota `mapstructure:"comments"`        // 发表评论
+}
+
+// RateLimitQuota 令牌桶配额，requests 或 key_requests 为 0 表示不限制
+type RateLimitQuota struct {
+	Requests    int           `mapstructure:"requests"`     // 每个 IP 在 per 时间内的请求数
+	Burst       int           `mapstructure:"burst"`        // 每个 IP 允许的突发请求数，为 0 时等于 requests
+	KeyRequests int           `mapstructure:"key_requests"` // 携带有效 API Key 时每个 Key 在 per 时间内的请求数
+	Per         time.Duration `mapstructure:"per"`
+}
+
+// MetricsConfig Prometheus 指标
+type MetricsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`  // 指标路径，不在 API 前缀下
+	Token   string `mapstructure:"token"` // 设置后抓取时需携带 Authorization: Bearer <token>
+}
+
+// HealthConfig 就绪检查
+type HealthConfig struct {
+	MinFreeDisk uint64 `mapstructure:"min_free_disk"` // 存储目录所在磁盘的最低剩余空间，字节为单位，为 0 时不检查
+}
+
+// AuthConfig 用户登录
+type AuthConfig struct {
+	SessionTTL time.Duration `mapstructure:"session_ttl"` // 会话有效期
+}
+
+// OIDCConfig OpenID Connect 单点登录
+type OIDCConfig struct {
+	Enabled       bool                `mapstructure:"enabled"`
+	Name          string              `mapstructure:"name"`           // 登录按钮上显示的名称
+	Issuer        string              `mapstructure:"issuer"`         // 通过 {issuer}/.well-known/openid-configuration 发现端点
+	ClientID      string              `mapstructure:"client_id"`      //
+	ClientSecret  string              `mapstructure:"client_secret"`  // 公开客户端留空，仅依赖 PKCE
+	RedirectURL   string              `mapstructure:"redirect_url"`   // 如 https://example.com/api/v1/auth/oidc/callback
+	Scopes        []string            `mapstructure:"scopes"`         // 授权范围，必须包含 openid
+	UsernameClaim string              `mapstructure:"username_claim"` // ID Token 中作为用户名的声明
+	GroupsClaim   string              `mapstructure:"groups_claim"`   // ID Token 中的用户组声明
+	RoleGroups    map[string][]string `mapstructure:"role_groups"`    // 角色 -> 用户组，匹配多个时取最高的角色
+	DefaultRole   string              `mapstructure:"default_role"`   // 没有匹配的用户组时的角色，留空则拒绝登录
+}
+
 var Config *AppConfig
 
 func LoadConfig(configPath string) error {
@@ -56,10 +151,51 @@ func LoadConfig(configPath string) error {
 
 	// 设置默认值
 	v.SetDefault("server.port", 9000)
+	// 端口可由环境变量覆盖，Docker 镜像的健康检查读取同一变量
+	if err := v.BindEnv("server.port", "PLN_SERVER_PORT"); err != nil {
+		return err
+	}
 	v.SetDefault("server.host", "localhost")
 	v.SetDefault("server.mode", "debug")
 	v.SetDefault("database.driver", "sqlite")
 	v.SetDefault("database.path", "./data/artwork.db")
+	v.SetDefault("import.max_size", 20<<20)
+	v.SetDefault("import.timeout", "30s")
+	v.SetDefault("import.allow_private", false)
+	v.SetDefault("archive.max_files", 50)
+	v.SetDefault("archive.max_request_size", 1<<30)
+	v.SetDefault("archive.max_entries", 500)
+	v.SetDefault("archive.max_entry_size", 64<<20)
+	v.SetDefault("archive.max_total_size", 1<<30)
+	v.SetDefault("content.default_rating", "safe")
+	v.SetDefault("content.public_max_rating", "safe")
+	v.SetDefault("moderation.enabled", false)
+	v.SetDefault("rate_limit.enabled", true)
+	v.SetDefault("rate_limit.trusted_proxies", []string{})
+	v.SetDefault("rate_limit.uploads.requests", 10)
+	v.SetDefault("rate_limit.uploads.per", "1m")
+	v.SetDefault("rate_limit.uploads.key_requests", 0)
+	v.SetDefault("rate_limit.reactions.requests", 60)
+	v.SetDefault("rate_limit.reactions.per", "1m")
+	v.SetDefault("rate_limit.reactions.key_requests", 0)
+	v.SetDefault("rate_limit.login.requests", 10)
+	v.SetDefault("rate_limit.login.per", "1m")
+	v.SetDefault("rate_limit.comments.requests", 5)
+	v.SetDefault("rate_limit.comments.per", "1m")
+	v.SetDefault("rate_limit.comments.key_requests", 0)
+	v.SetDefault("auth.session_ttl", "720h")
+	v.SetDefault("audit.retention", "2160h")
+	v.SetDefault("audit.cleanup_interval", "1h")
+	v.SetDefault("metrics.enabled", true)
+	v.SetDefault("metrics.path", "/metrics")
+	v.SetDefault("metrics.token", "")
+	v.SetDefault("health.min_free_disk", 100<<20)
+	v.SetDefault("oidc.enabled", false)
+	v.SetDefault("oidc.name", "SSO")
+	v.SetDefault("oidc.scopes", []string{"openid", "profile", "email"})
+	v.SetDefault("oidc.username_claim", "preferred_username")
+	v.SetDefault("oidc.groups_claim", "groups")
+	v.SetDefault("oidc.default_role", "")
 
 	if err := v.ReadInConfig(); err != nil {
 		return fmt.Errorf("读取配置文件失败: %w", err)
@@ -70,6 +206,17 @@ func LoadConfig(configPath string) error {
 		return fmt.Errorf("解析配置文件失败: %w", err)
 	}
 
+	for key, rating := range map[string]string{
+		"content.default_rating":    Config.Content.DefaultRating,
+		"content.public_max_rating": Config.Content.PublicMaxRating,
+	} {
+		switch rating {
+		case "safe", "questionable", "explicit":
+		default:
+			return fmt.Errorf("配置项 %s 无效: %q（可选值: safe, questionable, explicit）", key, rating)
+		}
+	}
+
 	return nil
 }
 
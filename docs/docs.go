@@ -15,17 +15,72 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/artworks": {
+        "/admin/audit": {
             "get": {
-                "description": "分页获取作品列表，支持过滤",
+                "description": "作品修改、标签修改、删除与审核，以及 API Key 与用户管理的记录，按时间倒序；diff 为字段的修改前后值",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Artwork"
+                    "Admin"
                 ],
-                "summary": "获取作品列表",
+                "summary": "审计记录",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "操作者，如 user:alice、apikey:1a2b3c4d",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "artwork.update",
+                            "artwork.tags",
+                            "artwork.delete",
+                            "artwork.approve",
+                            "artwork.reject",
+                            "apikey.create",
+                            "apikey.rotate",
+                            "apikey.revoke",
+                            "user.create",
+                            "user.update",
+                            "user.delete"
+                        ],
+                        "type": "string",
+                        "description": "操作",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "artwork_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "非作品操作的对象，如 apikey:3、user:5",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "请求ID",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "起始时间（RFC 3339，含）",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "截止时间（RFC 3339，不含）",
+                        "name": "until",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "default": 1,
@@ -55,7 +110,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/models.ArtworkResponse"
+                                                "$ref": "#/definitions/models.AuditLogResponse"
                                             }
                                         }
                                     }
@@ -66,25 +121,68 @@ const docTemplate = `{
                 }
             }
         },
-        "/artworks/random": {
+        "/admin/backup": {
             "get": {
-                "description": "随机获取指定数量的作品",
+                "description": "生成一致的数据库快照，并与存储目录一起打包为 tar 归档",
                 "produces": [
+                    "application/x-tar"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "下载完整备份",
+                "responses": {
+                    "200": {
+                        "description": "备份归档",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "以上一次备份的清单为基准，只打包哈希发生变化的文件",
+                "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/x-tar"
+                ],
                 "tags": [
-                    "Artwork"
+                    "Admin"
                 ],
-                "summary": "随机获取作品",
+                "summary": "下载增量备份",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "数量",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "上一次备份的清单",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/service.BackupManifest"
+                        }
                     }
                 ],
+                "responses": {
+                    "200": {
+                        "description": "备份归档",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/apikeys": {
+            "get": {
+                "description": "列出全部 API Key，包括已吊销的，不返回密钥明文",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "APIKey"
+                ],
+                "summary": "API Key 列表",
                 "responses": {
                     "200": {
                         "description": "获取成功",
@@ -99,7 +197,7 @@ const docTemplate = `{
                                         "data": {
                                             "type": "array",
                                             "items": {
-                                                "$ref": "#/definitions/models.ArtworkResponse"
+                                                "$ref": "#/definitions/models.APIKeyResponse"
                                             }
                                         }
                                     }
@@ -108,33 +206,33 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/artworks/upload": {
+            },
             "post": {
-                "description": "上传图片到 CDN 并同时创建艺术作品记录",
+                "description": "权限范围可选 read、upload、tag、delete、admin，密钥明文只在响应中出现一次",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Upload"
+                    "APIKey"
                 ],
-                "summary": "上传文件并创建作品",
+                "summary": "创建 API Key",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "要上传的文件",
-                        "name": "file",
-                        "in": "formData",
-                        "required": true
+                        "description": "名称、权限范围与过期时间",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.APIKeyCreateRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "创建成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -144,7 +242,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.ArtworkResponse"
+                                            "$ref": "#/definitions/models.APIKeyCreated"
                                         }
                                     }
                                 }
@@ -154,20 +252,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/artworks/{id}": {
-            "get": {
-                "description": "获取指定ID的作品详情",
+        "/apikeys/{id}": {
+            "delete": {
+                "description": "吊销后立即失效，记录保留以便追溯",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Artwork"
+                    "APIKey"
                 ],
-                "summary": "获取作品详情",
+                "summary": "吊销 API Key",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "作品ID",
+                        "description": "API Key ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -175,7 +273,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "获取成功",
+                        "description": "吊销成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -185,7 +283,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.ArtworkResponse"
+                                            "$ref": "#/definitions/models.APIKeyResponse"
                                         }
                                     }
                                 }
@@ -193,40 +291,30 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "更新指定ID的作品信息",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/apikeys/{id}/rotate": {
+            "post": {
+                "description": "生成新的密钥明文，旧明文立即失效，名称、权限范围与过期时间不变",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Artwork"
+                    "APIKey"
                 ],
-                "summary": "更新作品",
+                "summary": "轮换 API Key",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "作品ID",
+                        "description": "API Key ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "作品信息",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ArtworkUpdateRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "更新成功",
+                        "description": "轮换成功",
                         "schema": {
                             "allOf": [
                                 {
@@ -236,7 +324,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/models.ArtworkResponse"
+                                            "$ref": "#/definitions/models.APIKeyCreated"
                                         }
                                     }
                                 }
@@ -244,80 +332,186 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "删除指定ID的作品",
-                "tags": [
-                    "Artwork"
-                ],
-                "summary": "删除作品",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "作品ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    }
-                }
             }
         },
-        "/artworks/{id}/bookmark": {
-            "post": {
-                "description": "增加作品的收藏数",
+        "/artworks": {
+            "get": {
+                "description": "分页获取作品列表，支持过滤",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Artwork"
                 ],
-                "summary": "增加收藏数",
+                "summary": "获取作品列表",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "作品ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "标签过滤，支持 ns:value 与 ns:*（命名空间下任意标签）",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "作者",
+                        "name": "artist",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "授权协议",
+                        "name": "license",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "关键词：全文检索标题、描述、作者与标签，按相关度排序并返回高亮摘要；其中的 ns:value 按标签过滤",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "作品集：数字为手动作品集 ID，否则为智能作品集名称",
+                        "name": "collection",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "允许的内容分级，all 表示全部；匿名请求默认只显示 safe",
+                        "name": "rating",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "操作成功",
+                        "description": "获取成功",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ArtworkResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/artworks/{id}/like": {
-            "post": {
-                "description": "增加作品的点赞数",
+        "/artworks/export": {
+            "get": {
+                "description": "按与作品列表相同的过滤条件导出原图与 manifest.json（包含标签、计数、时间戳与哈希）",
                 "produces": [
-                    "application/json"
+                    "application/zip"
                 ],
                 "tags": [
-                    "Artwork"
+                    "Admin"
                 ],
-                "summary": "增加作品点赞数",
+                "summary": "导出作品",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "作品ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "标签过滤，支持 ns:value 与 ns:*（命名空间下任意标签）",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "作者",
+                        "name": "artist",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "授权协议",
+                        "name": "license",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "关键词，其中的 ns:value 按标签过滤",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "作品集：数字为手动作品集 ID，否则为智能作品集名称",
+                        "name": "collection",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "允许的内容分级，all 表示全部",
+                        "name": "rating",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "all",
+                            "approved",
+                            "pending"
+                        ],
+                        "type": "string",
+                        "default": "all",
+                        "description": "审核状态",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "操作成功",
+                        "description": "导出归档",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "无效的审核状态",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "作品集不存在",
                         "schema": {
                             "$ref": "#/definitions/response.Response"
                         }
@@ -325,115 +519,4896 @@ const docTemplate = `{
                 }
             }
         },
-        "/artworks/{id}/unbookmark": {
+        "/artworks/import": {
             "post": {
-                "description": "减少作品的收藏数",
+                "description": "导入由导出接口生成的归档，每个文件都经过标准的去重与相似检测，返回逐个文件的结果",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Artwork"
+                    "Admin"
                 ],
-                "summary": "取消收藏",
+                "summary": "导入作品",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "作品ID",
-                        "name": "id",
-                        "in": "path",
+                        "type": "file",
+                        "description": "导出归档（zip）",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "操作成功",
+                        "description": "导入结果",
                         "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.IngestResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/import-url": {
+            "post": {
+                "description": "下载远程图片（限制大小、超时与类型），记录来源链接并走标准的去重与变体流程",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "从网络地址导入作品",
+                "parameters": [
+                    {
+                        "description": "图片地址",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ArtworkImportURLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "导入成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ArtworkResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/random": {
+            "get": {
+                "description": "随机获取指定数量的作品",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Artwork"
+                ],
+                "summary": "随机获取作品",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "数量",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "标签过滤，支持 ns:value 与 ns:*",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "作者",
+                        "name": "artist",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "授权协议",
+                        "name": "license",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "关键词",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "只从该作品集中抽取：数字为手动作品集 ID，否则为智能作品集名称",
+                        "name": "collection",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "允许的内容分级，all 表示全部；匿名请求默认只显示 safe",
+                        "name": "rating",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ArtworkResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/reactions": {
+            "get": {
+                "description": "按 ids 的顺序返回当前客户端是否点赞、收藏了这些作品",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reaction"
+                ],
+                "summary": "当前客户端的互动状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "作品ID，逗号分隔，最多 200 个",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ReactionState"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/tags/bulk": {
+            "post": {
+                "description": "对 ids 指定的作品或 query 检索到的作品执行 add / remove / replace，全部修改在同一事务中完成",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Artwork"
+                ],
+                "summary": "批量修改作品标签",
+                "parameters": [
+                    {
+                        "description": "批量标签操作",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BulkTagRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.BulkTagResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/upload": {
+            "post": {
+                "description": "上传图片到 CDN 并同时创建艺术作品记录。开启 moderation.enabled 时匿名及 viewer 的上传处于待审核状态，审核通过前不会公开",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "上传文件并创建作品",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "要上传的文件",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "来源链接",
+                        "name": "source_url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "作者",
+                        "name": "artist",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "标题",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "描述",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "授权协议",
+                        "name": "license",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "safe",
+                            "questionable",
+                            "explicit"
+                        ],
+                        "type": "string",
+                        "description": "内容分级，默认为实例配置的 content.default_rating",
+                        "name": "rating",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ArtworkResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/upload/bulk": {
+            "post": {
+                "description": "上传多个图片文件或一个 zip 压缩包，逐个执行去重、相似检测与入库，返回每个文件的结果。请求中的文件数、入库文件数与总大小受 archive 配置限制，压缩包内的文件按解压后计算；上传限流按入库的文件数计算，超出的文件被拒绝。开启 moderation.enabled 时匿名及 viewer 的上传处于待审核状态，审核通过前不会公开",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Upload"
+                ],
+                "summary": "批量上传作品",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "file"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "图片文件或 zip 压缩包（可多个）",
+                        "name": "files",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "应用到所有作品的标签",
+                        "name": "tags",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "来源链接",
+                        "name": "source_url",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "作者",
+                        "name": "artist",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "标题",
+                        "name": "title",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "描述",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "授权协议",
+                        "name": "license",
+                        "in": "formData"
+                    },
+                    {
+                        "enum": [
+                            "safe",
+                            "questionable",
+                            "explicit"
+                        ],
+                        "type": "string",
+                        "description": "内容分级，默认为实例配置的 content.default_rating",
+                        "name": "rating",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "每个文件的处理结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.IngestResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "413": {
+                        "description": "请求体过大",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/{id}": {
+            "get": {
+                "description": "获取指定ID的作品详情，待审核的作品仅对 moderator 及以上可见",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Artwork"
+                ],
+                "summary": "获取作品详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "允许的内容分级，all 表示全部；匿名请求默认只显示 safe",
+                        "name": "rating",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ArtworkResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "更新指定ID的作品信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Artwork"
+                ],
+                "summary": "更新作品",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "作品信息",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ArtworkUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ArtworkResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "删除指定ID的作品",
+                "tags": [
+                    "Artwork"
+                ],
+                "summary": "删除作品",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/artworks/{id}/bookmark": {
+            "post": {
+                "description": "每个客户端对同一作品只计一次，重复收藏不改变计数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reaction"
+                ],
+                "summary": "收藏",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "操作成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ReactionResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/{id}/comments": {
+            "get": {
+                "description": "按发表时间排列，html 为渲染后的正文；moderator 及以上角色可以看到已隐藏的评论",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comment"
+                ],
+                "summary": "作品评论",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.CommentResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "正文支持 ` + "`" + `代码` + "`" + `、**粗体**、*斜体*、~~删除线~~ 与链接，其余 HTML 会被转义；登录用户以用户名署名，否则使用 author 或显示为匿名",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comment"
+                ],
+                "summary": "发表评论",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "评论内容",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CommentCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "发表成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CommentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/{id}/like": {
+            "post": {
+                "description": "每个客户端（pln_cid Cookie、X-Client-ID 头或 API Key）对同一作品只计一次，重复点赞不改变计数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reaction"
+                ],
+                "summary": "点赞",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "操作成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ReactionResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/{id}/tags": {
+            "patch": {
+                "description": "按 set（或 clear）→ add → remove 的顺序修改标签，结果会应用别名与蕴含规则",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Artwork"
+                ],
+                "summary": "增量修改作品标签",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "标签操作",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ArtworkTagPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ArtworkResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/{id}/tags/history": {
+            "get": {
+                "description": "按时间倒序列出作品的标签修改，包含操作者与修改前后的标签",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Artwork"
+                ],
+                "summary": "作品标签修改记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改记录",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.TagRevisionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/{id}/tags/revert": {
+            "post": {
+                "description": "将作品标签恢复到指定修改之前的状态，回滚本身会记录为一次新的修改",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Artwork"
+                ],
+                "summary": "回滚作品标签",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "要回滚的修改记录",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagRevertRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "回滚成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ArtworkResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/{id}/unbookmark": {
+            "post": {
+                "description": "只能取消当前客户端的收藏，未收藏时不改变计数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reaction"
+                ],
+                "summary": "取消收藏",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "操作成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ReactionResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/artworks/{id}/unlike": {
+            "post": {
+                "description": "只能取消当前客户端的点赞，未点赞时不改变计数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reaction"
+                ],
+                "summary": "取消点赞",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "操作成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ReactionResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "校验用户名密码，通过 pln_session Cookie 下发会话，同时在响应中返回令牌，供无法保存 Cookie 的客户端以 Authorization: Bearer 携带",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "登录",
+                "parameters": [
+                    {
+                        "description": "用户名与密码",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "登录成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "注销当前会话并清除 Cookie，未登录时同样返回成功",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "退出登录",
+                "responses": {
+                    "204": {
+                        "description": "退出成功",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/me": {
+            "get": {
+                "description": "返回当前请求的操作者与角色，通过会话登录时包含用户信息",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "当前身份",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CurrentIdentity"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc": {
+            "get": {
+                "description": "前端据此决定是否显示单点登录入口",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "单点登录配置",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.OIDCInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/callback": {
+            "get": {
+                "description": "校验 state，用授权码换取 ID Token 并校验签名与声明，按用户组映射角色后创建会话并跳转回站内",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "单点登录回调",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "授权码",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "登录成功，跳转回站内"
+                    }
+                }
+            }
+        },
+        "/auth/oidc/login": {
+            "get": {
+                "description": "生成 state、nonce 与 PKCE 参数并跳转到身份提供方，登录完成后回到 redirect 指定的站内路径",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "发起单点登录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "/",
+                        "description": "登录完成后跳转的站内路径",
+                        "name": "redirect",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "跳转到身份提供方"
+                    }
+                }
+            }
+        },
+        "/collections": {
+            "get": {
+                "description": "按最近修改时间分页列出作品集",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "作品集列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.CollectionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "创建作品集",
+                "parameters": [
+                    {
+                        "description": "作品集",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CollectionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "作品集详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品集ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CollectionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "修改标题、描述与封面，封面需为作品集中的作品，传 0 恢复默认封面",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "更新作品集",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品集ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "作品集",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CollectionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "只删除作品集本身，其中的作品不受影响",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "删除作品集",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品集ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/artworks": {
+            "get": {
+                "description": "按作品集中的顺序分页列出作品",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "作品集中的作品",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品集ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "允许的内容分级，all 表示全部；匿名请求默认只显示 safe",
+                        "name": "rating",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ArtworkResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "按顺序将作品插入到 position 处（默认追加到末尾），已在作品集中的作品会被忽略",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "批量加入作品",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品集ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "作品",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionItemsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "加入结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CollectionItemsResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "批量移除作品",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品集ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "作品",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionRemoveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "移除结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CollectionItemsResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/collections/{id}/order": {
+            "put": {
+                "description": "按新顺序给出作品集中的全部作品",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Collection"
+                ],
+                "summary": "调整作品顺序",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品集ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新顺序",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CollectionReorderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "调整成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CollectionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comments/{id}": {
+            "delete": {
+                "description": "作者本人（同一客户端）或 moderator 及以上角色可以删除",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comment"
+                ],
+                "summary": "删除评论",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "评论ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments/{id}/hide": {
+            "post": {
+                "description": "隐藏后只有 moderator 及以上角色可见，不计入作品的评论数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comment"
+                ],
+                "summary": "隐藏评论",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "评论ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "隐藏成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CommentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/comments/{id}/unhide": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Comment"
+                ],
+                "summary": "恢复评论",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "评论ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "恢复成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.CommentResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/me/bookmark-folders": {
+            "get": {
+                "description": "按名称排列当前客户端的收藏夹及其中的作品数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmark"
+                ],
+                "summary": "我的收藏夹",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.BookmarkFolderResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "同一客户端的收藏夹名称不能重复",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmark"
+                ],
+                "summary": "创建收藏夹",
+                "parameters": [
+                    {
+                        "description": "收藏夹名称",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BookmarkFolderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.BookmarkFolder"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/me/bookmark-folders/{id}": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmark"
+                ],
+                "summary": "重命名收藏夹",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "收藏夹ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新名称",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BookmarkFolderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.BookmarkFolder"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "收藏夹中的作品不会取消收藏，而是变为未分类",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmark"
+                ],
+                "summary": "删除收藏夹",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "收藏夹ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/bookmarks": {
+            "get": {
+                "description": "按收藏时间倒序列出当前客户端（登录用户、API Key 或 pln_cid Cookie）收藏的作品",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmark"
+                ],
+                "summary": "我的收藏",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "收藏夹ID，0 表示未分类，不传时列出全部",
+                        "name": "folder_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "general",
+                        "description": "分级过滤，逗号分隔，all 表示不过滤",
+                        "name": "rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.BookmarkItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/me/bookmarks/import": {
+            "post": {
+                "description": "导入前端 localStorage 中 artwork:bookmarks 的内容（以作品ID为键的对象），已收藏的作品保持不变，不存在或未公开的作品会被跳过。\n需要登录或携带已有的客户端标识；新增的收藏逐条计入点赞收藏限流，超出的记录列为 deferred，稍后重新导入即可",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmark"
+                ],
+                "summary": "导入本地收藏",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "导入到的收藏夹ID，不传时为未分类",
+                        "name": "folder_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "本地收藏记录",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/models.LocalBookmark"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "导入结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.BookmarkImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "没有已有的客户端标识",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/bookmarks/{id}": {
+            "put": {
+                "description": "将已收藏的作品移动到收藏夹，folder_id 为空时移出收藏夹",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Bookmark"
+                ],
+                "summary": "移动收藏",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "目标收藏夹",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.BookmarkMoveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "移动成功",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/bulk": {
+            "post": {
+                "description": "批量通过或驳回，不存在或不在待审核状态的作品会被跳过",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "批量审核",
+                "parameters": [
+                    {
+                        "description": "审核操作",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ModerationBulkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "审核结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ModerationResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/queue": {
+            "get": {
+                "description": "待审核的作品按上传顺序排列，已驳回的作品按审核时间倒序排列",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "审核队列",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "default": "pending",
+                        "description": "审核状态",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ModerationItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/{id}/approve": {
+            "post": {
+                "description": "通过后作品出现在公开接口中",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "审核通过",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "审核成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ModerationItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/{id}/reject": {
+            "post": {
+                "description": "驳回后删除作品并清理存储中的文件，驳回记录可在审核队列中查看",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "驳回",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "驳回原因",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ModerationRejectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "驳回成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.ModerationItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/smart-collections": {
+            "get": {
+                "description": "按名称分页列出智能作品集及当前匹配的作品数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SmartCollection"
+                ],
+                "summary": "智能作品集列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.SmartCollectionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "保存一组检索条件（tags / artist / license / q），之后可通过名称查询或作为随机接口的来源",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SmartCollection"
+                ],
+                "summary": "创建智能作品集",
+                "parameters": [
+                    {
+                        "description": "智能作品集",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SmartCollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SmartCollectionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/smart-collections/{name}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SmartCollection"
+                ],
+                "summary": "智能作品集详情",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SmartCollectionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "修改标题、描述与检索条件，名称不可修改",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SmartCollection"
+                ],
+                "summary": "更新智能作品集",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "智能作品集",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SmartCollectionUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.SmartCollectionResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SmartCollection"
+                ],
+                "summary": "删除智能作品集",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/smart-collections/{name}/artworks": {
+            "get": {
+                "description": "按保存的检索条件实时查询，与 GET /artworks?collection={name} 等价",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SmartCollection"
+                ],
+                "summary": "智能作品集中的作品",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "名称",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "允许的内容分级，all 表示全部；匿名请求默认只显示 safe",
+                        "name": "rating",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ArtworkResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "分页列出已使用的标签及其作品数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "标签目录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "count",
+                            "name"
+                        ],
+                        "type": "string",
+                        "default": "count",
+                        "description": "排序字段",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "排序方向，count 默认 desc，name 默认 asc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "只列出该命名空间下的标签",
+                        "name": "namespace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "标签前缀",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "只统计这些分级的作品，all 表示全部；匿名请求默认只统计 safe",
+                        "name": "rating",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.TagUsage"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/aliases": {
+            "get": {
+                "description": "获取全部标签别名，写入与检索时别名会被替换为规范标签",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "获取标签别名",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.TagAlias"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "创建别名 → 规范标签的映射，已有作品需调用 /tags/reapply 生效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "创建标签别名",
+                "parameters": [
+                    {
+                        "description": "别名",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagAliasRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.TagAlias"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/aliases/{id}": {
+            "delete": {
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "删除标签别名",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "别名ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/tags/autocomplete": {
+            "get": {
+                "description": "按前缀补全标签（也匹配命名空间之后的部分），包含通过别名匹配到的规范标签，按作品数排序",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "标签自动补全",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "前缀",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "数量",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "只统计这些分级的作品，all 表示全部；匿名请求默认只统计 safe",
+                        "name": "rating",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.TagSuggestion"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/changes": {
+            "get": {
+                "description": "全站标签修改记录，按时间倒序，供管理员审查",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "最近的标签修改",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "操作者",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "update",
+                            "patch",
+                            "bulk",
+                            "reapply",
+                            "revert"
+                        ],
+                        "type": "string",
+                        "description": "来源",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "作品ID",
+                        "name": "artwork_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改记录",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.TagRevisionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/implications": {
+            "get": {
+                "description": "获取全部蕴含规则，带有 tag 的作品会自动补充 implies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "获取标签蕴含规则",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.TagImplication"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "创建 tag → implies 规则，会形成循环的规则将被拒绝；已有作品需调用 /tags/reapply 生效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "创建标签蕴含规则",
+                "parameters": [
+                    {
+                        "description": "蕴含规则",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagImplicationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.TagImplication"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/implications/{id}": {
+            "delete": {
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "删除标签蕴含规则",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "规则ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/tags/namespaces": {
+            "get": {
+                "description": "获取全部命名空间及其颜色与排序，ns:value 形式的标签按命名空间分组展示",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "获取标签命名空间",
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.TagNamespace"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "创建标签命名空间",
+                "parameters": [
+                    {
+                        "description": "命名空间",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagNamespaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.TagNamespace"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/namespaces/{id}": {
+            "put": {
+                "description": "修改显示名称、颜色与排序，名称不可修改",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "更新标签命名空间",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "命名空间ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "命名空间",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagNamespaceUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.TagNamespace"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "删除后已有的 ns:value 标签保留为普通标签",
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "删除标签命名空间",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "命名空间ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/tags/reapply": {
+            "post": {
+                "description": "对全部作品重新应用别名与蕴含规则",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "重新应用标签规则",
+                "responses": {
+                    "200": {
+                        "description": "处理结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.TagReapplyResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/related": {
+            "get": {
+                "description": "统计与指定标签同时出现在作品上的标签，按共同出现次数排序",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tag"
+                ],
+                "summary": "相关标签",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "标签（别名会被替换为规范标签）",
+                        "name": "tag",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "数量",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "只统计这些分级的作品，all 表示全部；匿名请求默认只统计 safe",
+                        "name": "rating",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "count 为共同出现的作品数",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.TagUsage"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "用户列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "页码",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "每页数量",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "获取成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.User"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "用户名只能包含字母、数字与 _ . @ -，密码长度为 8 到 72 字节，角色默认为 viewer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "创建用户",
+                "parameters": [
+                    {
+                        "description": "用户信息",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "创建成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "put": {
+                "description": "修改密码、角色或禁用状态，修改密码或禁用后该用户的会话全部失效",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "更新用户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "更新内容",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/response.Response"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/models.User"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "删除用户及其全部会话",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "删除用户",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/response.Response"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.APIKeyCreateRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "expires_at": {
+                    "description": "为空时永不过期",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "read",
+                            "upload",
+                            "tag",
+                            "delete",
+                            "admin"
+                        ]
+                    }
+                }
+            }
+        },
+        "models.APIKeyCreated": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ArtworkImportURLRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "license": {
+                    "type": "string"
+                },
+                "rating": {
+                    "description": "为空时使用实例默认分级",
+                    "type": "string",
+                    "enum": [
+                        "safe",
+                        "questionable",
+                        "explicit"
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ArtworkQuery": {
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "license": {
+                    "type": "string"
+                },
+                "q": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ArtworkResponse": {
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "bookmarks": {
+                    "type": "integer"
+                },
+                "comment_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "license": {
+                    "type": "string"
+                },
+                "likes": {
+                    "type": "integer"
+                },
+                "preview_url": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "string"
+                },
+                "snippet": {
+                    "description": "关键词检索时的高亮摘要（HTML，已转义）",
+                    "type": "string"
+                },
+                "source_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tag_details": {
+                    "description": "结构化标签，命名空间标签在前",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TagDetail"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ArtworkTagPatchRequest": {
+            "type": "object",
+            "properties": {
+                "add": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "clear": {
+                    "description": "清空标签，不能与 set 同时使用",
+                    "type": "boolean"
+                },
+                "remove": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "set": {
+                    "description": "先替换为该列表，再执行 add / remove",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ArtworkUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "license": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "string",
+                    "enum": [
+                        "safe",
+                        "questionable",
+                        "explicit"
+                    ]
+                },
+                "source_url": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "nil 时不修改，空数组表示清空",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditChange": {
+            "type": "object",
+            "properties": {
+                "from": {},
+                "to": {}
+            }
+        },
+        "models.AuditLogResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "artwork_id": {
+                    "description": "非作品操作为 0",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "diff": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.AuditChange"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "target": {
+                    "description": "非作品操作的对象，如 apikey:3、user:5",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BookmarkFolder": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BookmarkFolderRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BookmarkFolderResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BookmarkImportResult": {
+            "type": "object",
+            "properties": {
+                "deferred": {
+                    "description": "超出限流未导入，稍后重新导入即可",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "existing": {
+                    "description": "已收藏，保持不变",
+                    "type": "integer"
+                },
+                "imported": {
+                    "description": "新增的收藏",
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "作品不存在或未公开",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.BookmarkItem": {
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "bookmarked_at": {
+                    "type": "string"
+                },
+                "bookmarks": {
+                    "type": "integer"
+                },
+                "comment_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "folder_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "license": {
+                    "type": "string"
+                },
+                "likes": {
+                    "type": "integer"
+                },
+                "preview_url": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "string"
+                },
+                "snippet": {
+                    "description": "关键词检索时的高亮摘要（HTML，已转义）",
+                    "type": "string"
+                },
+                "source_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tag_details": {
+                    "description": "结构化标签，命名空间标签在前",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TagDetail"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BookmarkMoveRequest": {
+            "type": "object",
+            "properties": {
+                "folder_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.BulkTagRequest": {
+            "type": "object",
+            "properties": {
+                "add": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "query": {
+                    "$ref": "#/definitions/models.ArtworkQuery"
+                },
+                "remove": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "replace": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.BulkTagResult": {
+            "type": "object",
+            "properties": {
+                "matched": {
+                    "description": "命中的作品数",
+                    "type": "integer"
+                },
+                "updated": {
+                    "description": "标签发生变化的作品数",
+                    "type": "integer"
+                },
+                "updated_ids": {
+                    "description": "标签发生变化的作品 ID",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.CollectionCover": {
+            "type": "object",
+            "properties": {
+                "artwork_id": {
+                    "type": "integer"
+                },
+                "preview_url": {
+                    "type": "string"
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CollectionItemsRequest": {
+            "type": "object",
+            "required": [
+                "artwork_ids"
+            ],
+            "properties": {
+                "artwork_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "position": {
+                    "description": "插入位置，从 0 开始；为空时追加到末尾",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CollectionItemsResult": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "description": "新加入的作品数（已存在的会被忽略）",
+                    "type": "integer"
+                },
+                "artwork_count": {
+                    "type": "integer"
+                },
+                "removed": {
+                    "description": "移除的作品数",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CollectionRemoveRequest": {
+            "type": "object",
+            "required": [
+                "artwork_ids"
+            ],
+            "properties": {
+                "artwork_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.CollectionReorderRequest": {
+            "type": "object",
+            "required": [
+                "artwork_ids"
+            ],
+            "properties": {
+                "artwork_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.CollectionRequest": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "artwork_ids": {
+                    "description": "初始作品，按顺序加入",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "cover_artwork_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CollectionResponse": {
+            "type": "object",
+            "properties": {
+                "artwork_count": {
+                    "type": "integer"
+                },
+                "cover": {
+                    "description": "实际使用的封面，作品集为空时为 null",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CollectionCover"
+                        }
+                    ]
+                },
+                "cover_artwork_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CollectionUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "cover_artwork_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CommentCreateRequest": {
+            "type": "object",
+            "required": [
+                "body"
+            ],
+            "properties": {
+                "author": {
+                    "description": "未登录时的昵称，为空时显示为匿名；登录用户固定使用用户名",
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CommentResponse": {
+            "type": "object",
+            "properties": {
+                "artwork_id": {
+                    "type": "integer"
+                },
+                "author": {
+                    "description": "显示名称，登录用户为用户名",
+                    "type": "string"
+                },
+                "body": {
+                    "description": "Markdown 原文",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hidden": {
+                    "type": "boolean"
+                },
+                "hidden_at": {
+                    "type": "string"
+                },
+                "hidden_by": {
+                    "type": "string"
+                },
+                "html": {
+                    "description": "渲染后的正文，已转义，只包含少量安全标签",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "mine": {
+                    "description": "是否为当前客户端发表，可以删除",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CurrentIdentity": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "操作者，如 user:alice、apikey:3",
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "description": "API Key 的权限范围，登录用户为空",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user": {
+                    "description": "API Key 请求为空",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.IngestResult": {
+            "type": "object",
+            "properties": {
+                "artwork_id": {
+                    "description": "新建作品的 ID",
+                    "type": "integer"
+                },
+                "existing_id": {
+                    "description": "重复或相似时已存在作品的 ID",
+                    "type": "integer"
+                },
+                "file": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "被拒绝的原因",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "created / duplicate / similar / rejected",
+                    "type": "string"
+                }
+            }
+        },
+        "models.LocalBookmark": {
+            "type": "object",
+            "properties": {
+                "artworkId": {
+                    "type": "integer"
+                },
+                "bookmarked": {
+                    "type": "boolean"
+                },
+                "timestamp": {
+                    "description": "毫秒",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "models.ModerationBulkRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "ids"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "approve",
+                        "reject"
+                    ]
+                },
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "reason": {
+                    "description": "驳回原因，仅 reject 时使用",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModerationItem": {
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string"
+                },
+                "bookmarks": {
+                    "type": "integer"
+                },
+                "comment_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "license": {
+                    "type": "string"
+                },
+                "likes": {
+                    "type": "integer"
+                },
+                "moderated_at": {
+                    "type": "string"
+                },
+                "moderated_by": {
+                    "type": "string"
+                },
+                "preview_url": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "string"
+                },
+                "reject_reason": {
+                    "type": "string"
+                },
+                "snippet": {
+                    "description": "关键词检索时的高亮摘要（HTML，已转义）",
+                    "type": "string"
+                },
+                "source_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tag_details": {
+                    "description": "结构化标签，命名空间标签在前",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TagDetail"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "thumbnail_url": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ModerationRejectRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ModerationResult": {
+            "type": "object",
+            "properties": {
+                "approved": {
+                    "description": "审核通过的作品",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "rejected": {
+                    "description": "驳回的作品",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
                     }
+                },
+                "skipped": {
+                    "description": "不存在或不在待审核状态的作品",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.OIDCInfo": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "login_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReactionResult": {
+            "type": "object",
+            "properties": {
+                "artwork_id": {
+                    "type": "integer"
+                },
+                "bookmarked": {
+                    "type": "boolean"
+                },
+                "bookmarks": {
+                    "type": "integer"
+                },
+                "changed": {
+                    "description": "为 false 表示状态本来如此，计数未变化",
+                    "type": "boolean"
+                },
+                "liked": {
+                    "type": "boolean"
+                },
+                "likes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReactionState": {
+            "type": "object",
+            "properties": {
+                "artwork_id": {
+                    "type": "integer"
+                },
+                "bookmarked": {
+                    "type": "boolean"
+                },
+                "liked": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.SmartCollectionRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "query": {
+                    "$ref": "#/definitions/models.ArtworkQuery"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SmartCollectionResponse": {
+            "type": "object",
+            "properties": {
+                "artwork_count": {
+                    "description": "当前匹配的作品数",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "用于 URL 的名称，如 wallpapers",
+                    "type": "string"
+                },
+                "query": {
+                    "$ref": "#/definitions/models.ArtworkQuery"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SmartCollectionUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "query": {
+                    "$ref": "#/definitions/models.ArtworkQuery"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagAlias": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "description": "别名，如 cats、neko",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "tag": {
+                    "description": "规范标签，如 cat",
+                    "type": "string"
                 }
             }
         },
-        "/artworks/{id}/unlike": {
-            "post": {
-                "description": "减少作品的点赞数",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Artwork"
-                ],
-                "summary": "取消点赞",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "作品ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "操作成功",
-                        "schema": {
-                            "$ref": "#/definitions/response.Response"
-                        }
-                    }
+        "models.TagAliasRequest": {
+            "type": "object",
+            "required": [
+                "alias",
+                "tag"
+            ],
+            "properties": {
+                "alias": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.ArtworkResponse": {
+        },
+        "models.TagDetail": {
             "type": "object",
             "properties": {
-                "bookmarks": {
+                "color": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "完整标签，如 artist:foo",
+                    "type": "string"
+                },
+                "namespace": {
+                    "description": "命名空间，普通标签为空",
+                    "type": "string"
+                },
+                "sort_order": {
                     "type": "integer"
                 },
+                "value": {
+                    "description": "去掉命名空间后的部分",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagImplication": {
+            "type": "object",
+            "properties": {
                 "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "likes": {
+                "implies": {
+                    "description": "如 cat",
+                    "type": "string"
+                },
+                "tag": {
+                    "description": "如 persian_cat",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagImplicationRequest": {
+            "type": "object",
+            "required": [
+                "implies",
+                "tag"
+            ],
+            "properties": {
+                "implies": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagNamespace": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "description": "显示颜色，如 #e67e22",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "name": {
+                    "description": "小写字母、数字或下划线",
+                    "type": "string"
                 },
-                "thumbnail_url": {
+                "sort_order": {
+                    "description": "越小越靠前",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "显示名称",
                     "type": "string"
                 },
                 "updated_at": {
                     "type": "string"
+                }
+            }
+        },
+        "models.TagNamespaceRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "color": {
+                    "type": "string"
                 },
-                "url": {
+                "name": {
                     "type": "string"
                 },
-                "views": {
+                "sort_order": {
                     "type": "integer"
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "models.ArtworkUpdateRequest": {
+        "models.TagNamespaceUpdateRequest": {
             "type": "object",
             "properties": {
-                "tags": {
+                "color": {
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagReapplyResult": {
+            "type": "object",
+            "properties": {
+                "scanned": {
+                    "description": "检查的作品数",
+                    "type": "integer"
+                },
+                "updated": {
+                    "description": "标签发生变化的作品数",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TagRevertRequest": {
+            "type": "object",
+            "required": [
+                "revision_id"
+            ],
+            "properties": {
+                "revision_id": {
+                    "description": "恢复到该记录修改前的标签",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TagRevisionResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "added": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     }
                 },
-                "thumbnail_url": {
+                "after": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "artwork_id": {
+                    "type": "integer"
+                },
+                "before": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "url": {
+                "id": {
+                    "type": "integer"
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "revert_of": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TagSuggestion": {
+            "type": "object",
+            "properties": {
+                "alias": {
+                    "description": "通过别名匹配时的别名",
+                    "type": "string"
+                },
+                "color": {
+                    "type": "string"
+                },
+                "count": {
+                    "description": "使用该标签的作品数",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "完整标签，如 artist:foo",
+                    "type": "string"
+                },
+                "namespace": {
+                    "description": "命名空间，普通标签为空",
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "去掉命名空间后的部分",
+                    "type": "string"
+                }
+            }
+        },
+        "models.TagUsage": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string"
+                },
+                "count": {
+                    "description": "使用该标签的作品数",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "完整标签，如 artist:foo",
+                    "type": "string"
+                },
+                "namespace": {
+                    "description": "命名空间，普通标签为空",
+                    "type": "string"
+                },
+                "sort_order": {
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "去掉命名空间后的部分",
+                    "type": "string"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "description": "禁用后无法登录，已有会话失效",
+                    "type": "boolean"
+                },
+                "external_id": {
+                    "description": "单点登录用户的 issuer 与 subject，本地用户为空",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_login_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserCreateRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "role": {
+                    "description": "为空时为 viewer",
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "uploader",
+                        "moderator",
+                        "admin"
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "type": "boolean"
+                },
+                "password": {
                     "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "viewer",
+                        "uploader",
+                        "moderator",
+                        "admin"
+                    ]
                 }
             }
         },
@@ -460,6 +5435,53 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "service.BackupFile": {
+            "type": "object",
+            "properties": {
+                "included": {
+                    "description": "增量备份中未变化的文件不会打包，仅记录哈希",
+                    "type": "boolean"
+                },
+                "path": {
+                    "description": "相对存储目录的路径（使用 / 分隔）",
+                    "type": "string"
+                },
+                "sha256": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "service.BackupManifest": {
+            "type": "object",
+            "properties": {
+                "base_created": {
+                    "description": "增量备份所基于的备份时间",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "database": {
+                    "$ref": "#/definitions/service.BackupFile"
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.BackupFile"
+                    }
+                },
+                "incremental": {
+                    "description": "是否为增量备份",
+                    "type": "boolean"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
@@ -12,6 +12,7 @@ export {}
 declare module 'vue' {
   export interface GlobalComponents {
     ArtworkCard: typeof import('./components/ArtworkCard.vue')['default']
+    CommentSection: typeof import('./components/CommentSection.vue')['default']
     HeaderArea: typeof import('./components/HeaderArea.vue')['default']
     PageNavigator: typeof import('./components/PageNavigator.vue')['default']
     RouterLink: typeof import('vue-router')['RouterLink']
@@ -11,6 +11,16 @@ import type {
   ApiError,
   UploadResponse,
   PageData,
+  ReactionResult,
+  ReactionState,
+  Comment,
+  BookmarkFolder,
+  BookmarkItem,
+  LocalBookmark,
+  BookmarkImportResult,
+  LoginResponse,
+  CurrentIdentity,
+  OIDCInfo,
 } from '@/types'
 
 // ==================== Composable ====================
@@ -174,7 +184,7 @@ export function useApi() {
     loading.value = true
     error.value = null
     try {
-      const response = await instance.post<ApiResponse<{ likes: number }>>(`/artworks/${id}/like`)
+      const response = await instance.post<ApiResponse<ReactionResult>>(`/artworks/${id}/like`)
       return response.data.data
     } catch (err) {
       throw err
@@ -190,7 +200,7 @@ export function useApi() {
     loading.value = true
     error.value = null
     try {
-      const response = await instance.post<ApiResponse<{ likes: number }>>(`/artworks/${id}/unlike`)
+      const response = await instance.post<ApiResponse<ReactionResult>>(`/artworks/${id}/unlike`)
       return response.data.data
     } catch (err) {
       throw err
@@ -206,9 +216,7 @@ export function useApi() {
     loading.value = true
     error.value = null
     try {
-      const response = await instance.post<ApiResponse<{ bookmarks: number }>>(
-        `/artworks/${id}/bookmark`,
-      )
+      const response = await instance.post<ApiResponse<ReactionResult>>(`/artworks/${id}/bookmark`)
       return response.data.data
     } catch (err) {
       throw err
@@ -224,7 +232,7 @@ export function useApi() {
     loading.value = true
     error.value = null
     try {
-      const response = await instance.post<ApiResponse<{ bookmarks: number }>>(
+      const response = await instance.post<ApiResponse<ReactionResult>>(
         `/artworks/${id}/unbookmark`,
       )
       return response.data.data
@@ -235,6 +243,173 @@ export function useApi() {
     }
   }
 
+  /**
+   * 获取当前客户端对作品的点赞与收藏状态
+   */
+  const getReactions = async (ids: number[]) => {
+    const response = await instance.get<ApiResponse<ReactionState[]>>('/artworks/reactions', {
+      params: { ids: ids.join(',') },
+    })
+    return response.data.data
+  }
+
+  // ==================== 评论相关 API ====================
+
+  /**
+   * 获取作品评论
+   */
+  const getComments = async (artworkId: number, page = 1, pageSize = 20) => {
+    const response = await instance.get<ApiResponse<PageData<Comment>>>(
+      `/artworks/${artworkId}/comments`,
+      { params: { page, page_size: pageSize } },
+    )
+    return response.data
+  }
+
+  /**
+   * 发表评论，登录后以用户名署名
+   */
+  const createComment = async (artworkId: number, body: string, author?: string) => {
+    const response = await instance.post<ApiResponse<Comment>>(`/artworks/${artworkId}/comments`, {
+      body,
+      author,
+    })
+    return response.data.data
+  }
+
+  /**
+   * 删除评论
+   */
+  const deleteComment = async (id: number) => {
+    await instance.delete(`/comments/${id}`)
+  }
+
+  /**
+   * 隐藏或恢复评论（需要 moderator 权限）
+   */
+  const setCommentHidden = async (id: number, hidden: boolean) => {
+    const response = await instance.post<ApiResponse<Comment>>(
+      `/comments/${id}/${hidden ? 'hide' : 'unhide'}`,
+    )
+    return response.data.data
+  }
+
+  // ==================== 收藏相关 API ====================
+
+  /**
+   * 获取当前客户端的收藏，folderId 为 0 时只返回未分类的收藏
+   */
+  const getBookmarks = async (page = 1, pageSize = 20, folderId?: number) => {
+    loading.value = true
+    error.value = null
+    try {
+      const response = await instance.get<ApiResponse<PageData<BookmarkItem>>>('/me/bookmarks', {
+        params: { page, page_size: pageSize, folder_id: folderId },
+      })
+      return response.data
+    } catch (err) {
+      throw err
+    } finally {
+      loading.value = false
+    }
+  }
+
+  /**
+   * 将收藏移动到收藏夹，folderId 为 null 时移出收藏夹
+   */
+  const moveBookmark = async (artworkId: number, folderId: number | null) => {
+    await instance.put(`/me/bookmarks/${artworkId}`, { folder_id: folderId })
+  }
+
+  /**
+   * 导入 localStorage 中的收藏记录
+   */
+  const importBookmarks = async (records: Record<string, LocalBookmark>, folderId?: number) => {
+    const response = await instance.post<ApiResponse<BookmarkImportResult>>(
+      '/me/bookmarks/import',
+      records,
+      { params: { folder_id: folderId } },
+    )
+    return response.data.data
+  }
+
+  /**
+   * 获取收藏夹列表
+   */
+  const getBookmarkFolders = async () => {
+    const response = await instance.get<ApiResponse<BookmarkFolder[]>>('/me/bookmark-folders')
+    return response.data.data
+  }
+
+  /**
+   * 创建收藏夹
+   */
+  const createBookmarkFolder = async (name: string) => {
+    const response = await instance.post<ApiResponse<BookmarkFolder>>('/me/bookmark-folders', {
+      name,
+    })
+    return response.data.data
+  }
+
+  /**
+   * 重命名收藏夹
+   */
+  const renameBookmarkFolder = async (id: number, name: string) => {
+    const response = await instance.put<ApiResponse<BookmarkFolder>>(
+      `/me/bookmark-folders/${id}`,
+      { name },
+    )
+    return response.data.data
+  }
+
+  /**
+   * 删除收藏夹，其中的收藏变为未分类
+   */
+  const deleteBookmarkFolder = async (id: number) => {
+    await instance.delete(`/me/bookmark-folders/${id}`)
+  }
+
+  // ==================== 登录相关 API ====================
+
+  /**
+   * 登录，会话通过 Cookie 保存
+   */
+  const login = async (username: string, password: string) => {
+    const response = await instance.post<ApiResponse<LoginResponse>>('/auth/login', {
+      username,
+      password,
+    })
+    return response.data.data
+  }
+
+  /**
+   * 退出登录
+   */
+  const logout = async () => {
+    await instance.post('/auth/logout')
+  }
+
+  /**
+   * 获取当前身份，未登录时返回 null
+   */
+  const getMe = async () => {
+    try {
+      const response = await instance.get<ApiResponse<CurrentIdentity>>('/auth/me')
+      return response.data.data
+    } catch (err) {
+      if ((err as AxiosError).response?.status === 401) return null
+      throw err
+    }
+  }
+
+  /**
+   * 获取单点登录配置
+   */
+  const getOidcInfo = async () => {
+    const response = await instance.get<ApiResponse<OIDCInfo>>('/auth/oidc')
+    return response.data.data
+  }
+
   // ==================== 文件相关 API ====================
 
   /**
@@ -359,6 +534,25 @@ export function useApi() {
     decrementBookmarks,
     incrementLikes,
     decrementLikes,
+    getReactions,
+    // 评论 API
+    getComments,
+    createComment,
+    deleteComment,
+    setCommentHidden,
+    // 收藏 API
+    getBookmarks,
+    moveBookmark,
+    importBookmarks,
+    getBookmarkFolders,
+    createBookmarkFolder,
+    renameBookmarkFolder,
+    deleteBookmarkFolder,
+    // 登录 API
+    login,
+    logout,
+    getMe,
+    getOidcInfo,
     // 文件 API
     uploadFile,
     deleteFile,
@@ -110,7 +110,7 @@ router.beforeEach((to, from, next) => {
 
   // 认证检查
   const requiresAuth = to.meta.requiresAuth as boolean
-  const isAuthenticated = !!localStorage.getItem('api_key')
+  const isAuthenticated = !!localStorage.getItem('api_key') || !!localStorage.getItem('session_user')
 
   if (requiresAuth && !isAuthenticated) {
     // 重定向到首页
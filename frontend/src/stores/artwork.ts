@@ -28,6 +28,8 @@ export const useArtworkStore = defineStore('artwork', () => {
     decrementBookmarks,
     incrementLikes,
     decrementLikes,
+    getReactions,
+    importBookmarks,
     getRandomArtworks,
     loading,
     error,
@@ -84,28 +86,22 @@ export const useArtworkStore = defineStore('artwork', () => {
    */
   const toggleLike = async (artworkId: number) => {
     try {
-      const currentStatus = isLiked(artworkId)
-
-      // 调用对应的 API
-      if (currentStatus) {
-        // 已点赞，取消点赞
-        await decrementLikes(artworkId)
-      } else {
-        // 未点赞，增加点赞
-        await incrementLikes(artworkId)
-      }
+      // 服务端按客户端去重，以返回的状态与计数为准
+      const result = isLiked(artworkId)
+        ? await decrementLikes(artworkId)
+        : await incrementLikes(artworkId)
 
       // 更新本地记录
       likeRecords.value[artworkId] = {
         artworkId,
-        liked: !currentStatus,
+        liked: result.liked,
         timestamp: Date.now(),
       }
 
       // 更新作品列表中的数据
-      updateArtworkLikeStatus(artworkId, !currentStatus)
+      updateArtworkLikes(artworkId, result.likes)
 
-      return !currentStatus
+      return result.liked
     } catch (err) {
       console.error('点赞失败:', err)
       throw err
@@ -147,31 +143,37 @@ export const useArtworkStore = defineStore('artwork', () => {
    */
   const toggleBookmark = async (artworkId: number) => {
     try {
-      const currentStatus = isBookmarked(artworkId)
-
-      // 调用对应的 API
-      if (currentStatus) {
-        // 已收藏，取消收藏
-        await decrementBookmarks(artworkId)
-      } else {
-        // 未收藏，增加收藏
-        await incrementBookmarks(artworkId)
-      }
+      const result = isBookmarked(artworkId)
+        ? await decrementBookmarks(artworkId)
+        : await incrementBookmarks(artworkId)
 
       // 更新本地记录
       bookmarkRecords.value[artworkId] = {
         artworkId,
-        bookmarked: !currentStatus,
+        bookmarked: result.bookmarked,
         timestamp: Date.now(),
       }
 
-      return !currentStatus
+      return result.bookmarked
     } catch (err) {
       console.error('收藏失败:', err)
       throw err
     }
   }
 
+  /**
+   * 将本地收藏记录导入服务端，每个浏览器只导入一次
+   */
+  const importLocalBookmarks = async () => {
+    if (localStorage.getItem('artwork:bookmarks:imported')) return null
+    const records = Object.fromEntries(
+      Object.entries(bookmarkRecords.value).filter(([, record]) => record.bookmarked),
+    )
+    const result = Object.keys(records).length > 0 ? await importBookmarks(records) : null
+    localStorage.setItem('artwork:bookmarks:imported', String(Date.now()))
+    return result
+  }
+
   /**
    * 清除收藏记录
    */
@@ -198,6 +200,8 @@ export const useArtworkStore = defineStore('artwork', () => {
       total.value = result.data.total || 0
       currentPage.value = page
       pageSize.value = pageSize_
+      // 点赞收藏状态以服务端为准，同步失败不影响列表
+      syncReactions(artworks.value.map((a) => a.id)).catch(() => {})
 
       return result
     } catch (err) {
@@ -306,23 +310,36 @@ export const useArtworkStore = defineStore('artwork', () => {
   // ==================== 辅助方法 ====================
 
   /**
-   * 更新作品的点赞状态
+   * 更新作品的点赞数
    */
-  const updateArtworkLikeStatus = (artworkId: number, liked: boolean) => {
+  const updateArtworkLikes = (artworkId: number, likes: number) => {
     const artwork = artworks.value.find((a) => a.id === artworkId)
     if (artwork) {
-      if (liked) {
-        artwork.likes = (artwork.likes || 0) + 1
-      } else {
-        artwork.likes = Math.max(0, (artwork.likes || 1) - 1)
-      }
+      artwork.likes = likes
     }
 
     if (currentArtwork.value?.id === artworkId) {
-      if (liked) {
-        currentArtwork.value.likes = (currentArtwork.value.likes || 0) + 1
-      } else {
-        currentArtwork.value.likes = Math.max(0, (currentArtwork.value.likes || 1) - 1)
+      currentArtwork.value.likes = likes
+    }
+  }
+
+  /**
+   * 从服务端同步点赞与收藏状态
+   */
+  const syncReactions = async (ids: number[]) => {
+    if (ids.length === 0) return
+    const states = await getReactions(ids)
+    const now = Date.now()
+    for (const state of states) {
+      likeRecords.value[state.artwork_id] = {
+        artworkId: state.artwork_id,
+        liked: state.liked,
+        timestamp: now,
+      }
+      bookmarkRecords.value[state.artwork_id] = {
+        artworkId: state.artwork_id,
+        bookmarked: state.bookmarked,
+        timestamp: now,
       }
     }
   }
@@ -363,6 +380,8 @@ export const useArtworkStore = defineStore('artwork', () => {
     toggleBookmark,
     clearBookmarkRecord,
     clearAllBookmarkRecords,
+    importLocalBookmarks,
+    syncReactions,
 
     // 作品列表
     fetchArtworks,
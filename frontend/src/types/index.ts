@@ -77,14 +77,144 @@ export interface Artwork {
   url: string
   thumbnail_url: string
   preview_url: string
+  source_url: string
+  artist: string
+  title: string
+  description: string
+  license: string
+  rating: Rating
+  status: ArtworkStatus
   views: number
   likes: number
   bookmarks: number
+  comment_count: number
   tags: string[]
+  tag_details: TagDetail[]
   created_at: string
   updated_at: string
 }
 
+// 内容分级
+export type Rating = 'safe' | 'questionable' | 'explicit'
+
+// 当前客户端对作品的点赞与收藏状态
+export interface ReactionState {
+  artwork_id: number
+  liked: boolean
+  bookmarked: boolean
+}
+
+// 点赞或收藏操作结果，changed 为 false 表示状态本来如此
+export interface ReactionResult extends ReactionState {
+  changed: boolean
+  likes: number
+  bookmarks: number
+}
+
+// ==================== 评论 ====================
+
+export interface Comment {
+  id: number
+  artwork_id: number
+  author: string
+  body: string
+  html: string // 服务端渲染并转义后的正文
+  hidden: boolean
+  hidden_by: string
+  hidden_at: string | null
+  mine: boolean
+  created_at: string
+  updated_at: string
+}
+
+// ==================== 收藏 ====================
+
+export interface BookmarkFolder {
+  id: number
+  name: string
+  count?: number
+  created_at: string
+  updated_at: string
+}
+
+// 收藏的作品，folder_id 为 null 表示未分类
+export interface BookmarkItem extends Artwork {
+  folder_id: number | null
+  bookmarked_at: string
+}
+
+// localStorage 中 artwork:bookmarks 的单条记录
+export interface LocalBookmark {
+  artworkId: number
+  bookmarked: boolean
+  timestamp: number
+}
+
+export interface BookmarkImportResult {
+  imported: number
+  existing: number
+  skipped: number[]
+}
+
+// ==================== 用户 ====================
+
+export type UserRole = 'viewer' | 'uploader' | 'moderator' | 'admin'
+
+export interface User {
+  id: number
+  username: string
+  role: UserRole
+  disabled: boolean
+  external_id?: string | null
+  last_login_at?: string | null
+  created_at: string
+  updated_at: string
+}
+
+// 登录结果，浏览器依靠 pln_session Cookie 保持会话
+export interface LoginResponse {
+  token: string
+  expires_at: string
+  user: User
+}
+
+// 单点登录配置
+export interface OIDCInfo {
+  enabled: boolean
+  name: string
+  login_url?: string
+}
+
+// 当前身份，API Key 请求没有 user，登录用户没有 scopes
+export interface CurrentIdentity {
+  actor: string
+  role: UserRole
+  user?: User
+  scopes?: ApiKeyScope[]
+}
+
+export type ApiKeyScope = 'read' | 'upload' | 'tag' | 'delete' | 'admin'
+
+// 审核状态，公开接口只返回 approved
+export type ArtworkStatus = 'pending' | 'approved' | 'rejected'
+
+// 结构化标签（命名空间标签在前）
+export interface TagDetail {
+  name: string
+  namespace?: string
+  value: string
+  color?: string
+  sort_order: number
+}
+
+export interface TagNamespace {
+  id: number
+  name: string
+  title: string
+  color: string
+  sort_order: number
+}
+
 export interface ArtworkCreateRequest {
   file_id: string
   url?: string
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"context"
+
+	"pln/middleware"
+	"pln/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// actorContext 返回携带操作者与请求信息的 context
+func actorContext(c *gin.Context) context.Context {
+	ctx := service.WithActor(c.Request.Context(), requestActor(c))
+	return service.WithRequest(ctx, service.RequestInfo{
+		ID: c.GetString("request_id"),
+		IP: c.GetString(middleware.ClientIPKey),
+	})
+}
+
+// requestActor 请求的操作者，由 middleware.Identify 识别
+func requestActor(c *gin.Context) string {
+	if actor := c.GetString(middleware.ActorKey); actor != "" {
+		return actor
+	}
+	return "anonymous"
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type APIKeyHandler struct {
+	service service.APIKeyService
+}
+
+func NewAPIKeyHandler(service service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+// ListAPIKeys API Key 列表
+// @Summary API Key 列表
+// @Description 列出全部 API Key，包括已吊销的，不返回密钥明文
+// @Tags APIKey
+// @Produce json
+// @Success 200 {object} response.Response{data=[]models.APIKeyResponse} "获取成功"
+// @Router /apikeys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.service.List()
+	if err != nil {
+		writeAPIKeyError(c, err, "获取 API Key 列表失败")
+		return
+	}
+
+	response.OK().WithData(keys).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// CreateAPIKey 创建 API Key
+// @Summary 创建 API Key
+// @Description 权限范围可选 read、upload、tag、delete、admin，密钥明文只在响应中出现一次
+// @Tags APIKey
+// @Accept json
+// @Produce json
+// @Param body body models.APIKeyCreateRequest true "名称、权限范围与过期时间"
+// @Success 200 {object} response.Response{data=models.APIKeyCreated} "创建成功"
+// @Router /apikeys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req models.APIKeyCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	created, err := h.service.Create(actorContext(c), &req)
+	if err != nil {
+		writeAPIKeyError(c, err, "创建 API Key 失败")
+		return
+	}
+
+	response.OK().WithData(created).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// RotateAPIKey 轮换 API Key
+// @Summary 轮换 API Key
+// @Description 生成新的密钥明文，旧明文立即失效，名称、权限范围与过期时间不变
+// @Tags APIKey
+// @Produce json
+// @Param id path int true "API Key ID"
+// @Success 200 {object} response.Response{data=models.APIKeyCreated} "轮换成功"
+// @Router /apikeys/{id}/rotate [post]
+func (h *APIKeyHandler) RotateAPIKey(c *gin.Context) {
+	id, ok := apiKeyID(c)
+	if !ok {
+		return
+	}
+
+	created, err := h.service.Rotate(actorContext(c), id)
+	if err != nil {
+		writeAPIKeyError(c, err, "轮换 API Key 失败")
+		return
+	}
+
+	response.OK().WithData(created).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// RevokeAPIKey 吊销 API Key
+// @Summary 吊销 API Key
+// @Description 吊销后立即失效，记录保留以便追溯
+// @Tags APIKey
+// @Produce json
+// @Param id path int true "API Key ID"
+// @Success 200 {object} response.Response{data=models.APIKeyResponse} "吊销成功"
+// @Router /apikeys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, ok := apiKeyID(c)
+	if !ok {
+		return
+	}
+
+	key, err := h.service.Revoke(actorContext(c), id)
+	if err != nil {
+		writeAPIKeyError(c, err, "吊销 API Key 失败")
+		return
+	}
+
+	response.OK().WithData(key).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// apiKeyID 解析路径中的 API Key ID，无效时写入 400 响应
+func apiKeyID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid api key id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeAPIKeyError 将 API Key 相关错误映射为响应
+func writeAPIKeyError(c *gin.Context, err error, msg string) {
+	requestID := c.GetString("request_id")
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.NotFound("API Key 不存在").WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrAPIKeyRevoked):
+		response.Conflict(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrInvalidAPIKey):
+		response.BadRequest(err.Error()).WithRequestID(requestID).GJSON(c)
+	default:
+		log.Error().Err(err).Msg(msg)
+		response.InternalError(msg).WithRequestID(requestID).GJSON(c)
+	}
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"pln/middleware"
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// PatchArtworkTags 增量修改作品标签
+// @Summary 增量修改作品标签
+// @Description 按 set（或 clear）→ add → remove 的顺序修改标签，结果会应用别名与蕴含规则
+// @Tags Artwork
+// @Accept json
+// @Produce json
+// @Param id path int true "作品ID"
+// @Param body body models.ArtworkTagPatchRequest true "标签操作"
+// @Success 200 {object} response.Response{data=models.ArtworkResponse} "修改成功"
+// @Router /artworks/{id}/tags [patch]
+func (h *ArtworkHandler) PatchArtworkTags(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid artwork id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	var req models.ArtworkTagPatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	artwork, err := h.service.PatchTags(actorContext(c), uint(id), &req)
+	if err != nil {
+		writeTagEditError(c, err)
+		return
+	}
+
+	response.OK().WithData(artwork).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// BulkEditTags 批量修改作品标签
+// @Summary 批量修改作品标签
+// @Description 对 ids 指定的作品或 query 检索到的作品执行 add / remove / replace，全部修改在同一事务中完成
+// @Tags Artwork
+// @Accept json
+// @Produce json
+// @Param body body models.BulkTagRequest true "批量标签操作"
+// @Success 200 {object} response.Response{data=models.BulkTagResult} "修改结果"
+// @Router /artworks/tags/bulk [post]
+func (h *ArtworkHandler) BulkEditTags(c *gin.Context) {
+	var req models.BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	result, err := h.service.BulkEditTags(actorContext(c), &req)
+	if err != nil {
+		writeTagEditError(c, err)
+		return
+	}
+
+	log.Ctx(c.Request.Context()).Info().
+		Int("matched", result.Matched).
+		Int("updated", result.Updated).
+		Msg("批量修改标签完成")
+
+	response.OK().WithData(result).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// writeTagEditError 将标签修改错误映射为响应
+func writeTagEditError(c *gin.Context, err error) {
+	requestID := c.GetString("request_id")
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.NotFound("作品或修改记录不存在").WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrArtworksNotFound):
+		response.NotFound(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrRevisionMismatch):
+		response.NotFound(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrInvalidTagEdit),
+		errors.Is(err, service.ErrTooManyArtworks):
+		response.BadRequest(err.Error()).WithRequestID(requestID).GJSON(c)
+	default:
+		log.Error().Err(err).Msg("修改标签失败")
+		response.InternalError("修改标签失败").WithRequestID(requestID).GJSON(c)
+	}
+}
+
+// GetTagHistory 作品标签修改记录
+// @Summary 作品标签修改记录
+// @Description 按时间倒序列出作品的标签修改，包含操作者与修改前后的标签
+// @Tags Artwork
+// @Produce json
+// @Param id path int true "作品ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=[]models.TagRevisionResponse} "修改记录"
+// @Router /artworks/{id}/tags/history [get]
+func (h *ArtworkHandler) GetTagHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid artwork id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	revisions, total, err := h.service.TagHistory(uint(id), middleware.HasRole(c, models.RoleModerator), page, pageSize)
+	if err != nil {
+		writeTagEditError(c, err)
+		return
+	}
+
+	response.Page(revisions, total, page, pageSize).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// RevertArtworkTags 回滚作品标签
+// @Summary 回滚作品标签
+// @Description 将作品标签恢复到指定修改之前的状态，回滚本身会记录为一次新的修改
+// @Tags Artwork
+// @Accept json
+// @Produce json
+// @Param id path int true "作品ID"
+// @Param body body models.TagRevertRequest true "要回滚的修改记录"
+// @Success 200 {object} response.Response{data=models.ArtworkResponse} "回滚成功"
+// @Router /artworks/{id}/tags/revert [post]
+func (h *ArtworkHandler) RevertArtworkTags(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid artwork id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	var req models.TagRevertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	artwork, err := h.service.RevertTags(actorContext(c), uint(id), req.RevisionID)
+	if err != nil {
+		writeTagEditError(c, err)
+		return
+	}
+
+	response.OK().WithData(artwork).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// ListTagChanges 最近的标签修改
+// @Summary 最近的标签修改
+// @Description 全站标签修改记录，按时间倒序，供管理员审查
+// @Tags Tag
+// @Produce json
+// @Param actor query string false "操作者"
+// @Param action query string false "来源" Enums(update, patch, bulk, reapply, revert)
+// @Param artwork_id query int false "作品ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=[]models.TagRevisionResponse} "修改记录"
+// @Router /tags/changes [get]
+func (h *ArtworkHandler) ListTagChanges(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	artworkID, _ := strconv.ParseUint(c.Query("artwork_id"), 10, 32)
+
+	query := models.TagRevisionQuery{
+		ArtworkID: uint(artworkID),
+		Actor:     c.Query("actor"),
+		Action:    c.Query("action"),
+	}
+
+	revisions, total, err := h.service.ListTagChanges(query, page, pageSize)
+	if err != nil {
+		log.Error().Err(err).Msg("查询标签修改记录失败")
+		response.InternalError("查询标签修改记录失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	response.Page(revisions, total, page, pageSize).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
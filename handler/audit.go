@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type AuditHandler struct {
+	service service.AuditService
+}
+
+func NewAuditHandler(service service.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// ListAuditLogs 审计记录
+// @Summary 审计记录
+// @Description 作品修改、标签修改、删除与审核，以及 API Key 与用户管理的记录，按时间倒序；diff 为字段的修改前后值
+// @Tags Admin
+// @Produce json
+// @Param actor query string false "操作者，如 user:alice、apikey:1a2b3c4d"
+// @Param action query string false "操作" Enums(artwork.update, artwork.tags, artwork.delete, artwork.approve, artwork.reject, apikey.create, apikey.rotate, apikey.revoke, user.create, user.update, user.delete)
+// @Param artwork_id query int false "作品ID"
+// @Param target query string false "非作品操作的对象，如 apikey:3、user:5"
+// @Param request_id query string false "请求ID"
+// @Param since query string false "起始时间（RFC 3339，含）"
+// @Param until query string false "截止时间（RFC 3339，不含）"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=[]models.AuditLogResponse} "获取成功"
+// @Router /admin/audit [get]
+func (h *AuditHandler) ListAuditLogs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	artworkID, _ := strconv.ParseUint(c.Query("artwork_id"), 10, 32)
+
+	query := models.AuditLogQuery{
+		ArtworkID: uint(artworkID),
+		Target:    c.Query("target"),
+		Actor:     c.Query("actor"),
+		Action:    c.Query("action"),
+		RequestID: c.Query("request_id"),
+	}
+	for param, target := range map[string]**time.Time{"since": &query.Since, "until": &query.Until} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			response.BadRequest("invalid " + param + ", expected RFC 3339").
+				WithRequestID(c.GetString("request_id")).
+				GJSON(c)
+			return
+		}
+		*target = &t
+	}
+
+	logs, total, err := h.service.List(query, page, pageSize)
+	if err != nil {
+		log.Error().Err(err).Msg("查询审计记录失败")
+		response.InternalError("查询审计记录失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	response.Page(logs, total, page, pageSize).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"net/netip"
+	"time"
+
+	"pln/middleware"
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type AuthHandler struct {
+	service    service.UserService
+	sessionTTL time.Duration
+	proxies    []netip.Prefix
+}
+
+func NewAuthHandler(service service.UserService, sessionTTL time.Duration, proxies []netip.Prefix) *AuthHandler {
+	return &AuthHandler{service: service, sessionTTL: sessionTTL, proxies: proxies}
+}
+
+// Login 登录
+// @Summary 登录
+// @Description 校验用户名密码，通过 pln_session Cookie 下发会话，同时在响应中返回令牌，供无法保存 Cookie 的客户端以 Authorization: Bearer 携带
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param body body models.LoginRequest true "用户名与密码"
+// @Success 200 {object} response.Response{data=models.LoginResponse} "登录成功"
+// @Router /auth/login [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	result, err := h.service.Login(&req, c.Request.UserAgent(), middleware.ClientIP(c, h.proxies))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCredentials) {
+			response.Unauthorized(err.Error()).
+				WithRequestID(c.GetString("request_id")).
+				GJSON(c)
+			return
+		}
+		log.Error().Err(err).Msg("登录失败")
+		response.InternalError("登录失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	setSessionCookie(c, result.Token, h.sessionTTL)
+
+	response.OK().WithData(result).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// Logout 退出登录
+// @Summary 退出登录
+// @Description 注销当前会话并清除 Cookie，未登录时同样返回成功
+// @Tags Auth
+// @Produce json
+// @Success 204 {object} response.Response "退出成功"
+// @Router /auth/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.service.Logout(middleware.SessionToken(c)); err != nil {
+		log.Error().Err(err).Msg("退出登录失败")
+		response.InternalError("退出登录失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	setSessionCookie(c, "", -1)
+
+	response.NoContent().
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// Me 当前身份
+// @Summary 当前身份
+// @Description 返回当前请求的操作者与角色，通过会话登录时包含用户信息
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} response.Response{data=models.CurrentIdentity} "获取成功"
+// @Router /auth/me [get]
+func (h *AuthHandler) Me(c *gin.Context) {
+	identity := models.CurrentIdentity{
+		Actor: requestActor(c),
+		Role:  c.GetString(middleware.RoleKey),
+	}
+	if scopes, ok := c.Get(middleware.ScopesKey); ok {
+		identity.Scopes = scopes.([]string)
+	}
+
+	if id, ok := c.Get(middleware.UserIDKey); ok {
+		user, err := h.service.GetUser(id.(uint))
+		if err != nil {
+			writeUserError(c, err, "获取当前用户失败")
+			return
+		}
+		identity.User = user
+	}
+
+	response.OK().WithData(identity).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// setSessionCookie 写入会话 Cookie，ttl 为负数时清除
+func setSessionCookie(c *gin.Context, token string, ttl time.Duration) {
+	maxAge := int(ttl.Seconds())
+	if ttl < 0 {
+		maxAge = -1
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(middleware.SessionCookie, token, maxAge, "/", "", c.Request.TLS != nil, true)
+}
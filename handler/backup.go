@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type BackupHandler struct {
+	service *service.BackupService
+}
+
+func NewBackupHandler(service *service.BackupService) *BackupHandler {
+	return &BackupHandler{service: service}
+}
+
+// CreateBackup 下载完整备份
+// @Summary 下载完整备份
+// @Description 生成一致的数据库快照，并与存储目录一起打包为 tar 归档
+// @Tags Admin
+// @Produce application/x-tar
+// @Success 200 {file} file "备份归档"
+// @Router /admin/backup [get]
+func (h *BackupHandler) CreateBackup(c *gin.Context) {
+	h.writeBackup(c, nil)
+}
+
+// CreateIncrementalBackup 下载增量备份
+// @Summary 下载增量备份
+// @Description 以上一次备份的清单为基准，只打包哈希发生变化的文件
+// @Tags Admin
+// @Accept json
+// @Produce application/x-tar
+// @Param body body service.BackupManifest true "上一次备份的清单"
+// @Success 200 {file} file "备份归档"
+// @Router /admin/backup [post]
+func (h *BackupHandler) CreateIncrementalBackup(c *gin.Context) {
+	var base service.BackupManifest
+	if err := c.ShouldBindJSON(&base); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	h.writeBackup(c, &base)
+}
+
+func (h *BackupHandler) writeBackup(c *gin.Context, base *service.BackupManifest) {
+	ctx := c.Request.Context()
+
+	filename := fmt.Sprintf("pln-backup-%s.tar", time.Now().Format("20060102150405"))
+	c.Header("Content-Type", "application/x-tar")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if _, err := h.service.Backup(ctx, c.Writer, base); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("生成备份失败")
+
+		// 归档以流的方式写出，已开始写入时只能中断连接
+		if c.Writer.Written() {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Header("Content-Disposition", "")
+		response.InternalError("生成备份失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+}
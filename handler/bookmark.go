@@ -0,0 +1,283 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"pln/middleware"
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type BookmarkHandler struct {
+	service service.BookmarkService
+}
+
+func NewBookmarkHandler(service service.BookmarkService) *BookmarkHandler {
+	return &BookmarkHandler{service: service}
+}
+
+// ListBookmarks 我的收藏
+// @Summary 我的收藏
+// @Description 按收藏时间倒序列出当前客户端（登录用户、API Key 或 pln_cid Cookie）收藏的作品
+// @Tags Bookmark
+// @Produce json
+// @Param folder_id query int false "收藏夹ID，0 表示未分类，不传时列出全部"
+// @Param rating query string false "分级过滤，逗号分隔，all 表示不过滤" default(general)
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=[]models.BookmarkItem} "获取成功"
+// @Router /me/bookmarks [get]
+func (h *BookmarkHandler) ListBookmarks(c *gin.Context) {
+	folderID, ok := folderQuery(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	items, total, err := h.service.List(c.GetString(middleware.ClientKey), folderID, requestRatings(c), page, pageSize)
+	if err != nil {
+		writeBookmarkError(c, err, "获取收藏失败")
+		return
+	}
+
+	response.Page(items, total, page, pageSize).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// MoveBookmark 移动收藏
+// @Summary 移动收藏
+// @Description 将已收藏的作品移动到收藏夹，folder_id 为空时移出收藏夹
+// @Tags Bookmark
+// @Accept json
+// @Produce json
+// @Param id path int true "作品ID"
+// @Param body body models.BookmarkMoveRequest true "目标收藏夹"
+// @Success 204 {object} response.Response "移动成功"
+// @Router /me/bookmarks/{id} [put]
+func (h *BookmarkHandler) MoveBookmark(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid artwork id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	var req models.BookmarkMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	if err := h.service.Move(c.GetString(middleware.ClientKey), uint(id), req.FolderID); err != nil {
+		writeBookmarkError(c, err, "移动收藏失败")
+		return
+	}
+
+	response.NoContent().
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// ImportBookmarks 导入本地收藏
+// @Summary 导入本地收藏
+// @Description 导入前端 localStorage 中 artwork:bookmarks 的内容（以作品ID为键的对象），已收藏的作品保持不变，不存在或未公开的作品会被跳过。
+// @Description 需要登录或携带已有的客户端标识；新增的收藏逐条计入点赞收藏限流，超出的记录列为 deferred，稍后重新导入即可
+// @Tags Bookmark
+// @Accept json
+// @Produce json
+// @Param folder_id query int false "导入到的收藏夹ID，不传时为未分类"
+// @Param body body map[string]models.LocalBookmark true "本地收藏记录"
+// @Success 200 {object} response.Response{data=models.BookmarkImportResult} "导入结果"
+// @Failure 403 {object} response.Response "没有已有的客户端标识"
+// @Router /me/bookmarks/import [post]
+func (h *BookmarkHandler) ImportBookmarks(c *gin.Context) {
+	// 每次请求都可以获得新的匿名标识，只允许已有的标识导入，避免绕过按客户端的去重
+	if c.GetBool(middleware.ClientIssuedKey) {
+		response.Forbidden("导入收藏需要登录或已有的客户端标识").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	folderID, ok := folderQuery(c)
+	if !ok {
+		return
+	}
+
+	var records map[string]models.LocalBookmark
+	if err := c.ShouldBindJSON(&records); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	result, err := h.service.Import(c.GetString(middleware.ClientKey), records, folderID, chargePerItem(c))
+	if err != nil {
+		writeBookmarkError(c, err, "导入收藏失败")
+		return
+	}
+
+	response.OK().WithData(result).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// ListBookmarkFolders 我的收藏夹
+// @Summary 我的收藏夹
+// @Description 按名称排列当前客户端的收藏夹及其中的作品数
+// @Tags Bookmark
+// @Produce json
+// @Success 200 {object} response.Response{data=[]models.BookmarkFolderResponse} "获取成功"
+// @Router /me/bookmark-folders [get]
+func (h *BookmarkHandler) ListBookmarkFolders(c *gin.Context) {
+	folders, err := h.service.ListFolders(c.GetString(middleware.ClientKey))
+	if err != nil {
+		writeBookmarkError(c, err, "获取收藏夹失败")
+		return
+	}
+
+	response.OK().WithData(folders).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// CreateBookmarkFolder 创建收藏夹
+// @Summary 创建收藏夹
+// @Description 同一客户端的收藏夹名称不能重复
+// @Tags Bookmark
+// @Accept json
+// @Produce json
+// @Param body body models.BookmarkFolderRequest true "收藏夹名称"
+// @Success 200 {object} response.Response{data=models.BookmarkFolder} "创建成功"
+// @Router /me/bookmark-folders [post]
+func (h *BookmarkHandler) CreateBookmarkFolder(c *gin.Context) {
+	var req models.BookmarkFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	folder, err := h.service.CreateFolder(c.GetString(middleware.ClientKey), &req)
+	if err != nil {
+		writeBookmarkError(c, err, "创建收藏夹失败")
+		return
+	}
+
+	response.OK().WithData(folder).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// RenameBookmarkFolder 重命名收藏夹
+// @Summary 重命名收藏夹
+// @Tags Bookmark
+// @Accept json
+// @Produce json
+// @Param id path int true "收藏夹ID"
+// @Param body body models.BookmarkFolderRequest true "新名称"
+// @Success 200 {object} response.Response{data=models.BookmarkFolder} "更新成功"
+// @Router /me/bookmark-folders/{id} [put]
+func (h *BookmarkHandler) RenameBookmarkFolder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid folder id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	var req models.BookmarkFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	folder, err := h.service.RenameFolder(c.GetString(middleware.ClientKey), uint(id), &req)
+	if err != nil {
+		writeBookmarkError(c, err, "重命名收藏夹失败")
+		return
+	}
+
+	response.OK().WithData(folder).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// DeleteBookmarkFolder 删除收藏夹
+// @Summary 删除收藏夹
+// @Description 收藏夹中的作品不会取消收藏，而是变为未分类
+// @Tags Bookmark
+// @Produce json
+// @Param id path int true "收藏夹ID"
+// @Success 204 {object} response.Response "删除成功"
+// @Router /me/bookmark-folders/{id} [delete]
+func (h *BookmarkHandler) DeleteBookmarkFolder(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid folder id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	if err := h.service.DeleteFolder(c.GetString(middleware.ClientKey), uint(id)); err != nil {
+		writeBookmarkError(c, err, "删除收藏夹失败")
+		return
+	}
+
+	response.NoContent().
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// folderQuery 解析 folder_id 查询参数，未传时返回 nil；解析失败时已写入响应
+func folderQuery(c *gin.Context) (*uint, bool) {
+	value, ok := c.GetQuery("folder_id")
+	if !ok || value == "" {
+		return nil, true
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		response.BadRequest("invalid folder id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return nil, false
+	}
+	folderID := uint(id)
+	return &folderID, true
+}
+
+// writeBookmarkError 将收藏错误映射为响应
+func writeBookmarkError(c *gin.Context, err error, msg string) {
+	requestID := c.GetString("request_id")
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.NotFound("收藏夹不存在").WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrBookmarkNotFound):
+		response.NotFound(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrBookmarkFolderExists):
+		response.Conflict(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrInvalidBookmark), errors.Is(err, service.ErrNoClient):
+		response.BadRequest(err.Error()).WithRequestID(requestID).GJSON(c)
+	default:
+		log.Error().Err(err).Msg(msg)
+		response.InternalError(msg).WithRequestID(requestID).GJSON(c)
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"pln/conf"
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// BulkUploadArtworks 批量上传作品
+// @Summary 批量上传作品
+// @Description 上传多个图片文件或一个 zip 压缩包，逐个执行去重、相似检测与入库，返回每个文件的结果。请求中的文件数、入库文件数与总大小受 archive 配置限制，压缩包内的文件按解压后计算；上传限流按入库的文件数计算，超出的文件被拒绝。开启 moderation.enabled 时匿名及 viewer 的上传处于待审核状态，审核通过前不会公开
+// @Tags Upload
+// @Accept multipart/form-data
+// @Produce json
+// @Param files formData []file true "图片文件或 zip 压缩包（可多个）" collectionFormat(multi)
+// @Param tags formData []string false "应用到所有作品的标签" collectionFormat(multi)
+// @Param source_url formData string false "来源链接"
+// @Param artist formData string false "作者"
+// @Param title formData string false "标题"
+// @Param description formData string false "描述"
+// @Param license formData string false "授权协议"
+// @Param rating formData string false "内容分级，默认为实例配置的 content.default_rating" Enums(safe, questionable, explicit)
+// @Success 200 {object} response.Response{data=[]models.IngestResult} "每个文件的处理结果"
+// @Failure 413 {object} response.Response "请求体过大"
+// @Router /artworks/upload/bulk [post]
+func (h *ArtworkHandler) BulkUploadArtworks(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID := c.GetString("request_id")
+	logger := log.Ctx(ctx).With().Str("component", "ArtworkHandler").Logger()
+
+	limits := conf.Config.Archive
+	if limits.MaxRequestSize > 0 {
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limits.MaxRequestSize)
+	}
+
+	form, err := c.MultipartForm()
+	if maxErr := (*http.MaxBytesError)(nil); errors.As(err, &maxErr) {
+		response.Custom(41300, fmt.Sprintf("请求体超过上限 %d 字节", maxErr.Limit), http.StatusRequestEntityTooLarge).
+			WithRequestID(requestID).
+			GJSON(c)
+		return
+	}
+	if err != nil || len(form.File["files"]) == 0 {
+		response.BadRequest("没有找到文件").
+			WithRequestID(requestID).
+			GJSON(c)
+		return
+	}
+	if limits.MaxFiles > 0 && len(form.File["files"]) > limits.MaxFiles {
+		response.BadRequest(fmt.Sprintf("单次最多上传 %d 个文件", limits.MaxFiles)).
+			WithRequestID(requestID).
+			GJSON(c)
+		return
+	}
+
+	// 标签与署名信息应用到本次上传的所有文件
+	meta := formAttribution(form.Value)
+	meta.Tags = formTags(form.Value)
+	meta.Status = uploadStatus(c)
+	if meta.Rating != "" && !models.ValidRating(meta.Rating) {
+		response.BadRequest(service.ErrInvalidRating.Error()).
+			WithRequestID(requestID).
+			GJSON(c)
+		return
+	}
+
+	// 请求中的全部文件共用文件数与大小的额度，并逐个计入上传限流
+	budget := h.ingest.NewUploadBudget(chargePerItem(c))
+	results := []models.IngestResult{}
+	for _, file := range form.File["files"] {
+		if err := ctx.Err(); err != nil {
+			break
+		}
+
+		if strings.ToLower(filepath.Ext(file.Filename)) == ".zip" {
+			src, err := file.Open()
+			if err != nil {
+				results = append(results, service.NewIngestResult(file.Filename, nil, err))
+				continue
+			}
+
+			archiveResults, err := h.ingest.IngestArchive(ctx, src, file.Size, meta, budget)
+			src.Close()
+			if err != nil {
+				results = append(results, service.NewIngestResult(file.Filename, nil, err))
+			}
+			results = append(results, archiveResults...)
+			continue
+		}
+
+		src, err := file.Open()
+		if err != nil {
+			results = append(results, service.NewIngestResult(file.Filename, nil, err))
+			continue
+		}
+
+		req := meta
+		req.Filename = file.Filename
+		artwork, err := h.ingest.IngestFile(ctx, src, file.Size, req, budget)
+		src.Close()
+		results = append(results, service.NewIngestResult(file.Filename, artwork, err))
+	}
+
+	logger.Info().Int("count", len(results)).Msg("批量上传完成")
+
+	response.OK().WithData(results).
+		WithRequestID(requestID).
+		GJSON(c)
+}
+
+// formTags 读取表单中的标签，兼容 tags 与 tags[] 两种字段名
+func formTags(values map[string][]string) []string {
+	tags := []string{}
+	for _, key := range []string{"tags", "tags[]"} {
+		for _, tag := range values[key] {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+// formAttribution 读取表单中的来源与署名字段
+func formAttribution(values map[string][]string) service.IngestRequest {
+	get := func(key string) string {
+		if v := values[key]; len(v) > 0 {
+			return strings.TrimSpace(v[0])
+		}
+		return ""
+	}
+
+	return service.IngestRequest{
+		SourceURL:   get("source_url"),
+		Artist:      get("artist"),
+		Title:       get("title"),
+		Description: get("description"),
+		License:     get("license"),
+		Rating:      get("rating"),
+	}
+}
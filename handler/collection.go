@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type CollectionHandler struct {
+	service service.CollectionService
+}
+
+func NewCollectionHandler(service service.CollectionService) *CollectionHandler {
+	return &CollectionHandler{service: service}
+}
+
+// ListCollections 作品集列表
+// @Summary 作品集列表
+// @Description 按最近修改时间分页列出作品集
+// @Tags Collection
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=[]models.CollectionResponse} "获取成功"
+// @Router /collections [get]
+func (h *CollectionHandler) ListCollections(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	collections, total, err := h.service.ListCollections(page, pageSize, requestRatings(c))
+	if err != nil {
+		log.Error().Err(err).Msg("获取作品集列表失败")
+		response.InternalError("获取作品集列表失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	response.Page(collections, total, page, pageSize).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// GetCollection 作品集详情
+// @Summary 作品集详情
+// @Tags Collection
+// @Produce json
+// @Param id path int true "作品集ID"
+// @Success 200 {object} response.Response{data=models.CollectionResponse} "获取成功"
+// @Router /collections/{id} [get]
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	collection, err := h.service.GetCollection(id, requestRatings(c))
+	if err != nil {
+		writeCollectionError(c, err, "获取作品集失败")
+		return
+	}
+
+	response.OK().WithData(collection).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// ListCollectionArtworks 作品集中的作品
+// @Summary 作品集中的作品
+// @Description 按作品集中的顺序分页列出作品
+// @Tags Collection
+// @Produce json
+// @Param id path int true "作品集ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param rating query []string false "允许的内容分级，all 表示全部；匿名请求默认只显示 safe" collectionFormat(csv)
+// @Success 200 {object} response.Response{data=[]models.ArtworkResponse} "获取成功"
+// @Router /collections/{id}/artworks [get]
+func (h *CollectionHandler) ListCollectionArtworks(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	artworks, total, err := h.service.ListArtworks(id, page, pageSize, requestRatings(c))
+	if err != nil {
+		writeCollectionError(c, err, "获取作品集作品失败")
+		return
+	}
+
+	response.Page(artworks, total, page, pageSize).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// CreateCollection 创建作品集
+// @Summary 创建作品集
+// @Tags Collection
+// @Accept json
+// @Produce json
+// @Param body body models.CollectionRequest true "作品集"
+// @Success 200 {object} response.Response{data=models.CollectionResponse} "创建成功"
+// @Router /collections [post]
+func (h *CollectionHandler) CreateCollection(c *gin.Context) {
+	var req models.CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	collection, err := h.service.CreateCollection(&req)
+	if err != nil {
+		writeCollectionError(c, err, "创建作品集失败")
+		return
+	}
+
+	response.OK().WithData(collection).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// UpdateCollection 更新作品集
+// @Summary 更新作品集
+// @Description 修改标题、描述与封面，封面需为作品集中的作品，传 0 恢复默认封面
+// @Tags Collection
+// @Accept json
+// @Produce json
+// @Param id path int true "作品集ID"
+// @Param body body models.CollectionUpdateRequest true "作品集"
+// @Success 200 {object} response.Response{data=models.CollectionResponse} "更新成功"
+// @Router /collections/{id} [put]
+func (h *CollectionHandler) UpdateCollection(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CollectionUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	collection, err := h.service.UpdateCollection(id, &req)
+	if err != nil {
+		writeCollectionError(c, err, "更新作品集失败")
+		return
+	}
+
+	response.OK().WithData(collection).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// DeleteCollection 删除作品集
+// @Summary 删除作品集
+// @Description 只删除作品集本身，其中的作品不受影响
+// @Tags Collection
+// @Produce json
+// @Param id path int true "作品集ID"
+// @Success 204 {object} response.Response "删除成功"
+// @Router /collections/{id} [delete]
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteCollection(id); err != nil {
+		writeCollectionError(c, err, "删除作品集失败")
+		return
+	}
+
+	response.NoContent().
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// AddCollectionArtworks 批量加入作品
+// @Summary 批量加入作品
+// @Description 按顺序将作品插入到 position 处（默认追加到末尾），已在作品集中的作品会被忽略
+// @Tags Collection
+// @Accept json
+// @Produce json
+// @Param id path int true "作品集ID"
+// @Param body body models.CollectionItemsRequest true "作品"
+// @Success 200 {object} response.Response{data=models.CollectionItemsResult} "加入结果"
+// @Router /collections/{id}/artworks [post]
+func (h *CollectionHandler) AddCollectionArtworks(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CollectionItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	result, err := h.service.AddArtworks(id, &req)
+	if err != nil {
+		writeCollectionError(c, err, "加入作品失败")
+		return
+	}
+
+	response.OK().WithData(result).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// RemoveCollectionArtworks 批量移除作品
+// @Summary 批量移除作品
+// @Tags Collection
+// @Accept json
+// @Produce json
+// @Param id path int true "作品集ID"
+// @Param body body models.CollectionRemoveRequest true "作品"
+// @Success 200 {object} response.Response{data=models.CollectionItemsResult} "移除结果"
+// @Router /collections/{id}/artworks [delete]
+func (h *CollectionHandler) RemoveCollectionArtworks(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CollectionRemoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	result, err := h.service.RemoveArtworks(id, req.ArtworkIDs)
+	if err != nil {
+		writeCollectionError(c, err, "移除作品失败")
+		return
+	}
+
+	response.OK().WithData(result).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// ReorderCollection 调整作品顺序
+// @Summary 调整作品顺序
+// @Description 按新顺序给出作品集中的全部作品
+// @Tags Collection
+// @Accept json
+// @Produce json
+// @Param id path int true "作品集ID"
+// @Param body body models.CollectionReorderRequest true "新顺序"
+// @Success 200 {object} response.Response{data=models.CollectionResponse} "调整成功"
+// @Router /collections/{id}/order [put]
+func (h *CollectionHandler) ReorderCollection(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	var req models.CollectionReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	collection, err := h.service.Reorder(id, req.ArtworkIDs)
+	if err != nil {
+		writeCollectionError(c, err, "调整作品顺序失败")
+		return
+	}
+
+	response.OK().WithData(collection).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// collectionID 解析路径中的作品集 ID，失败时写入错误响应
+func collectionID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid collection id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeCollectionError 将作品集错误映射为响应
+func writeCollectionError(c *gin.Context, err error, msg string) {
+	requestID := c.GetString("request_id")
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.NotFound("作品集不存在").WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrArtworksNotFound):
+		response.NotFound(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrInvalidCollection),
+		errors.Is(err, service.ErrCoverNotInItems):
+		response.BadRequest(err.Error()).WithRequestID(requestID).GJSON(c)
+	default:
+		log.Error().Err(err).Msg(msg)
+		response.InternalError(msg).WithRequestID(requestID).GJSON(c)
+	}
+}
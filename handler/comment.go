@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"errors"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"pln/middleware"
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type CommentHandler struct {
+	service service.CommentService
+	proxies []netip.Prefix
+}
+
+func NewCommentHandler(service service.CommentService, proxies []netip.Prefix) *CommentHandler {
+	return &CommentHandler{service: service, proxies: proxies}
+}
+
+// ListComments 作品评论
+// @Summary 作品评论
+// @Description 按发表时间排列，html 为渲染后的正文；moderator 及以上角色可以看到已隐藏的评论
+// @Tags Comment
+// @Produce json
+// @Param id path int true "作品ID"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=[]models.CommentResponse} "获取成功"
+// @Router /artworks/{id}/comments [get]
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid artwork id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	comments, total, err := h.service.List(uint(id), c.GetString(middleware.ClientKey),
+		middleware.HasRole(c, models.RoleModerator), page, pageSize)
+	if err != nil {
+		writeCommentError(c, err, "获取评论失败")
+		return
+	}
+
+	response.Page(comments, total, page, pageSize).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// CreateComment 发表评论
+// @Summary 发表评论
+// @Description 正文支持 `代码`、**粗体**、*斜体*、~~删除线~~ 与链接，其余 HTML 会被转义；登录用户以用户名署名，否则使用 author 或显示为匿名
+// @Tags Comment
+// @Accept json
+// @Produce json
+// @Param id path int true "作品ID"
+// @Param body body models.CommentCreateRequest true "评论内容"
+// @Success 200 {object} response.Response{data=models.CommentResponse} "发表成功"
+// @Router /artworks/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid artwork id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	var req models.CommentCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	author := req.Author
+	if actor := c.GetString(middleware.ActorKey); actor != "" {
+		author = strings.TrimPrefix(actor, "user:")
+	}
+
+	comment, err := h.service.Create(uint(id), c.GetString(middleware.ClientKey), author,
+		middleware.ClientIP(c, h.proxies), &req)
+	if err != nil {
+		writeCommentError(c, err, "发表评论失败")
+		return
+	}
+
+	response.OK().WithData(comment).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// DeleteComment 删除评论
+// @Summary 删除评论
+// @Description 作者本人（同一客户端）或 moderator 及以上角色可以删除
+// @Tags Comment
+// @Produce json
+// @Param id path int true "评论ID"
+// @Success 204 {object} response.Response "删除成功"
+// @Router /comments/{id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid comment id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	err = h.service.Delete(uint(id), c.GetString(middleware.ClientKey), middleware.HasRole(c, models.RoleModerator))
+	if err != nil {
+		writeCommentError(c, err, "删除评论失败")
+		return
+	}
+
+	response.NoContent().
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// HideComment 隐藏评论
+// @Summary 隐藏评论
+// @Description 隐藏后只有 moderator 及以上角色可见，不计入作品的评论数
+// @Tags Comment
+// @Produce json
+// @Param id path int true "评论ID"
+// @Success 200 {object} response.Response{data=models.CommentResponse} "隐藏成功"
+// @Router /comments/{id}/hide [post]
+func (h *CommentHandler) HideComment(c *gin.Context) {
+	h.setHidden(c, true, "隐藏评论失败")
+}
+
+// UnhideComment 恢复评论
+// @Summary 恢复评论
+// @Tags Comment
+// @Produce json
+// @Param id path int true "评论ID"
+// @Success 200 {object} response.Response{data=models.CommentResponse} "恢复成功"
+// @Router /comments/{id}/unhide [post]
+func (h *CommentHandler) UnhideComment(c *gin.Context) {
+	h.setHidden(c, false, "恢复评论失败")
+}
+
+func (h *CommentHandler) setHidden(c *gin.Context, hidden bool, msg string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid comment id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	comment, err := h.service.SetHidden(actorContext(c), uint(id), hidden)
+	if err != nil {
+		writeCommentError(c, err, msg)
+		return
+	}
+
+	response.OK().WithData(comment).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// writeCommentError 将评论错误映射为响应
+func writeCommentError(c *gin.Context, err error, msg string) {
+	requestID := c.GetString("request_id")
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.NotFound("评论或作品不存在").WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrCommentForbidden):
+		response.Forbidden(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrInvalidComment), errors.Is(err, service.ErrNoClient):
+		response.BadRequest(err.Error()).WithRequestID(requestID).GJSON(c)
+	default:
+		log.Error().Err(err).Msg(msg)
+		response.InternalError(msg).WithRequestID(requestID).GJSON(c)
+	}
+}
@@ -37,7 +37,7 @@ func (h *ArtworkHandler) DeleteArtwork(c *gin.Context) {
 	}
 
 	//  删除数据库记录
-	if err := h.service.DeleteArtwork(uint(id)); err != nil {
+	if err := h.service.DeleteArtwork(actorContext(c), uint(id)); err != nil {
 		log.Error().Err(err).Msg("删除作品失败")
 		response.InternalError("删除作品失败").
 			WithRequestID(c.GetString("request_id")).
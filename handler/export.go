@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type ExportHandler struct {
+	service *service.ExportService
+}
+
+func NewExportHandler(service *service.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// ExportArtworks 导出作品
+// @Summary 导出作品
+// @Description 按与作品列表相同的过滤条件导出原图与 manifest.json（包含标签、计数、时间戳与哈希）
+// @Tags Admin
+// @Produce application/zip
+// @Param tags query []string false "标签过滤，支持 ns:value 与 ns:*（命名空间下任意标签）" collectionFormat(multi)
+// @Param artist query string false "作者"
+// @Param license query string false "授权协议"
+// @Param q query string false "关键词，其中的 ns:value 按标签过滤"
+// @Param collection query string false "作品集：数字为手动作品集 ID，否则为智能作品集名称"
+// @Param rating query []string false "允许的内容分级，all 表示全部" collectionFormat(csv)
+// @Param status query string false "审核状态" Enums(all, approved, pending) default(all)
+// @Failure 400 {object} response.Response "无效的审核状态"
+// @Success 200 {file} file "导出归档"
+// @Failure 404 {object} response.Response "作品集不存在"
+// @Router /artworks/export [get]
+func (h *ExportHandler) ExportArtworks(c *gin.Context) {
+	ctx := c.Request.Context()
+	filters := parseArtworkFilters(c)
+
+	switch status := c.DefaultQuery("status", models.StatusAll); status {
+	case models.StatusAll, models.StatusApproved, models.StatusPending:
+		filters["status"] = status
+	default:
+		response.BadRequest("无效的审核状态，可选值: all, approved, pending").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	filename := fmt.Sprintf("pln-export-%s.zip", time.Now().Format("20060102150405"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if _, err := h.service.Export(ctx, c.Writer, filters); err != nil {
+		// 归档以流的方式写出，已开始写入时只能中断连接
+		if c.Writer.Written() {
+			log.Ctx(ctx).Error().Err(err).Msg("导出作品失败")
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Header("Content-Type", "")
+		c.Header("Content-Disposition", "")
+		if errors.Is(err, service.ErrCollectionNotFound) {
+			response.NotFound(err.Error()).
+				WithRequestID(c.GetString("request_id")).
+				GJSON(c)
+			return
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("导出作品失败")
+		response.InternalError("导出作品失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+	}
+}
+
+// ImportArtworks 导入作品
+// @Summary 导入作品
+// @Description 导入由导出接口生成的归档，每个文件都经过标准的去重与相似检测，返回逐个文件的结果
+// @Tags Admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "导出归档（zip）"
+// @Success 200 {object} response.Response{data=[]models.IngestResult} "导入结果"
+// @Router /artworks/import [post]
+func (h *ExportHandler) ImportArtworks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.BadRequest("没有找到文件").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("打开上传文件失败")
+		response.InternalError("导入失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+	defer src.Close()
+
+	results, err := h.service.Import(ctx, src, file.Size)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("导入作品失败")
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	response.OK().WithData(results).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
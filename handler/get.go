@@ -1,8 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
 
+	"pln/middleware"
+	"pln/models"
+	"pln/service"
+
 	"github.com/Yuelioi/gkit/web/response"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
@@ -10,10 +15,11 @@ import (
 
 // GetArtwork 获取单个作品
 // @Summary 获取作品详情
-// @Description 获取指定ID的作品详情
+// @Description 获取指定ID的作品详情，待审核的作品仅对 moderator 及以上可见
 // @Tags Artwork
 // @Produce json
 // @Param id path int true "作品ID"
+// @Param rating query []string false "允许的内容分级，all 表示全部；匿名请求默认只显示 safe" collectionFormat(csv)
 // @Success 200 {object} response.Response{data=models.ArtworkResponse} "获取成功"
 // @Router /artworks/{id} [get]
 func (h *ArtworkHandler) GetArtwork(c *gin.Context) {
@@ -25,7 +31,7 @@ func (h *ArtworkHandler) GetArtwork(c *gin.Context) {
 		return
 	}
 
-	artwork, err := h.service.GetArtwork(uint(id))
+	artwork, err := h.service.GetArtwork(uint(id), requestRatings(c), !middleware.HasRole(c, models.RoleModerator))
 	if err != nil {
 		response.NotFound("artwork not found").
 			WithRequestID(c.GetString("request_id")).
@@ -44,6 +50,12 @@ func (h *ArtworkHandler) GetArtwork(c *gin.Context) {
 // @Tags Artwork
 // @Produce json
 // @Param limit query int false "数量" default(10)
+// @Param tags query []string false "标签过滤，支持 ns:value 与 ns:*" collectionFormat(multi)
+// @Param artist query string false "作者"
+// @Param license query string false "授权协议"
+// @Param q query string false "关键词"
+// @Param collection query string false "只从该作品集中抽取：数字为手动作品集 ID，否则为智能作品集名称"
+// @Param rating query []string false "允许的内容分级，all 表示全部；匿名请求默认只显示 safe" collectionFormat(csv)
 // @Success 200 {object} response.Response{data=[]models.ArtworkResponse} "获取成功"
 // @Router /artworks/random [get]
 func (h *ArtworkHandler) RandomArtworks(c *gin.Context) {
@@ -54,12 +66,15 @@ func (h *ArtworkHandler) RandomArtworks(c *gin.Context) {
 		limit = 10
 	}
 
-	filters := make(map[string]any)
-	if tags := c.QueryArray("tags"); len(tags) > 0 {
-		filters["tags"] = tags
-	}
+	filters := parseArtworkFilters(c)
 
 	artworks, err := h.service.GetRandomArtworks(limit, filters)
+	if errors.Is(err, service.ErrCollectionNotFound) {
+		response.NotFound(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("获取随机作品失败")
 		response.InternalError("获取随机作品失败").
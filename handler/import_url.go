@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+type URLImportHandler struct {
+	service *service.URLImportService
+}
+
+func NewURLImportHandler(service *service.URLImportService) *URLImportHandler {
+	return &URLImportHandler{service: service}
+}
+
+// ImportFromURL 从网络地址导入作品
+// @Summary 从网络地址导入作品
+// @Description 下载远程图片（限制大小、超时与类型），记录来源链接并走标准的去重与变体流程
+// @Tags Upload
+// @Accept json
+// @Produce json
+// @Param body body models.ArtworkImportURLRequest true "图片地址"
+// @Success 200 {object} response.Response{data=models.ArtworkResponse} "导入成功"
+// @Router /artworks/import-url [post]
+func (h *URLImportHandler) ImportFromURL(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID := c.GetString("request_id")
+
+	var req models.ArtworkImportURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(requestID).
+			GJSON(c)
+		return
+	}
+
+	artwork, err := h.service.Import(ctx, &req)
+	if err != nil {
+		var dup *service.DuplicateError
+		switch {
+		case errors.As(err, &dup):
+			response.Conflict(dup.Error()).
+				WithRequestID(requestID).
+				GJSON(c)
+		case errors.Is(err, service.ErrInvalidURL),
+			errors.Is(err, service.ErrForbiddenAddress),
+			errors.Is(err, service.ErrNotImage),
+			errors.Is(err, service.ErrTooLarge),
+			errors.Is(err, service.ErrUnsupportedFormat),
+			errors.Is(err, service.ErrInvalidRating):
+			response.BadRequest(err.Error()).
+				WithRequestID(requestID).
+				GJSON(c)
+		default:
+			log.Ctx(ctx).Error().Err(err).Str("url", req.URL).Msg("从网络地址导入失败")
+			response.InternalError("导入失败").
+				WithRequestID(requestID).
+				GJSON(c)
+		}
+		return
+	}
+
+	response.OK().WithData(artwork).
+		WithRequestID(requestID).
+		GJSON(c)
+}
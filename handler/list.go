@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"strconv"
+	"strings"
+
+	"pln/service"
 
 	"github.com/Yuelioi/gkit/web/response"
 	"github.com/gin-gonic/gin"
@@ -15,19 +19,27 @@ import (
 // @Produce json
 // @Param page query int false "页码" default(1)
 // @Param page_size query int false "每页数量" default(20)
+// @Param tags query []string false "标签过滤，支持 ns:value 与 ns:*（命名空间下任意标签）" collectionFormat(multi)
+// @Param artist query string false "作者"
+// @Param license query string false "授权协议"
+// @Param q query string false "关键词：全文检索标题、描述、作者与标签，按相关度排序并返回高亮摘要；其中的 ns:value 按标签过滤"
+// @Param collection query string false "作品集：数字为手动作品集 ID，否则为智能作品集名称"
+// @Param rating query []string false "允许的内容分级，all 表示全部；匿名请求默认只显示 safe" collectionFormat(csv)
 // @Success 200 {object} response.Response{data=[]models.ArtworkResponse} "获取成功"
 // @Router /artworks [get]
 func (h *ArtworkHandler) ListArtworks(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
 
-	filters := make(map[string]any)
-
-	if tags := c.QueryArray("tags"); len(tags) > 0 {
-		filters["tags"] = tags
-	}
+	filters := parseArtworkFilters(c)
 
 	artworks, total, err := h.service.GetArtworks(page, pageSize, filters)
+	if errors.Is(err, service.ErrCollectionNotFound) {
+		response.NotFound(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Msg("获取作品列表失败")
 		response.InternalError("获取作品列表失败").
@@ -40,3 +52,23 @@ func (h *ArtworkHandler) ListArtworks(c *gin.Context) {
 		WithRequestID(c.GetString("request_id")).
 		GJSON(c)
 }
+
+// parseArtworkFilters 解析作品列表的过滤参数
+func parseArtworkFilters(c *gin.Context) map[string]any {
+	filters := make(map[string]any)
+
+	if tags := c.QueryArray("tags"); len(tags) > 0 {
+		filters["tags"] = tags
+	}
+	for _, key := range []string{"artist", "license", "q", "collection"} {
+		if v := strings.TrimSpace(c.Query(key)); v != "" {
+			filters[key] = v
+		}
+	}
+
+	if ratings := requestRatings(c); ratings != nil {
+		filters["ratings"] = ratings
+	}
+
+	return filters
+}
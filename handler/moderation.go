@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"pln/conf"
+	"pln/middleware"
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ModerationHandler struct {
+	service service.ModerationService
+}
+
+func NewModerationHandler(service service.ModerationService) *ModerationHandler {
+	return &ModerationHandler{service: service}
+}
+
+// ListModerationQueue 审核队列
+// @Summary 审核队列
+// @Description 待审核的作品按上传顺序排列，已驳回的作品按审核时间倒序排列
+// @Tags Moderation
+// @Produce json
+// @Param status query string false "审核状态" Enums(pending, rejected) default(pending)
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=[]models.ModerationItem} "获取成功"
+// @Router /moderation/queue [get]
+func (h *ModerationHandler) ListModerationQueue(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	items, total, err := h.service.Queue(c.Query("status"), page, pageSize)
+	if err != nil {
+		writeModerationError(c, err, "获取审核队列失败")
+		return
+	}
+
+	response.Page(items, total, page, pageSize).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// ApproveArtwork 审核通过
+// @Summary 审核通过
+// @Description 通过后作品出现在公开接口中
+// @Tags Moderation
+// @Produce json
+// @Param id path int true "作品ID"
+// @Success 200 {object} response.Response{data=models.ModerationItem} "审核成功"
+// @Router /moderation/{id}/approve [post]
+func (h *ModerationHandler) ApproveArtwork(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid artwork id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	item, err := h.service.Approve(actorContext(c), uint(id))
+	if err != nil {
+		writeModerationError(c, err, "审核作品失败")
+		return
+	}
+
+	response.OK().WithData(item).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// RejectArtwork 驳回
+// @Summary 驳回
+// @Description 驳回后删除作品并清理存储中的文件，驳回记录可在审核队列中查看
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "作品ID"
+// @Param body body models.ModerationRejectRequest false "驳回原因"
+// @Success 200 {object} response.Response{data=models.ModerationItem} "驳回成功"
+// @Router /moderation/{id}/reject [post]
+func (h *ModerationHandler) RejectArtwork(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid artwork id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	var req models.ModerationRejectRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.BadRequest(err.Error()).
+				WithRequestID(c.GetString("request_id")).
+				GJSON(c)
+			return
+		}
+	}
+
+	item, err := h.service.Reject(actorContext(c), uint(id), req.Reason)
+	if err != nil {
+		writeModerationError(c, err, "驳回作品失败")
+		return
+	}
+
+	response.OK().WithData(item).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// BulkModerate 批量审核
+// @Summary 批量审核
+// @Description 批量通过或驳回，不存在或不在待审核状态的作品会被跳过
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param body body models.ModerationBulkRequest true "审核操作"
+// @Success 200 {object} response.Response{data=models.ModerationResult} "审核结果"
+// @Router /moderation/bulk [post]
+func (h *ModerationHandler) BulkModerate(c *gin.Context) {
+	var req models.ModerationBulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	result, err := h.service.Bulk(actorContext(c), &req)
+	if err != nil {
+		writeModerationError(c, err, "批量审核失败")
+		return
+	}
+
+	response.OK().WithData(result).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// uploadStatus 上传作品的审核状态：开启审核时 uploader 以下的上传需等待审核
+func uploadStatus(c *gin.Context) string {
+	if conf.Config.Moderation.Enabled && !middleware.HasRole(c, models.RoleUploader) {
+		return models.StatusPending
+	}
+	return models.StatusApproved
+}
+
+// writeModerationError 将审核错误映射为响应
+func writeModerationError(c *gin.Context, err error, msg string) {
+	requestID := c.GetString("request_id")
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.NotFound("作品不存在").WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrNotPending):
+		response.Conflict(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrInvalidModeration):
+		response.BadRequest(err.Error()).WithRequestID(requestID).GJSON(c)
+	default:
+		log.Error().Err(err).Msg(msg)
+		response.InternalError(msg).WithRequestID(requestID).GJSON(c)
+	}
+}
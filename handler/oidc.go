@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/netip"
+	"time"
+
+	"pln/middleware"
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	oidcFlowCookie = "pln_oidc"          // 保存登录流程状态直到回调
+	oidcFlowPath   = "/api/v1/auth/oidc" // 只在登录与回调时携带
+	oidcFlowMaxAge = 10 * 60             // 登录流程的有效期（秒）
+	oidcLoginPath  = oidcFlowPath + "/login"
+)
+
+type OIDCHandler struct {
+	service    service.OIDCService // 未启用单点登录时为 nil
+	name       string
+	sessionTTL time.Duration
+	proxies    []netip.Prefix
+}
+
+func NewOIDCHandler(service service.OIDCService, name string, sessionTTL time.Duration, proxies []netip.Prefix) *OIDCHandler {
+	return &OIDCHandler{service: service, name: name, sessionTTL: sessionTTL, proxies: proxies}
+}
+
+// GetOIDCInfo 单点登录配置
+// @Summary 单点登录配置
+// @Description 前端据此决定是否显示单点登录入口
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} response.Response{data=models.OIDCInfo} "获取成功"
+// @Router /auth/oidc [get]
+func (h *OIDCHandler) GetOIDCInfo(c *gin.Context) {
+	info := models.OIDCInfo{Enabled: h.service != nil}
+	if info.Enabled {
+		info.Name = h.name
+		info.LoginURL = oidcLoginPath
+	}
+
+	response.OK().WithData(info).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// OIDCLogin 发起单点登录
+// @Summary 发起单点登录
+// @Description 生成 state、nonce 与 PKCE 参数并跳转到身份提供方，登录完成后回到 redirect 指定的站内路径
+// @Tags Auth
+// @Param redirect query string false "登录完成后跳转的站内路径" default(/)
+// @Success 302 "跳转到身份提供方"
+// @Router /auth/oidc/login [get]
+func (h *OIDCHandler) OIDCLogin(c *gin.Context) {
+	authURL, flow, err := h.service.Begin(c.Request.Context(), c.DefaultQuery("redirect", "/"))
+	if err != nil {
+		writeOIDCError(c, err)
+		return
+	}
+
+	data, err := json.Marshal(flow)
+	if err != nil {
+		writeOIDCError(c, err)
+		return
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcFlowCookie, base64.RawURLEncoding.EncodeToString(data), oidcFlowMaxAge, oidcFlowPath, "", c.Request.TLS != nil, true)
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback 单点登录回调
+// @Summary 单点登录回调
+// @Description 校验 state，用授权码换取 ID Token 并校验签名与声明，按用户组映射角色后创建会话并跳转回站内
+// @Tags Auth
+// @Param code query string true "授权码"
+// @Param state query string true "state"
+// @Success 302 "登录成功，跳转回站内"
+// @Router /auth/oidc/callback [get]
+func (h *OIDCHandler) OIDCCallback(c *gin.Context) {
+	var flow *service.OIDCFlow
+	if raw, err := c.Cookie(oidcFlowCookie); err == nil {
+		if data, err := base64.RawURLEncoding.DecodeString(raw); err == nil {
+			_ = json.Unmarshal(data, &flow)
+		}
+	}
+	// 流程状态只能使用一次
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcFlowCookie, "", -1, oidcFlowPath, "", c.Request.TLS != nil, true)
+
+	if e := c.Query("error"); e != "" {
+		response.Unauthorized("身份提供方拒绝了登录: " + e + " " + c.Query("error_description")).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	result, err := h.service.Finish(c.Request.Context(), flow, c.Query("state"), c.Query("code"),
+		c.Request.UserAgent(), middleware.ClientIP(c, h.proxies))
+	if err != nil {
+		writeOIDCError(c, err)
+		return
+	}
+
+	setSessionCookie(c, result.Token, h.sessionTTL)
+	c.Redirect(http.StatusFound, flow.Redirect)
+}
+
+// writeOIDCError 将单点登录错误映射为响应
+func writeOIDCError(c *gin.Context, err error) {
+	requestID := c.GetString("request_id")
+
+	switch {
+	case errors.Is(err, service.ErrOIDCState):
+		response.BadRequest(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrInvalidIDToken):
+		log.Warn().Err(err).Msg("ID Token 校验失败")
+		response.Unauthorized(service.ErrInvalidIDToken.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrOIDCNoRole),
+		errors.Is(err, service.ErrInvalidCredentials):
+		response.Forbidden(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrUserExists):
+		response.Conflict("用户名已被本地账户占用").WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrInvalidUser):
+		response.BadRequest(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrOIDCProvider):
+		log.Error().Err(err).Msg("单点登录失败")
+		response.ServiceUnavailable(service.ErrOIDCProvider.Error()).WithRequestID(requestID).GJSON(c)
+	default:
+		log.Error().Err(err).Msg("单点登录失败")
+		response.InternalError("单点登录失败").WithRequestID(requestID).GJSON(c)
+	}
+}
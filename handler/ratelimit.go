@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"errors"
+
+	"pln/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errRateLimited 一次请求处理多个条目时超出限流的条目
+var errRateLimited = errors.New("请求过于频繁，请稍后再试")
+
+// chargePerItem 按处理的条目数追加扣除当前请求的限流令牌，请求本身已扣除的令牌抵扣第一个条目
+func chargePerItem(c *gin.Context) func(n int) error {
+	prepaid := 1
+	return func(n int) error {
+		paid := min(prepaid, n)
+		if !middleware.ChargeRateLimit(c, n-paid) {
+			return errRateLimited
+		}
+		prepaid -= paid
+		return nil
+	}
+}
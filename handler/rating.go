@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"strings"
+
+	"pln/conf"
+	"pln/middleware"
+	"pln/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestRatings 请求可见的内容分级，nil 表示不限制。
+// rating 参数（可重复或逗号分隔，all 表示全部）为主动选择；
+// 否则已认证的请求不限制，匿名请求只能看到不高于 content.public_max_rating 的作品。
+func requestRatings(c *gin.Context) []string {
+	var ratings []string
+	for _, value := range c.QueryArray("rating") {
+		for _, rating := range strings.Split(value, ",") {
+			rating = strings.ToLower(strings.TrimSpace(rating))
+			if rating == "all" {
+				return nil
+			}
+			if models.ValidRating(rating) {
+				ratings = append(ratings, rating)
+			}
+		}
+	}
+	if len(ratings) > 0 {
+		return ratings
+	}
+
+	if c.GetBool(middleware.AuthenticatedKey) {
+		return nil
+	}
+	return models.RatingsUpTo(conf.Config.Content.PublicMaxRating)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"pln/middleware"
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ReactionHandler struct {
+	service service.ReactionService
+}
+
+func NewReactionHandler(service service.ReactionService) *ReactionHandler {
+	return &ReactionHandler{service: service}
+}
+
+// Like 点赞
+// @Summary 点赞
+// @Description 每个客户端（pln_cid Cookie、X-Client-ID 头或 API Key）对同一作品只计一次，重复点赞不改变计数
+// @Tags Reaction
+// @Produce json
+// @Param id path int true "作品ID"
+// @Success 200 {object} response.Response{data=models.ReactionResult} "操作成功"
+// @Router /artworks/{id}/like [post]
+func (h *ReactionHandler) Like(c *gin.Context) {
+	h.react(c, models.ReactionLike, true, "点赞失败")
+}
+
+// Unlike 取消点赞
+// @Summary 取消点赞
+// @Description 只能取消当前客户端的点赞，未点赞时不改变计数
+// @Tags Reaction
+// @Produce json
+// @Param id path int true "作品ID"
+// @Success 200 {object} response.Response{data=models.ReactionResult} "操作成功"
+// @Router /artworks/{id}/unlike [post]
+func (h *ReactionHandler) Unlike(c *gin.Context) {
+	h.react(c, models.ReactionLike, false, "取消点赞失败")
+}
+
+// Bookmark 收藏
+// @Summary 收藏
+// @Description 每个客户端对同一作品只计一次，重复收藏不改变计数
+// @Tags Reaction
+// @Produce json
+// @Param id path int true "作品ID"
+// @Success 200 {object} response.Response{data=models.ReactionResult} "操作成功"
+// @Router /artworks/{id}/bookmark [post]
+func (h *ReactionHandler) Bookmark(c *gin.Context) {
+	h.react(c, models.ReactionBookmark, true, "收藏失败")
+}
+
+// Unbookmark 取消收藏
+// @Summary 取消收藏
+// @Description 只能取消当前客户端的收藏，未收藏时不改变计数
+// @Tags Reaction
+// @Produce json
+// @Param id path int true "作品ID"
+// @Success 200 {object} response.Response{data=models.ReactionResult} "操作成功"
+// @Router /artworks/{id}/unbookmark [post]
+func (h *ReactionHandler) Unbookmark(c *gin.Context) {
+	h.react(c, models.ReactionBookmark, false, "取消收藏失败")
+}
+
+// ListReactions 当前客户端的互动状态
+// @Summary 当前客户端的互动状态
+// @Description 按 ids 的顺序返回当前客户端是否点赞、收藏了这些作品
+// @Tags Reaction
+// @Produce json
+// @Param ids query string true "作品ID，逗号分隔，最多 200 个"
+// @Success 200 {object} response.Response{data=[]models.ReactionState} "获取成功"
+// @Router /artworks/reactions [get]
+func (h *ReactionHandler) ListReactions(c *gin.Context) {
+	var ids []uint
+	for _, value := range c.QueryArray("ids") {
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s == "" {
+				continue
+			}
+			id, err := strconv.ParseUint(s, 10, 32)
+			if err != nil {
+				response.BadRequest("invalid artwork id").
+					WithRequestID(c.GetString("request_id")).
+					GJSON(c)
+				return
+			}
+			ids = append(ids, uint(id))
+		}
+	}
+
+	states, err := h.service.States(c.GetString(middleware.ClientKey), ids)
+	if err != nil {
+		writeReactionError(c, err, "获取互动状态失败")
+		return
+	}
+
+	response.OK().WithData(states).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+func (h *ReactionHandler) react(c *gin.Context, kind string, on bool, msg string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid artwork id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	result, err := h.service.React(uint(id), c.GetString(middleware.ClientKey), kind, on)
+	if err != nil {
+		writeReactionError(c, err, msg)
+		return
+	}
+
+	response.OK().WithData(result).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// writeReactionError 将互动错误映射为响应
+func writeReactionError(c *gin.Context, err error, msg string) {
+	requestID := c.GetString("request_id")
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.NotFound("artwork not found").WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrInvalidReaction), errors.Is(err, service.ErrNoClient):
+		response.BadRequest(err.Error()).WithRequestID(requestID).GJSON(c)
+	default:
+		log.Error().Err(err).Msg(msg)
+		response.InternalError(msg).WithRequestID(requestID).GJSON(c)
+	}
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type SmartCollectionHandler struct {
+	service service.SmartCollectionService
+}
+
+func NewSmartCollectionHandler(service service.SmartCollectionService) *SmartCollectionHandler {
+	return &SmartCollectionHandler{service: service}
+}
+
+// ListSmartCollections 智能作品集列表
+// @Summary 智能作品集列表
+// @Description 按名称分页列出智能作品集及当前匹配的作品数
+// @Tags SmartCollection
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=[]models.SmartCollectionResponse} "获取成功"
+// @Router /smart-collections [get]
+func (h *SmartCollectionHandler) ListSmartCollections(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	collections, total, err := h.service.ListSmartCollections(page, pageSize)
+	if err != nil {
+		log.Error().Err(err).Msg("获取智能作品集列表失败")
+		response.InternalError("获取智能作品集列表失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	response.Page(collections, total, page, pageSize).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// GetSmartCollection 智能作品集详情
+// @Summary 智能作品集详情
+// @Tags SmartCollection
+// @Produce json
+// @Param name path string true "名称"
+// @Success 200 {object} response.Response{data=models.SmartCollectionResponse} "获取成功"
+// @Router /smart-collections/{name} [get]
+func (h *SmartCollectionHandler) GetSmartCollection(c *gin.Context) {
+	collection, err := h.service.GetSmartCollection(c.Param("name"))
+	if err != nil {
+		writeSmartCollectionError(c, err, "获取智能作品集失败")
+		return
+	}
+
+	response.OK().WithData(collection).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// ListSmartCollectionArtworks 智能作品集中的作品
+// @Summary 智能作品集中的作品
+// @Description 按保存的检索条件实时查询，与 GET /artworks?collection={name} 等价
+// @Tags SmartCollection
+// @Produce json
+// @Param name path string true "名称"
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Param rating query []string false "允许的内容分级，all 表示全部；匿名请求默认只显示 safe" collectionFormat(csv)
+// @Success 200 {object} response.Response{data=[]models.ArtworkResponse} "获取成功"
+// @Router /smart-collections/{name}/artworks [get]
+func (h *SmartCollectionHandler) ListSmartCollectionArtworks(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	artworks, total, err := h.service.ListArtworks(c.Param("name"), page, pageSize, requestRatings(c))
+	if err != nil {
+		writeSmartCollectionError(c, err, "获取智能作品集作品失败")
+		return
+	}
+
+	response.Page(artworks, total, page, pageSize).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// CreateSmartCollection 创建智能作品集
+// @Summary 创建智能作品集
+// @Description 保存一组检索条件（tags / artist / license / q），之后可通过名称查询或作为随机接口的来源
+// @Tags SmartCollection
+// @Accept json
+// @Produce json
+// @Param body body models.SmartCollectionRequest true "智能作品集"
+// @Success 200 {object} response.Response{data=models.SmartCollectionResponse} "创建成功"
+// @Router /smart-collections [post]
+func (h *SmartCollectionHandler) CreateSmartCollection(c *gin.Context) {
+	var req models.SmartCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	collection, err := h.service.CreateSmartCollection(&req)
+	if err != nil {
+		writeSmartCollectionError(c, err, "创建智能作品集失败")
+		return
+	}
+
+	response.OK().WithData(collection).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// UpdateSmartCollection 更新智能作品集
+// @Summary 更新智能作品集
+// @Description 修改标题、描述与检索条件，名称不可修改
+// @Tags SmartCollection
+// @Accept json
+// @Produce json
+// @Param name path string true "名称"
+// @Param body body models.SmartCollectionUpdateRequest true "智能作品集"
+// @Success 200 {object} response.Response{data=models.SmartCollectionResponse} "更新成功"
+// @Router /smart-collections/{name} [put]
+func (h *SmartCollectionHandler) UpdateSmartCollection(c *gin.Context) {
+	var req models.SmartCollectionUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	collection, err := h.service.UpdateSmartCollection(c.Param("name"), &req)
+	if err != nil {
+		writeSmartCollectionError(c, err, "更新智能作品集失败")
+		return
+	}
+
+	response.OK().WithData(collection).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// DeleteSmartCollection 删除智能作品集
+// @Summary 删除智能作品集
+// @Tags SmartCollection
+// @Produce json
+// @Param name path string true "名称"
+// @Success 204 {object} response.Response "删除成功"
+// @Router /smart-collections/{name} [delete]
+func (h *SmartCollectionHandler) DeleteSmartCollection(c *gin.Context) {
+	if err := h.service.DeleteSmartCollection(c.Param("name")); err != nil {
+		writeSmartCollectionError(c, err, "删除智能作品集失败")
+		return
+	}
+
+	response.NoContent().
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// writeSmartCollectionError 将智能作品集错误映射为响应
+func writeSmartCollectionError(c *gin.Context, err error, msg string) {
+	requestID := c.GetString("request_id")
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, service.ErrCollectionNotFound):
+		response.NotFound("智能作品集不存在").WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrInvalidSmartCollection):
+		response.BadRequest(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrSmartCollectionExists):
+		response.Conflict(err.Error()).WithRequestID(requestID).GJSON(c)
+	default:
+		log.Error().Err(err).Msg(msg)
+		response.InternalError(msg).WithRequestID(requestID).GJSON(c)
+	}
+}
@@ -0,0 +1,460 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type TagHandler struct {
+	service service.TagService
+}
+
+func NewTagHandler(service service.TagService) *TagHandler {
+	return &TagHandler{service: service}
+}
+
+// ListTags 标签目录
+// @Summary 标签目录
+// @Description 分页列出已使用的标签及其作品数
+// @Tags Tag
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(50)
+// @Param sort query string false "排序字段" Enums(count, name) default(count)
+// @Param order query string false "排序方向，count 默认 desc，name 默认 asc" Enums(asc, desc)
+// @Param namespace query string false "只列出该命名空间下的标签"
+// @Param prefix query string false "标签前缀"
+// @Param rating query []string false "只统计这些分级的作品，all 表示全部；匿名请求默认只统计 safe" collectionFormat(csv)
+// @Success 200 {object} response.Response{data=[]models.TagUsage} "获取成功"
+// @Router /tags [get]
+func (h *TagHandler) ListTags(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 200 {
+		pageSize = 50
+	}
+
+	sort := c.DefaultQuery("sort", "count")
+	if sort != "count" && sort != "name" {
+		response.BadRequest("sort 只能为 count 或 name").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+	desc := sort == "count"
+	switch c.Query("order") {
+	case "asc":
+		desc = false
+	case "desc":
+		desc = true
+	}
+
+	tags, total, err := h.service.ListTags(models.TagListQuery{
+		Prefix:    c.Query("prefix"),
+		Namespace: c.Query("namespace"),
+		Sort:      sort,
+		Desc:      desc,
+		Ratings:   requestRatings(c),
+		Offset:    (page - 1) * pageSize,
+		Limit:     pageSize,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("获取标签目录失败")
+		response.InternalError("获取标签目录失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	response.Page(tags, total, page, pageSize).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// AutocompleteTags 标签自动补全
+// @Summary 标签自动补全
+// @Description 按前缀补全标签（也匹配命名空间之后的部分），包含通过别名匹配到的规范标签，按作品数排序
+// @Tags Tag
+// @Produce json
+// @Param q query string true "前缀"
+// @Param limit query int false "数量" default(10)
+// @Param rating query []string false "只统计这些分级的作品，all 表示全部；匿名请求默认只统计 safe" collectionFormat(csv)
+// @Success 200 {object} response.Response{data=[]models.TagSuggestion} "获取成功"
+// @Router /tags/autocomplete [get]
+func (h *TagHandler) AutocompleteTags(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	suggestions, err := h.service.Autocomplete(c.Query("q"), limit, requestRatings(c))
+	if err != nil {
+		log.Error().Err(err).Msg("标签自动补全失败")
+		response.InternalError("标签自动补全失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	response.OK().WithData(suggestions).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// RelatedTags 相关标签
+// @Summary 相关标签
+// @Description 统计与指定标签同时出现在作品上的标签，按共同出现次数排序
+// @Tags Tag
+// @Produce json
+// @Param tag query string true "标签（别名会被替换为规范标签）"
+// @Param limit query int false "数量" default(20)
+// @Param rating query []string false "只统计这些分级的作品，all 表示全部；匿名请求默认只统计 safe" collectionFormat(csv)
+// @Success 200 {object} response.Response{data=[]models.TagUsage} "count 为共同出现的作品数"
+// @Router /tags/related [get]
+func (h *TagHandler) RelatedTags(c *gin.Context) {
+	tag := c.Query("tag")
+	if tag == "" {
+		response.BadRequest("缺少 tag 参数").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	tags, err := h.service.RelatedTags(tag, limit, requestRatings(c))
+	if err != nil {
+		log.Error().Err(err).Msg("获取相关标签失败")
+		response.InternalError("获取相关标签失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	response.OK().WithData(tags).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// ListAliases 获取标签别名
+// @Summary 获取标签别名
+// @Description 获取全部标签别名，写入与检索时别名会被替换为规范标签
+// @Tags Tag
+// @Produce json
+// @Success 200 {object} response.Response{data=[]models.TagAlias} "获取成功"
+// @Router /tags/aliases [get]
+func (h *TagHandler) ListAliases(c *gin.Context) {
+	aliases, err := h.service.ListAliases()
+	if err != nil {
+		log.Error().Err(err).Msg("获取标签别名失败")
+		response.InternalError("获取标签别名失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	response.OK().WithData(aliases).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// CreateAlias 创建标签别名
+// @Summary 创建标签别名
+// @Description 创建别名 → 规范标签的映射，已有作品需调用 /tags/reapply 生效
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Param body body models.TagAliasRequest true "别名"
+// @Success 200 {object} response.Response{data=models.TagAlias} "创建成功"
+// @Router /tags/aliases [post]
+func (h *TagHandler) CreateAlias(c *gin.Context) {
+	var req models.TagAliasRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	alias, err := h.service.CreateAlias(&req)
+	if err != nil {
+		writeTagRuleError(c, err, "创建标签别名失败")
+		return
+	}
+
+	response.OK().WithData(alias).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// DeleteAlias 删除标签别名
+// @Summary 删除标签别名
+// @Tags Tag
+// @Param id path int true "别名ID"
+// @Success 204
+// @Router /tags/aliases/{id} [delete]
+func (h *TagHandler) DeleteAlias(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid alias id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	if err := h.service.DeleteAlias(uint(id)); err != nil {
+		writeTagRuleError(c, err, "删除标签别名失败")
+		return
+	}
+
+	response.NoContent().
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// ListImplications 获取标签蕴含规则
+// @Summary 获取标签蕴含规则
+// @Description 获取全部蕴含规则，带有 tag 的作品会自动补充 implies
+// @Tags Tag
+// @Produce json
+// @Success 200 {object} response.Response{data=[]models.TagImplication} "获取成功"
+// @Router /tags/implications [get]
+func (h *TagHandler) ListImplications(c *gin.Context) {
+	implications, err := h.service.ListImplications()
+	if err != nil {
+		log.Error().Err(err).Msg("获取标签蕴含规则失败")
+		response.InternalError("获取标签蕴含规则失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	response.OK().WithData(implications).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// CreateImplication 创建标签蕴含规则
+// @Summary 创建标签蕴含规则
+// @Description 创建 tag → implies 规则，会形成循环的规则将被拒绝；已有作品需调用 /tags/reapply 生效
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Param body body models.TagImplicationRequest true "蕴含规则"
+// @Success 200 {object} response.Response{data=models.TagImplication} "创建成功"
+// @Router /tags/implications [post]
+func (h *TagHandler) CreateImplication(c *gin.Context) {
+	var req models.TagImplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	implication, err := h.service.CreateImplication(&req)
+	if err != nil {
+		writeTagRuleError(c, err, "创建标签蕴含规则失败")
+		return
+	}
+
+	response.OK().WithData(implication).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// DeleteImplication 删除标签蕴含规则
+// @Summary 删除标签蕴含规则
+// @Tags Tag
+// @Param id path int true "规则ID"
+// @Success 204
+// @Router /tags/implications/{id} [delete]
+func (h *TagHandler) DeleteImplication(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid implication id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	if err := h.service.DeleteImplication(uint(id)); err != nil {
+		writeTagRuleError(c, err, "删除标签蕴含规则失败")
+		return
+	}
+
+	response.NoContent().
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// ListNamespaces 获取标签命名空间
+// @Summary 获取标签命名空间
+// @Description 获取全部命名空间及其颜色与排序，ns:value 形式的标签按命名空间分组展示
+// @Tags Tag
+// @Produce json
+// @Success 200 {object} response.Response{data=[]models.TagNamespace} "获取成功"
+// @Router /tags/namespaces [get]
+func (h *TagHandler) ListNamespaces(c *gin.Context) {
+	namespaces, err := h.service.ListNamespaces()
+	if err != nil {
+		log.Error().Err(err).Msg("获取标签命名空间失败")
+		response.InternalError("获取标签命名空间失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	response.OK().WithData(namespaces).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// CreateNamespace 创建标签命名空间
+// @Summary 创建标签命名空间
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Param body body models.TagNamespaceRequest true "命名空间"
+// @Success 200 {object} response.Response{data=models.TagNamespace} "创建成功"
+// @Router /tags/namespaces [post]
+func (h *TagHandler) CreateNamespace(c *gin.Context) {
+	var req models.TagNamespaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	namespace, err := h.service.CreateNamespace(&req)
+	if err != nil {
+		writeTagRuleError(c, err, "创建标签命名空间失败")
+		return
+	}
+
+	response.OK().WithData(namespace).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// UpdateNamespace 更新标签命名空间
+// @Summary 更新标签命名空间
+// @Description 修改显示名称、颜色与排序，名称不可修改
+// @Tags Tag
+// @Accept json
+// @Produce json
+// @Param id path int true "命名空间ID"
+// @Param body body models.TagNamespaceUpdateRequest true "命名空间"
+// @Success 200 {object} response.Response{data=models.TagNamespace} "更新成功"
+// @Router /tags/namespaces/{id} [put]
+func (h *TagHandler) UpdateNamespace(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid namespace id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	var req models.TagNamespaceUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	namespace, err := h.service.UpdateNamespace(uint(id), &req)
+	if err != nil {
+		writeTagRuleError(c, err, "更新标签命名空间失败")
+		return
+	}
+
+	response.OK().WithData(namespace).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// DeleteNamespace 删除标签命名空间
+// @Summary 删除标签命名空间
+// @Description 删除后已有的 ns:value 标签保留为普通标签
+// @Tags Tag
+// @Param id path int true "命名空间ID"
+// @Success 204
+// @Router /tags/namespaces/{id} [delete]
+func (h *TagHandler) DeleteNamespace(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid namespace id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	if err := h.service.DeleteNamespace(uint(id)); err != nil {
+		writeTagRuleError(c, err, "删除标签命名空间失败")
+		return
+	}
+
+	response.NoContent().
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// ReapplyRules 对已有作品重新应用标签规则
+// @Summary 重新应用标签规则
+// @Description 对全部作品重新应用别名与蕴含规则
+// @Tags Tag
+// @Produce json
+// @Success 200 {object} response.Response{data=models.TagReapplyResult} "处理结果"
+// @Router /tags/reapply [post]
+func (h *TagHandler) ReapplyRules(c *gin.Context) {
+	result, err := h.service.ReapplyRules(actorContext(c))
+	if err != nil {
+		log.Error().Err(err).Msg("重新应用标签规则失败")
+		response.InternalError("重新应用标签规则失败").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	response.OK().WithData(result).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// writeTagRuleError 将标签规则与命名空间错误映射为响应
+func writeTagRuleError(c *gin.Context, err error, msg string) {
+	requestID := c.GetString("request_id")
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.NotFound("记录不存在").WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrInvalidTagRule),
+		errors.Is(err, service.ErrInvalidNamespace):
+		response.BadRequest(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrTagRuleExists),
+		errors.Is(err, service.ErrTagRuleConflict),
+		errors.Is(err, service.ErrTagCycle),
+		errors.Is(err, service.ErrNamespaceExists):
+		response.Conflict(err.Error()).WithRequestID(requestID).GJSON(c)
+	default:
+		log.Error().Err(err).Msg(msg)
+		response.InternalError(msg).WithRequestID(requestID).GJSON(c)
+	}
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"pln/models"
+	"pln/service"
 	"strconv"
 
 	"github.com/Yuelioi/gkit/web/response"
@@ -36,136 +38,22 @@ func (h *ArtworkHandler) UpdateArtwork(c *gin.Context) {
 		return
 	}
 
-	artwork, err := h.service.UpdateArtwork(uint(id), &req)
-	if err != nil {
-		log.Error().Err(err).Msg("更新作品失败")
-		response.InternalError("更新作品失败").
-			WithRequestID(c.GetString("request_id")).
-			GJSON(c)
-		return
-	}
-
-	response.OK().WithData(artwork).
-		WithRequestID(c.GetString("request_id")).
-		GJSON(c)
-}
-
-// IncrementLikes 增加点赞数
-// @Summary 增加作品点赞数
-// @Description 增加作品的点赞数
-// @Tags Artwork
-// @Produce json
-// @Param id path int true "作品ID"
-// @Success 200 {object} response.Response "操作成功"
-// @Router /artworks/{id}/like [post]
-func (h *ArtworkHandler) IncrementLikes(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		response.BadRequest("invalid artwork id").
-			WithRequestID(c.GetString("request_id")).
-			GJSON(c)
-		return
-	}
-
-	if err := h.service.IncrementLikes(uint(id)); err != nil {
-		log.Error().Err(err).Msg("点赞失败")
-		response.InternalError("点赞失败").
-			WithRequestID(c.GetString("request_id")).
-			GJSON(c)
-		return
-	}
-
-	response.OK().
-		WithRequestID(c.GetString("request_id")).
-		GJSON(c)
-}
-
-// DecrementLikes 减少点赞数
-// @Summary 取消点赞
-// @Description 减少作品的点赞数
-// @Tags Artwork
-// @Produce json
-// @Param id path int true "作品ID"
-// @Success 200 {object} response.Response "操作成功"
-// @Router /artworks/{id}/unlike [post]
-func (h *ArtworkHandler) DecrementLikes(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		response.BadRequest("invalid artwork id").
-			WithRequestID(c.GetString("request_id")).
-			GJSON(c)
-		return
-	}
-
-	if err := h.service.DecrementLikes(uint(id)); err != nil {
-		log.Error().Err(err).Msg("取消点赞失败")
-		response.InternalError("取消点赞失败").
-			WithRequestID(c.GetString("request_id")).
-			GJSON(c)
-		return
-	}
-
-	response.OK().
-		WithRequestID(c.GetString("request_id")).
-		GJSON(c)
-}
-
-// IncrementBookmarks 增加收藏数
-// @Summary 增加收藏数
-// @Description 增加作品的收藏数
-// @Tags Artwork
-// @Produce json
-// @Param id path int true "作品ID"
-// @Success 200 {object} response.Response "操作成功"
-// @Router /artworks/{id}/bookmark [post]
-func (h *ArtworkHandler) IncrementBookmarks(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
-	if err != nil {
-		response.BadRequest("invalid artwork id").
-			WithRequestID(c.GetString("request_id")).
-			GJSON(c)
-		return
-	}
-
-	if err := h.service.IncrementBookmarks(uint(id)); err != nil {
-		log.Error().Err(err).Msg("收藏失败")
-		response.InternalError("收藏失败").
+	artwork, err := h.service.UpdateArtwork(actorContext(c), uint(id), &req)
+	if errors.Is(err, service.ErrInvalidRating) {
+		response.BadRequest(err.Error()).
 			WithRequestID(c.GetString("request_id")).
 			GJSON(c)
 		return
 	}
-
-	response.OK().
-		WithRequestID(c.GetString("request_id")).
-		GJSON(c)
-}
-
-// DecrementBookmarks 减少收藏数
-// @Summary 取消收藏
-// @Description 减少作品的收藏数
-// @Tags Artwork
-// @Produce json
-// @Param id path int true "作品ID"
-// @Success 200 {object} response.Response "操作成功"
-// @Router /artworks/{id}/unbookmark [post]
-func (h *ArtworkHandler) DecrementBookmarks(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		response.BadRequest("invalid artwork id").
-			WithRequestID(c.GetString("request_id")).
-			GJSON(c)
-		return
-	}
-
-	if err := h.service.DecrementBookmarks(uint(id)); err != nil {
-		log.Error().Err(err).Msg("取消收藏失败")
-		response.InternalError("取消收藏失败").
+		log.Error().Err(err).Msg("更新作品失败")
+		response.InternalError("更新作品失败").
 			WithRequestID(c.GetString("request_id")).
 			GJSON(c)
 		return
 	}
 
-	response.OK().
+	response.OK().WithData(artwork).
 		WithRequestID(c.GetString("request_id")).
 		GJSON(c)
 }
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"pln/middleware"
+	"pln/models"
+	"pln/service"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type UserHandler struct {
+	service service.UserService
+}
+
+func NewUserHandler(service service.UserService) *UserHandler {
+	return &UserHandler{service: service}
+}
+
+// ListUsers 用户列表
+// @Summary 用户列表
+// @Tags User
+// @Produce json
+// @Param page query int false "页码" default(1)
+// @Param page_size query int false "每页数量" default(20)
+// @Success 200 {object} response.Response{data=[]models.User} "获取成功"
+// @Router /users [get]
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+
+	users, total, err := h.service.ListUsers(page, pageSize)
+	if err != nil {
+		writeUserError(c, err, "获取用户列表失败")
+		return
+	}
+
+	response.Page(users, total, page, pageSize).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// CreateUser 创建用户
+// @Summary 创建用户
+// @Description 用户名只能包含字母、数字与 _ . @ -，密码长度为 8 到 72 字节，角色默认为 viewer
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param body body models.UserCreateRequest true "用户信息"
+// @Success 200 {object} response.Response{data=models.User} "创建成功"
+// @Router /users [post]
+func (h *UserHandler) CreateUser(c *gin.Context) {
+	var req models.UserCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	user, err := h.service.CreateUser(actorContext(c), &req)
+	if err != nil {
+		writeUserError(c, err, "创建用户失败")
+		return
+	}
+
+	response.OK().WithData(user).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// UpdateUser 更新用户
+// @Summary 更新用户
+// @Description 修改密码、角色或禁用状态，修改密码或禁用后该用户的会话全部失效
+// @Tags User
+// @Accept json
+// @Produce json
+// @Param id path int true "用户ID"
+// @Param body body models.UserUpdateRequest true "更新内容"
+// @Success 200 {object} response.Response{data=models.User} "更新成功"
+// @Router /users/{id} [put]
+func (h *UserHandler) UpdateUser(c *gin.Context) {
+	id, ok := userID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UserUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(err.Error()).
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	// 避免管理员误操作失去自己的权限
+	if self, ok := c.Get(middleware.UserIDKey); ok && self.(uint) == id &&
+		((req.Role != nil && *req.Role != models.RoleAdmin) || (req.Disabled != nil && *req.Disabled)) {
+		response.BadRequest("不能降低自己的角色或禁用自己").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	user, err := h.service.UpdateUser(actorContext(c), id, &req)
+	if err != nil {
+		writeUserError(c, err, "更新用户失败")
+		return
+	}
+
+	response.OK().WithData(user).
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// DeleteUser 删除用户
+// @Summary 删除用户
+// @Description 删除用户及其全部会话
+// @Tags User
+// @Produce json
+// @Param id path int true "用户ID"
+// @Success 204 {object} response.Response "删除成功"
+// @Router /users/{id} [delete]
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	id, ok := userID(c)
+	if !ok {
+		return
+	}
+
+	if self, ok := c.Get(middleware.UserIDKey); ok && self.(uint) == id {
+		response.BadRequest("不能删除自己").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return
+	}
+
+	if err := h.service.DeleteUser(actorContext(c), id); err != nil {
+		writeUserError(c, err, "删除用户失败")
+		return
+	}
+
+	response.NoContent().
+		WithRequestID(c.GetString("request_id")).
+		GJSON(c)
+}
+
+// userID 解析路径中的用户 ID，无效时写入 400 响应
+func userID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		response.BadRequest("invalid user id").
+			WithRequestID(c.GetString("request_id")).
+			GJSON(c)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeUserError 将用户相关错误映射为响应
+func writeUserError(c *gin.Context, err error, msg string) {
+	requestID := c.GetString("request_id")
+
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.NotFound("用户不存在").WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrUserExists):
+		response.Conflict(err.Error()).WithRequestID(requestID).GJSON(c)
+	case errors.Is(err, service.ErrInvalidUser):
+		response.BadRequest(err.Error()).WithRequestID(requestID).GJSON(c)
+	default:
+		log.Error().Err(err).Msg(msg)
+		response.InternalError(msg).WithRequestID(requestID).GJSON(c)
+	}
+}
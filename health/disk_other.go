@@ -0,0 +1,8 @@
+//go:build !unix
+
+package health
+
+// diskUsage 非 Unix 平台不检查磁盘空间
+func diskUsage(dir string) (free, total uint64, err error) {
+	return 0, 0, ErrUnsupported
+}
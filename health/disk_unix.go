@@ -0,0 +1,14 @@
+//go:build unix
+
+package health
+
+import "golang.org/x/sys/unix"
+
+// diskUsage 返回目录所在文件系统对非特权用户可用的空间与总空间
+func diskUsage(dir string) (free, total uint64, err error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(dir, &st); err != nil {
+		return 0, 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), uint64(st.Blocks) * uint64(st.Bsize), nil
+}
@@ -0,0 +1,274 @@
+// Package health 提供存活与就绪检查
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 检查结果状态
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded" // 存在失败的检查
+	StatusFailed   = "failed"
+	StatusSkipped  = "skipped" // 当前平台不支持该检查
+)
+
+// checkTimeout 单次就绪检查的超时时间
+const checkTimeout = 3 * time.Second
+
+// ErrUnsupported 当前平台不支持的检查返回该错误，结果记为 skipped
+var ErrUnsupported = errors.New("当前平台不支持")
+
+// CheckFunc 检查函数，返回的 details 会原样输出
+type CheckFunc func(ctx context.Context) (details map[string]any, err error)
+
+// CheckResult 单项检查结果
+type CheckResult struct {
+	Status     string         `json:"status"`
+	Error      string         `json:"error,omitempty"`
+	DurationMS int64          `json:"duration_ms"`
+	Details    map[string]any `json:"details,omitempty"`
+}
+
+// Report 就绪检查报告
+type Report struct {
+	Status    string                 `json:"status"`
+	Uptime    string                 `json:"uptime"`
+	Timestamp time.Time              `json:"timestamp"`
+	Checks    map[string]CheckResult `json:"checks"`
+}
+
+// Checker 汇总各项依赖检查与后台任务心跳
+type Checker struct {
+	started time.Time
+
+	mu      sync.RWMutex
+	names   []string
+	checks  map[string]CheckFunc
+	workers []*Worker
+}
+
+func NewChecker() *Checker {
+	return &Checker{started: time.Now(), checks: make(map[string]CheckFunc)}
+}
+
+// Add 注册一项就绪检查，同名检查会被替换
+func (h *Checker) Add(name string, fn CheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.checks[name]; !ok {
+		h.names = append(h.names, name)
+	}
+	h.checks[name] = fn
+}
+
+// Worker 注册后台任务，任务需每隔 interval 调用一次 Beat，超过两个周期未调用视为停止
+func (h *Checker) Worker(name string, interval time.Duration) *Worker {
+	w := &Worker{name: name, interval: interval}
+	w.Beat()
+
+	h.mu.Lock()
+	h.workers = append(h.workers, w)
+	h.mu.Unlock()
+	return w
+}
+
+// Check 并发执行所有检查，任意一项失败时整体状态为 degraded
+func (h *Checker) Check(ctx context.Context) Report {
+	h.mu.RLock()
+	checks := make(map[string]CheckFunc, len(h.checks)+len(h.workers))
+	for _, name := range h.names {
+		checks[name] = h.checks[name]
+	}
+	for _, w := range h.workers {
+		checks["worker:"+w.name] = w.check
+	}
+	h.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	report := Report{
+		Status:    StatusOK,
+		Uptime:    time.Since(h.started).Round(time.Second).String(),
+		Timestamp: time.Now(),
+		Checks:    make(map[string]CheckResult, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, fn := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := run(ctx, fn)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if result.Status == StatusFailed {
+				report.Status = StatusDegraded
+			}
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run 执行单项检查，超时时不再等待检查函数返回
+func run(ctx context.Context, fn CheckFunc) CheckResult {
+	start := time.Now()
+
+	type outcome struct {
+		details map[string]any
+		err     error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		details, err := fn(ctx)
+		done <- outcome{details, err}
+	}()
+
+	var out outcome
+	select {
+	case out = <-done:
+	case <-ctx.Done():
+		out.err = fmt.Errorf("检查超时: %w", ctx.Err())
+	}
+
+	result := CheckResult{
+		Status:     StatusOK,
+		DurationMS: time.Since(start).Milliseconds(),
+		Details:    out.details,
+	}
+	switch {
+	case errors.Is(out.err, ErrUnsupported):
+		result.Status = StatusSkipped
+	case out.err != nil:
+		result.Status = StatusFailed
+		result.Error = out.err.Error()
+	}
+	return result
+}
+
+// Liveness 存活检查，进程能处理请求即返回 200
+func (h *Checker) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": StatusOK,
+		"uptime": time.Since(h.started).Round(time.Second).String(),
+	})
+}
+
+// Readiness 就绪检查，存在失败的检查时返回 503
+func (h *Checker) Readiness(c *gin.Context) {
+	report := h.Check(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// Serve 在 livePath 与 readyPath 上响应检查，其余请求直接放行。
+// 需注册为全局中间件，使检查路径不受 API 前缀限制。
+func (h *Checker) Serve(livePath, readyPath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var handler gin.HandlerFunc
+		switch c.Request.URL.Path {
+		case livePath:
+			handler = h.Liveness
+		case readyPath:
+			handler = h.Readiness
+		default:
+			c.Next()
+			return
+		}
+
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.AbortWithStatus(http.StatusMethodNotAllowed)
+			return
+		}
+		c.Header("Cache-Control", "no-store")
+		handler(c)
+		c.Abort()
+	}
+}
+
+// Database 检查数据库连接是否可用
+func Database(db *gorm.DB) CheckFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		if err := sqlDB.PingContext(ctx); err != nil {
+			return nil, fmt.Errorf("连接数据库失败: %w", err)
+		}
+
+		var one int
+		if err := db.WithContext(ctx).Raw("SELECT 1").Scan(&one).Error; err != nil {
+			return nil, fmt.Errorf("执行查询失败: %w", err)
+		}
+
+		stats := sqlDB.Stats()
+		return map[string]any{
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+		}, nil
+	}
+}
+
+// Writable 检查目录是否可写入文件
+func Writable(dir string) CheckFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		f, err := os.CreateTemp(dir, ".healthcheck-*")
+		if err != nil {
+			return nil, fmt.Errorf("目录不可写: %w", err)
+		}
+		name := f.Name()
+		_, err = f.WriteString("ok")
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		os.Remove(name)
+		if err != nil {
+			return nil, fmt.Errorf("写入文件失败: %w", err)
+		}
+
+		abs, _ := filepath.Abs(dir)
+		return map[string]any{"path": abs}, nil
+	}
+}
+
+// DiskSpace 检查目录所在磁盘的剩余空间不低于 minFree 字节
+func DiskSpace(dir string, minFree uint64) CheckFunc {
+	return func(ctx context.Context) (map[string]any, error) {
+		free, total, err := diskUsage(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		details := map[string]any{
+			"free_bytes":     free,
+			"total_bytes":    total,
+			"min_free_bytes": minFree,
+		}
+		if free < minFree {
+			return details, fmt.Errorf("磁盘剩余空间不足: %d < %d 字节", free, minFree)
+		}
+		return details, nil
+	}
+}
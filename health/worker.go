@@ -0,0 +1,35 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Worker 后台任务的心跳
+type Worker struct {
+	name     string
+	interval time.Duration
+	lastBeat atomic.Int64 // UnixNano
+}
+
+// Beat 记录一次心跳，任务每轮执行后调用
+func (w *Worker) Beat() {
+	w.lastBeat.Store(time.Now().UnixNano())
+}
+
+// check 超过两个周期没有心跳时视为任务已停止
+func (w *Worker) check(ctx context.Context) (map[string]any, error) {
+	last := time.Unix(0, w.lastBeat.Load())
+	since := time.Since(last)
+
+	details := map[string]any{
+		"interval":  w.interval.String(),
+		"last_beat": last,
+	}
+	if since > 2*w.interval {
+		return details, fmt.Errorf("超过 %s 没有心跳", since.Round(time.Second))
+	}
+	return details, nil
+}
@@ -0,0 +1,96 @@
+package metrics
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// storageCacheTTL 遍历存储目录的结果缓存时间，避免每次抓取都扫描磁盘
+const storageCacheTTL = time.Minute
+
+var (
+	storageBytesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "storage_bytes"),
+		"存储目录中文件的总字节数（含变体）", nil, nil,
+	)
+	storageFilesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "storage_files"),
+		"存储目录中的文件数（含变体）", nil, nil,
+	)
+	artworksDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "artworks"),
+		"作品总数，按审核状态区分", []string{"status"}, nil,
+	)
+)
+
+// StatsCollector 在抓取时统计存储占用与作品数量
+type StatsCollector struct {
+	storagePath string
+	countFn     func() (map[string]int64, error)
+
+	mu        sync.Mutex
+	scannedAt time.Time
+	bytes     int64
+	files     int64
+}
+
+// NewStatsCollector storagePath 为本地存储目录，countFn 返回各审核状态的作品数
+func NewStatsCollector(storagePath string, countFn func() (map[string]int64, error)) *StatsCollector {
+	return &StatsCollector{storagePath: storagePath, countFn: countFn}
+}
+
+func (s *StatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- storageBytesDesc
+	ch <- storageFilesDesc
+	ch <- artworksDesc
+}
+
+func (s *StatsCollector) Collect(ch chan<- prometheus.Metric) {
+	bytes, files := s.storageUsage()
+	ch <- prometheus.MustNewConstMetric(storageBytesDesc, prometheus.GaugeValue, float64(bytes))
+	ch <- prometheus.MustNewConstMetric(storageFilesDesc, prometheus.GaugeValue, float64(files))
+
+	counts, err := s.countFn()
+	if err != nil {
+		log.Warn().Err(err).Msg("统计作品数量失败")
+		return
+	}
+	for status, n := range counts {
+		ch <- prometheus.MustNewConstMetric(artworksDesc, prometheus.GaugeValue, float64(n), status)
+	}
+}
+
+// storageUsage 返回缓存的存储占用，过期时重新遍历目录
+func (s *StatsCollector) storageUsage() (bytes, files int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.scannedAt) < storageCacheTTL {
+		return s.bytes, s.files
+	}
+
+	err := filepath.WalkDir(s.storagePath, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		bytes += info.Size()
+		files++
+		return nil
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("path", s.storagePath).Msg("统计存储占用失败")
+	}
+
+	s.scannedAt = time.Now()
+	s.bytes, s.files = bytes, files
+	return bytes, files
+}
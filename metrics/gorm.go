@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// GormLogger 包装 GORM 日志，在记录 SQL 的同时统计查询耗时
+type GormLogger struct {
+	logger.Interface
+}
+
+// NewGormLogger 包装已有的 GORM 日志实现
+func NewGormLogger(inner logger.Interface) *GormLogger {
+	return &GormLogger{Interface: inner}
+}
+
+func (l *GormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &GormLogger{Interface: l.Interface.LogMode(level)}
+}
+
+// Trace 每条语句执行后调用，与日志级别无关
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	sql, rows := fc()
+	op := sqlOperation(sql)
+
+	dbDuration.WithLabelValues(op).Observe(elapsed.Seconds())
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		dbErrors.WithLabelValues(op).Inc()
+	}
+
+	// fc 可能较昂贵，交给内层日志时复用结果
+	l.Interface.Trace(ctx, begin, func() (string, int64) { return sql, rows }, err)
+}
+
+// sqlOperation 取语句的首个关键字作为标签，避免 SQL 文本导致标签基数过高
+func sqlOperation(sql string) string {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	switch keyword = strings.ToUpper(keyword); keyword {
+	case "SELECT", "INSERT", "UPDATE", "DELETE", "WITH", "PRAGMA":
+		return strings.ToLower(keyword)
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Instrument 统计 HTTP 请求数与耗时，以路由模板作为标签，未匹配路由的请求统一记为 unmatched
+func Instrument() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Serve 在 path 上输出指标，其余请求直接放行。
+// 需注册为全局中间件，使指标路径不受 API 前缀限制；token 不为空时要求 Authorization: Bearer <token>。
+func Serve(path, token string) gin.HandlerFunc {
+	handler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+
+	return func(c *gin.Context) {
+		if c.Request.URL.Path != path {
+			c.Next()
+			return
+		}
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.AbortWithStatus(http.StatusMethodNotAllowed)
+			return
+		}
+
+		if token != "" {
+			scheme, credential, _ := strings.Cut(c.GetHeader("Authorization"), " ")
+			if !strings.EqualFold(scheme, "Bearer") ||
+				subtle.ConstantTimeCompare([]byte(strings.TrimSpace(credential)), []byte(token)) != 1 {
+				c.Header("WWW-Authenticate", `Bearer realm="metrics"`)
+				c.AbortWithStatus(http.StatusUnauthorized)
+				return
+			}
+		}
+
+		handler.ServeHTTP(c.Writer, c.Request)
+		c.Abort()
+	}
+}
@@ -0,0 +1,118 @@
+// Package metrics 提供 Prometheus 指标的采集与导出
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "pln"
+
+// 上传结果
+const (
+	UploadCreated   = "created"
+	UploadDuplicate = "duplicate"
+	UploadFailed    = "failed"
+)
+
+// 去重拒绝类型
+const (
+	DedupeExact = "exact" // 文件 Hash 完全一致
+	DedupePHash = "phash" // 与已有图片感知哈希相近
+)
+
+// Registry 应用指标注册表，包含 Go 运行时与进程指标
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP 请求数，按方法、路由与状态码区分",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP 请求耗时",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	uploads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "uploads_total",
+		Help:      "图片入库次数，按结果区分",
+	}, []string{"result"})
+
+	uploadBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "upload_bytes_total",
+		Help:      "成功入库的图片字节数",
+	})
+
+	dedupeRejections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "dedupe_rejections_total",
+		Help:      "因重复被拒绝的上传，exact 为 Hash 一致，phash 为相似图片",
+	}, []string{"kind"})
+
+	variantDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "variant_duration_seconds",
+		Help:      "缩略图、预览图等变体的生成耗时",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"variant"})
+
+	variantFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "variant_failures_total",
+		Help:      "变体生成失败次数",
+	}, []string{"variant"})
+
+	dbDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "数据库查询耗时，按语句类型区分",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation"})
+
+	dbErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "数据库查询失败次数（不含记录不存在），按语句类型区分",
+	}, []string{"operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration,
+		uploads, uploadBytes, dedupeRejections,
+		variantDuration, variantFailures,
+		dbDuration, dbErrors,
+	)
+}
+
+// ObserveUpload 记录一次入库结果，成功时累计字节数
+func ObserveUpload(result string, size int) {
+	uploads.WithLabelValues(result).Inc()
+	if result == UploadCreated {
+		uploadBytes.Add(float64(size))
+	}
+}
+
+// ObserveDedupe 记录一次因重复被拒绝的上传
+func ObserveDedupe(kind string) {
+	dedupeRejections.WithLabelValues(kind).Inc()
+}
+
+// ObserveVariant 记录一次变体生成的耗时与结果
+func ObserveVariant(variant string, elapsed time.Duration, err error) {
+	variantDuration.WithLabelValues(variant).Observe(elapsed.Seconds())
+	if err != nil {
+		variantFailures.WithLabelValues(variant).Inc()
+	}
+}
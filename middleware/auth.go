@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"strings"
+
+	"pln/models"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+)
+
+// gin.Context 中的键
+const (
+	AuthenticatedKey = "authenticated" // bool，请求携带了有效的 API Key 或会话
+	ActorKey         = "actor"         // string，操作者标识
+	ClientKey        = "client"        // string，客户端标识，用于点赞与收藏去重
+	ClientIssuedKey  = "client_issued" // bool，客户端标识由本次请求新签发
+	RoleKey          = "role"          // string，当前身份的角色
+	UserIDKey        = "user_id"       // uint，登录用户 ID，API Key 请求不设置
+	ScopesKey        = "scopes"        // []string，API Key 的权限范围，登录用户不设置
+	ClientIPKey      = "client_ip"     // string，客户端 IP，由 RealIP 设置
+)
+
+// SessionCookie 登录会话 Cookie
+const SessionCookie = "pln_session"
+
+// Identify 识别请求携带的 API Key，不拦截请求。
+// Key 有效时设置 AuthenticatedKey 与权限范围，并由权限范围推导角色供 HasRole 使用，以 Key 的 ID 作为操作者（apikey:<id>），轮换后保持不变，也避免明文出现在记录中。
+func Identify(scheme string, lookup func(key string) (*models.APIKey, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		prefix, key, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+		if ok && strings.EqualFold(prefix, scheme) && key != "" {
+			if apiKey, err := lookup(strings.TrimSpace(key)); err == nil {
+				scopes := apiKey.GetScopes()
+				c.Set(AuthenticatedKey, true)
+				c.Set(ActorKey, apiKey.Actor())
+				c.Set(RoleKey, models.ScopeRole(scopes))
+				c.Set(ScopesKey, scopes)
+			}
+		}
+		c.Next()
+	}
+}
+
+// Session 识别登录会话（需在 Identify 之后），不拦截请求。
+// 令牌来自 pln_session Cookie 或 Authorization: Bearer 头，已通过 API Key 认证的请求跳过。
+func Session(lookup func(token string) (*models.User, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool(AuthenticatedKey) {
+			c.Next()
+			return
+		}
+
+		if token := SessionToken(c); token != "" {
+			if user, err := lookup(token); err == nil {
+				c.Set(AuthenticatedKey, true)
+				c.Set(ActorKey, "user:"+user.Username)
+				c.Set(RoleKey, user.Role)
+				c.Set(UserIDKey, user.ID)
+			}
+		}
+		c.Next()
+	}
+}
+
+// SessionToken 请求携带的会话令牌，Authorization: Bearer 优先于 Cookie
+func SessionToken(c *gin.Context) string {
+	if prefix, token, ok := strings.Cut(c.GetHeader("Authorization"), " "); ok && strings.EqualFold(prefix, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	token, _ := c.Cookie(SessionCookie)
+	return token
+}
+
+// HasRole 当前身份的角色是否不低于 role
+func HasRole(c *gin.Context, role string) bool {
+	return c.GetBool(AuthenticatedKey) && models.RoleAtLeast(c.GetString(RoleKey), role)
+}
+
+// RequireRole 要求已认证且角色不低于 role，否则返回 401 或 403。
+// API Key 的角色由权限范围推导，路由还需通过 RequireScope 检查具体的权限范围
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool(AuthenticatedKey) {
+			response.Unauthorized("未登录或凭据无效").
+				WithRequestID(c.GetString("request_id")).
+				GJSON(c)
+			c.Abort()
+			return
+		}
+		if !HasRole(c, role) {
+			response.Forbidden("需要 " + role + " 或更高权限").
+				WithRequestID(c.GetString("request_id")).
+				GJSON(c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScope 要求 API Key 拥有 scope，否则返回 403；登录用户由 RequireRole 按角色检查，不受影响
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if scopes, ok := c.Get(ScopesKey); ok && !models.HasScope(scopes.([]string), scope) {
+			response.Forbidden("API Key 缺少 " + scope + " 权限").
+				WithRequestID(c.GetString("request_id")).
+				GJSON(c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"pln/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testKeys 测试用的 API Key，明文即 map 的键
+var testKeys = map[string][]string{
+	"read-key":  {models.ScopeRead},
+	"tag-key":   {models.ScopeTag},
+	"admin-key": {models.ScopeAdmin},
+}
+
+func lookupTestKey(plain string) (*models.APIKey, error) {
+	scopes, ok := testKeys[plain]
+	if !ok {
+		return nil, errors.New("unknown key")
+	}
+	key := &models.APIKey{ID: uint(len(plain))}
+	if err := key.SetScopes(scopes); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func lookupTestSession(token string) (*models.User, error) {
+	if token != "moderator-session" {
+		return nil, errors.New("unknown session")
+	}
+	return &models.User{ID: 1, Username: "mod", Role: models.RoleModerator}, nil
+}
+
+// authRequest 携带 Authorization 头的请求，header 为空时匿名
+func authRequest(header string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	if header != "" {
+		req.Header.Set("Authorization", header)
+	}
+	return req
+}
+
+func TestRequireRoleAndScope(t *testing.T) {
+	identify := []gin.HandlerFunc{Identify("X-API-Key", lookupTestKey), Session(lookupTestSession)}
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	tests := []struct {
+		name   string
+		header string
+		role   string
+		scope  string
+		status int
+		reason string // 403 时响应中应包含的原因
+	}{
+		{"anonymous", "", models.RoleModerator, models.ScopeTag, http.StatusUnauthorized, ""},
+		{"invalid key", "X-API-Key nope", models.RoleModerator, models.ScopeTag, http.StatusUnauthorized, ""},
+		{"key role too low", "X-API-Key read-key", models.RoleModerator, models.ScopeRead, http.StatusForbidden, "需要 moderator"},
+		{"key role enough, scope missing", "X-API-Key tag-key", models.RoleModerator, models.ScopeDelete, http.StatusForbidden, "缺少 delete"},
+		{"key role and scope", "X-API-Key tag-key", models.RoleModerator, models.ScopeTag, http.StatusOK, ""},
+		{"admin key has every scope", "X-API-Key admin-key", models.RoleAdmin, models.ScopeDelete, http.StatusOK, ""},
+		{"session is checked by role only", "Bearer moderator-session", models.RoleModerator, models.ScopeDelete, http.StatusOK, ""},
+		{"session role too low", "Bearer moderator-session", models.RoleAdmin, models.ScopeRead, http.StatusForbidden, "需要 admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlers := append(identify[:len(identify):len(identify)], RequireRole(tt.role), RequireScope(tt.scope), ok)
+			w := serveRequest(t, authRequest(tt.header), handlers...)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.reason != "" && !strings.Contains(w.Body.String(), tt.reason) {
+				t.Fatalf("body %s does not mention %q", w.Body, tt.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ClientCookie = "pln_cid"     // 浏览器客户端标识
+	ClientHeader = "X-Client-ID" // 无法保存 Cookie 的客户端使用该头携带相同的标识
+
+	clientCookieMaxAge = 2 * 365 * 24 * 3600
+)
+
+// ClientID 识别发起请求的客户端并设置 ClientKey（需在 Identify 之后）。
+// 已认证的请求以操作者作为客户端；匿名请求读取 pln_cid Cookie 或 X-Client-ID 头中的签名标识，
+// 写请求缺少有效标识时签发新的标识，通过 Cookie 与 X-Client-ID 响应头返回。
+func ClientID(secret []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool(AuthenticatedKey) {
+			c.Set(ClientKey, c.GetString(ActorKey))
+			c.Next()
+			return
+		}
+
+		token := c.GetHeader(ClientHeader)
+		if token == "" {
+			token, _ = c.Cookie(ClientCookie)
+		}
+		id, ok := verifyClientToken(secret, token)
+
+		if !ok && c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			var err error
+			if id, token, err = newClientToken(secret); err == nil {
+				ok = true
+				c.SetSameSite(http.SameSiteLaxMode)
+				c.SetCookie(ClientCookie, token, clientCookieMaxAge, "/", "", c.Request.TLS != nil, true)
+				c.Header(ClientHeader, token)
+				c.Set(ClientIssuedKey, true)
+			}
+		}
+
+		if ok {
+			c.Set(ClientKey, "cid:"+id)
+		}
+		c.Next()
+	}
+}
+
+// newClientToken 生成新的客户端标识，格式为 id.签名
+func newClientToken(secret []byte) (id, token string, err error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	id = hex.EncodeToString(b)
+	return id, id + "." + signClientID(secret, id), nil
+}
+
+func verifyClientToken(secret []byte, token string) (string, bool) {
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok || len(id) != 32 {
+		return "", false
+	}
+	if !hmac.Equal([]byte(sig), []byte(signClientID(secret, id))) {
+		return "", false
+	}
+	return id, true
+}
+
+func signClientID(secret []byte, id string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Yuelioi/gkit/web/response"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+)
+
+// 空闲令牌桶的清理间隔
+const bucketCleanupInterval = time.Minute
+
+// rateLimitChargeKey 上下文中追加扣除令牌的函数，由 RateLimit 设置
+const rateLimitChargeKey = "rate_limit_charge"
+
+// Quota 令牌桶配额：每 Per 时间补充 Requests 个令牌，桶容量为 Burst
+type Quota struct {
+	Requests int
+	Per      time.Duration
+	Burst    int // 为 0 时等于 Requests
+}
+
+func (q Quota) enabled() bool {
+	return q.Requests > 0 && q.Per > 0
+}
+
+func (q Quota) limit() rate.Limit {
+	return rate.Limit(float64(q.Requests) / q.Per.Seconds())
+}
+
+func (q Quota) burst() int {
+	if q.Burst > 0 {
+		return q.Burst
+	}
+	return q.Requests
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type rateLimiter struct {
+	name    string
+	ip      Quota
+	key     Quota
+	proxies []netip.Prefix
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// RateLimit 按客户端限流的令牌桶，每次调用拥有独立的预算。
+// 携带有效 API Key 的请求（需在 Identify 之后）按 Key 使用 key 配额，其余请求按客户端 IP 使用 ip 配额；
+// 配额为零值时不限制。响应附带 X-RateLimit-* 头，超出时返回 429 与 Retry-After。
+// 每个请求扣除一个令牌，一次处理多个条目的接口通过 ChargeRateLimit 按条目追加扣除。
+func RateLimit(name string, ip, key Quota, proxies []netip.Prefix) gin.HandlerFunc {
+	l := &rateLimiter{
+		name:    name,
+		ip:      ip,
+		key:     key,
+		proxies: proxies,
+		buckets: make(map[string]*bucket),
+	}
+	go l.cleanup()
+
+	return func(c *gin.Context) {
+		quota, id := l.ip, "ip:"+ClientIP(c, l.proxies)
+		if c.GetBool(AuthenticatedKey) {
+			quota, id = l.key, c.GetString(ActorKey)
+		}
+		if !quota.enabled() {
+			c.Next()
+			return
+		}
+
+		now := time.Now()
+		limiter := l.limiter(id, quota, now)
+		allowed := limiter.AllowN(now, 1)
+		tokens := limiter.TokensAt(now)
+		burst := quota.burst()
+
+		setHeaders := func(tokens float64) {
+			c.Header("X-RateLimit-Limit", strconv.Itoa(burst))
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(max(int(tokens), 0)))
+			c.Header("X-RateLimit-Reset", strconv.Itoa(seconds(float64(burst)-tokens, quota.limit())))
+		}
+		setHeaders(tokens)
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(max(seconds(1-tokens, quota.limit()), 1)))
+			log.Ctx(c.Request.Context()).Warn().
+				Str("limiter", l.name).
+				Str("client", id).
+				Msg("请求过于频繁")
+			response.TooManyRequests("请求过于频繁，请稍后再试").
+				WithRequestID(c.GetString("request_id")).
+				GJSON(c)
+			c.Abort()
+			return
+		}
+
+		c.Set(rateLimitChargeKey, func(n int) bool {
+			now := time.Now()
+			if !limiter.AllowN(now, n) {
+				log.Ctx(c.Request.Context()).Warn().
+					Str("limiter", l.name).
+					Str("client", id).
+					Int("n", n).
+					Msg("请求过于频繁")
+				return false
+			}
+			setHeaders(limiter.TokensAt(now))
+			return true
+		})
+		c.Next()
+	}
+}
+
+// ChargeRateLimit 在当前请求所用的令牌桶上追加扣除 n 个令牌，令牌不足时不扣除并返回 false。
+// 请求未经过 RateLimit 或不受限制时总是返回 true
+func ChargeRateLimit(c *gin.Context, n int) bool {
+	charge, ok := c.Get(rateLimitChargeKey)
+	if !ok || n <= 0 {
+		return true
+	}
+	return charge.(func(int) bool)(n)
+}
+
+// limiter 获取客户端的令牌桶，不存在时创建
+func (l *rateLimiter) limiter(id string, quota Quota, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[id]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(quota.limit(), quota.burst())}
+		l.buckets[id] = b
+	}
+	b.lastSeen = now
+	return b.limiter
+}
+
+// cleanup 定期移除已经补满的令牌桶，补满的桶与新建的桶等价
+func (l *rateLimiter) cleanup() {
+	ticker := time.NewTicker(bucketCleanupInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		l.mu.Lock()
+		for id, b := range l.buckets {
+			if b.limiter.TokensAt(now) >= float64(b.limiter.Burst()) {
+				delete(l.buckets, id)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
+// seconds 以 limit 的速率补充 tokens 个令牌所需的秒数（向上取整）
+func seconds(tokens float64, limit rate.Limit) int {
+	if tokens <= 0 || limit <= 0 {
+		return 0
+	}
+	return int(math.Ceil(tokens / float64(limit)))
+}
+
+// RealIP 按可信代理解析客户端 IP 并设置 ClientIPKey，供审计等记录使用
+func RealIP(proxies []netip.Prefix) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(ClientIPKey, ClientIP(c, proxies))
+		c.Next()
+	}
+}
+
+// ClientIP 客户端地址。只有直连地址属于可信代理时才采用 X-Forwarded-For，
+// 并从右向左取第一个不属于可信代理的地址，避免客户端伪造该头绕过限流。
+func ClientIP(c *gin.Context, proxies []netip.Prefix) string {
+	addr, err := netip.ParseAddr(c.RemoteIP())
+	if err != nil {
+		return c.RemoteIP()
+	}
+	addr = addr.Unmap()
+	if !trusted(addr, proxies) {
+		return addr.String()
+	}
+
+	hops := strings.Split(strings.Join(c.Request.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		addr = hop.Unmap()
+		if !trusted(addr, proxies) {
+			break
+		}
+	}
+	return addr.String()
+}
+
+func trusted(addr netip.Addr, proxies []netip.Prefix) bool {
+	for _, p := range proxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTrustedProxies 解析可信代理列表，支持单个 IP 与 CIDR
+func ParseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	proxies := make([]netip.Prefix, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if strings.Contains(v, "/") {
+			p, err := netip.ParsePrefix(v)
+			if err != nil {
+				return nil, fmt.Errorf("无效的可信代理 %q: %w", v, err)
+			}
+			proxies = append(proxies, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(v)
+		if err != nil {
+			return nil, fmt.Errorf("无效的可信代理 %q: %w", v, err)
+		}
+		addr = addr.Unmap()
+		proxies = append(proxies, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return proxies, nil
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// serve 以 handlers 处理一个来自 remoteAddr 的请求
+func serve(t *testing.T, remoteAddr string, handlers ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	return serveRequest(t, req, handlers...)
+}
+
+// serveRequest 以 handlers 处理 req
+func serveRequest(t *testing.T, req *http.Request, handlers ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	c, r := gin.CreateTestContext(w)
+	r.GET("/", handlers...)
+	c.Request = req
+	r.HandleContext(c)
+	return w
+}
+
+func TestChargeRateLimit(t *testing.T) {
+	limit := RateLimit("test", Quota{Requests: 3, Per: time.Minute}, Quota{}, nil)
+
+	var charged []bool
+	bulk := func(c *gin.Context) {
+		// 请求本身扣除一个令牌，剩余两个
+		charged = append(charged, ChargeRateLimit(c, 3), ChargeRateLimit(c, 2), ChargeRateLimit(c, 1))
+		c.Status(http.StatusOK)
+	}
+
+	w := serve(t, "192.0.2.1:1234", limit, bulk)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if want := []bool{false, true, false}; !slices.Equal(charged, want) {
+		t.Fatalf("charged = %v, want %v", charged, want)
+	}
+	if got := w.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Fatalf("X-RateLimit-Remaining = %q, want 0", got)
+	}
+
+	// 追加扣除的令牌计入同一个令牌桶
+	if w := serve(t, "192.0.2.1:1234", limit, bulk); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+
+	// 未经过 RateLimit 的请求不受限制
+	var ok bool
+	serve(t, "192.0.2.1:1234", func(c *gin.Context) { ok = ChargeRateLimit(c, 100) })
+	if !ok {
+		t.Fatal("ChargeRateLimit without RateLimit should allow")
+	}
+}
+
+func TestRateLimitKeying(t *testing.T) {
+	limit := RateLimit("test", Quota{Requests: 1, Per: time.Minute}, Quota{Requests: 2, Per: time.Minute}, nil)
+	identify := Identify("X-API-Key", lookupTestKey)
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	request := func(remoteAddr, header string) int {
+		req := authRequest(header)
+		req.RemoteAddr = remoteAddr
+		return serveRequest(t, req, identify, limit, ok).Code
+	}
+
+	// 匿名请求按 IP 计数
+	if code := request("192.0.2.1:1000", ""); code != http.StatusOK {
+		t.Fatalf("first anonymous request: %d", code)
+	}
+	if code := request("192.0.2.1:2000", ""); code != http.StatusTooManyRequests {
+		t.Fatalf("same IP, other port: %d, want 429", code)
+	}
+	if code := request("192.0.2.2:1000", ""); code != http.StatusOK {
+		t.Fatalf("other IP: %d", code)
+	}
+
+	// 认证请求按操作者计数，使用 key 配额，不受同一 IP 匿名请求的影响
+	for i := range 2 {
+		if code := request("192.0.2.1:1000", "X-API-Key read-key"); code != http.StatusOK {
+			t.Fatalf("key request %d: %d", i+1, code)
+		}
+	}
+	if code := request("192.0.2.3:1000", "X-API-Key read-key"); code != http.StatusTooManyRequests {
+		t.Fatalf("same key from another IP: %d, want 429", code)
+	}
+	if code := request("192.0.2.1:1000", "X-API-Key tag-key"); code != http.StatusOK {
+		t.Fatalf("other key from the same IP: %d", code)
+	}
+
+	// 无效的 Key 视为匿名，按 IP 计数
+	if code := request("192.0.2.1:1000", "X-API-Key nope"); code != http.StatusTooManyRequests {
+		t.Fatalf("invalid key: %d, want 429", code)
+	}
+}
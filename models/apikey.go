@@ -0,0 +1,137 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// API Key 权限范围
+const (
+	ScopeRead   = "read"   // 查看全部分级的内容与变更记录
+	ScopeUpload = "upload" // 上传、编辑作品与作品集
+	ScopeTag    = "tag"    // 编辑作品标签，维护标签规则
+	ScopeDelete = "delete" // 删除、审核作品
+	ScopeAdmin  = "admin"  // 拥有全部权限
+)
+
+// Scopes 全部权限范围
+var Scopes = []string{ScopeRead, ScopeUpload, ScopeTag, ScopeDelete, ScopeAdmin}
+
+// ValidScope 是否为有效的权限范围
+func ValidScope(scope string) bool {
+	return slices.Contains(Scopes, scope)
+}
+
+// APIKey 供自动化脚本使用的密钥，只保存 SHA-256
+type APIKey struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	Name       string     `gorm:"not null" json:"name"`
+	Prefix     string     `gorm:"index;not null" json:"prefix"` // 用于辨认密钥，轮换后会变化
+	KeyHash    string     `gorm:"uniqueIndex;not null" json:"-"`
+	Scopes     string     `gorm:"type:text" json:"-"` // JSON 字符串格式存储
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `gorm:"index" json:"revoked_at"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// Actor 操作者与客户端标识，使用轮换后不变的 ID，点赞、收藏、评论与修改记录不会因轮换而断开
+func (k *APIKey) Actor() string {
+	return APIKeyActor(k.ID)
+}
+
+// APIKeyActor ID 为 id 的 API Key 的操作者标识
+func APIKeyActor(id uint) string {
+	return "apikey:" + strconv.FormatUint(uint64(id), 10)
+}
+
+// GetScopes 解析权限范围
+func (k *APIKey) GetScopes() []string {
+	var scopes []string
+	if k.Scopes != "" {
+		_ = json.Unmarshal([]byte(k.Scopes), &scopes)
+	}
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return scopes
+}
+
+// SetScopes 设置权限范围（将 []string 转换为 JSON 字符串）
+func (k *APIKey) SetScopes(scopes []string) error {
+	data, err := json.Marshal(scopes)
+	if err != nil {
+		return err
+	}
+	k.Scopes = string(data)
+	return nil
+}
+
+// HasScope 是否拥有 scope，admin 拥有全部权限
+func HasScope(scopes []string, scope string) bool {
+	return slices.Contains(scopes, ScopeAdmin) || slices.Contains(scopes, scope)
+}
+
+// ScopeRole 权限范围对应的角色，取其中最高者，用于与登录用户共用角色检查
+func ScopeRole(scopes []string) string {
+	switch {
+	case slices.Contains(scopes, ScopeAdmin):
+		return RoleAdmin
+	case slices.Contains(scopes, ScopeDelete), slices.Contains(scopes, ScopeTag):
+		return RoleModerator
+	case slices.Contains(scopes, ScopeUpload):
+		return RoleUploader
+	default:
+		return RoleViewer
+	}
+}
+
+// APIKeyResponse API Key 信息，不包含密钥本身
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ToResponse 转换为响应格式
+func (k *APIKey) ToResponse() APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.GetScopes(),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedBy:  k.CreatedBy,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// APIKeyCreateRequest 创建 API Key 请求
+type APIKeyCreateRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required" enums:"read,upload,tag,delete,admin"`
+	ExpiresAt *time.Time `json:"expires_at"` // 为空时永不过期
+}
+
+// APIKeyCreated 创建或轮换后的 API Key，明文密钥只在此时返回
+type APIKeyCreated struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
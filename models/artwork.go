@@ -9,19 +9,32 @@ import (
 
 type Artwork struct {
 	ID           uint           `gorm:"primaryKey" json:"id"`
-	FileID       string         `gorm:"uniqueIndex:idx_file_id;not null" json:"-"` // 本地备份文件ID
-	URL          string         `json:"url"`                                       // 原图链接
-	ThumbnailURL string         `json:"thumbnail_url"`                             // 缩略图URL
-	PreviewURL   string         `json:"preview_url"`                               // 预览图URL
-	Hash         string         `gorm:"index:idx_hash;not null" json:"hash"`       // 文件哈希
+	FileID       string         `gorm:"uniqueIndex:idx_file_id;not null" json:"-"`     // 本地备份文件ID
+	URL          string         `json:"url"`                                           // 原图链接
+	ThumbnailURL string         `json:"thumbnail_url"`                                 // 缩略图URL
+	PreviewURL   string         `json:"preview_url"`                                   // 预览图URL
+	SourceURL    string         `json:"source_url"`                                    // 来源链接
+	Artist       string         `gorm:"index:idx_artist" json:"artist"`                // 作者
+	Title        string         `json:"title"`                                         // 标题
+	Description  string         `gorm:"type:text" json:"description"`                  // 描述
+	License      string         `json:"license"`                                       // 授权协议，如 CC BY 4.0
+	Rating       string         `gorm:"index;not null;default:safe" json:"rating"`     // 内容分级：safe、questionable、explicit
+	Status       string         `gorm:"index;not null;default:approved" json:"status"` // 审核状态：pending、approved、rejected
+	RejectReason string         `json:"reject_reason"`                                 // 驳回原因
+	ModeratedBy  string         `json:"moderated_by"`                                  // 审核人
+	ModeratedAt  *time.Time     `json:"moderated_at"`                                  // 审核时间
+	Hash         string         `gorm:"index:idx_hash;not null" json:"hash"`           // 文件哈希
 	PHash        int64          `gorm:"index:idx_phash;column:phash;" json:"phash"`
 	Views        int            `gorm:"default:0" json:"views"`
 	Likes        int            `gorm:"default:0" json:"likes"`
 	Bookmarks    int            `gorm:"default:0" json:"bookmarks"`
-	Tags         string         `gorm:"type:text" json:"tags"` // JSON 字符串格式存储
+	CommentCount int            `gorm:"default:0" json:"comment_count"` // 未隐藏的评论数
+	Tags         string         `gorm:"type:text" json:"tags"`          // JSON 字符串格式存储
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Snippet string `gorm:"-" json:"-"` // 全文检索的高亮摘要，仅检索时填充
 }
 
 // TableName 指定表名
@@ -37,28 +50,114 @@ type ArtworkCreateRequest struct {
 	Hash         string   `json:"hash"`
 	ThumbnailURL string   `json:"thumbnail_url"`
 	PreviewURL   string   `json:"preview_url"`
+	SourceURL    string   `json:"source_url"`
+	Artist       string   `json:"artist"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	License      string   `json:"license"`
+	Rating       string   `json:"rating"` // 为空时使用 safe
+	Status       string   `json:"status"` // 为空时直接公开（approved）
 	Tags         []string `json:"tags"`
 }
 
+// ArtworkImportURLRequest 从网络地址导入请求
+type ArtworkImportURLRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Artist      string   `json:"artist"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	License     string   `json:"license"`
+	Rating      string   `json:"rating" enums:"safe,questionable,explicit"` // 为空时使用实例默认分级
+	Tags        []string `json:"tags"`
+}
+
 // ArtworkUpdateRequest 更新请求
+// 来源与署名字段为 nil 时不修改，传空字符串表示清空
 type ArtworkUpdateRequest struct {
-	URL          string   `json:"url"`
-	ThumbnailURL string   `json:"thumbnail_url"`
-	Tags         []string `json:"tags"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	SourceURL    *string   `json:"source_url"`
+	Artist       *string   `json:"artist"`
+	Title        *string   `json:"title"`
+	Description  *string   `json:"description"`
+	License      *string   `json:"license"`
+	Rating       *string   `json:"rating" enums:"safe,questionable,explicit"`
+	Tags         *[]string `json:"tags"` // nil 时不修改，空数组表示清空
+}
+
+// ArtworkTagPatchRequest 单个作品的标签增量修改
+type ArtworkTagPatchRequest struct {
+	Add    []string  `json:"add"`
+	Remove []string  `json:"remove"`
+	Set    *[]string `json:"set"`   // 先替换为该列表，再执行 add / remove
+	Clear  bool      `json:"clear"` // 清空标签，不能与 set 同时使用
+}
+
+// ArtworkQuery 作品检索条件，与作品列表的过滤参数一致
+type ArtworkQuery struct {
+	Tags    []string `json:"tags"`
+	Artist  string   `json:"artist"`
+	License string   `json:"license"`
+	Q       string   `json:"q"`
+}
+
+// Filters 转换为列表接口使用的过滤条件
+func (q *ArtworkQuery) Filters() map[string]any {
+	filters := make(map[string]any)
+	if len(q.Tags) > 0 {
+		filters["tags"] = q.Tags
+	}
+	if q.Artist != "" {
+		filters["artist"] = q.Artist
+	}
+	if q.License != "" {
+		filters["license"] = q.License
+	}
+	if q.Q != "" {
+		filters["q"] = q.Q
+	}
+	return filters
+}
+
+// BulkTagRequest 批量标签操作：ids 与 query 二选一；replace 不能与 add / remove 同时使用。
+// query 不限审核状态，待审核的作品同样会被修改
+type BulkTagRequest struct {
+	IDs     []uint        `json:"ids"`
+	Query   *ArtworkQuery `json:"query"`
+	Add     []string      `json:"add"`
+	Remove  []string      `json:"remove"`
+	Replace *[]string     `json:"replace"`
+}
+
+// BulkTagResult 批量标签操作结果
+type BulkTagResult struct {
+	Matched    int    `json:"matched"`     // 命中的作品数
+	Updated    int    `json:"updated"`     // 标签发生变化的作品数
+	UpdatedIDs []uint `json:"updated_ids"` // 标签发生变化的作品 ID
 }
 
 // 返回响应
 type ArtworkResponse struct {
-	ID           uint      `json:"id"`
-	URL          string    `json:"url"`
-	ThumbnailURL string    `json:"thumbnail_url"`
-	PreviewURL   string    `json:"preview_url"`
-	Views        int       `json:"views"`
-	Likes        int       `json:"likes"`
-	Bookmarks    int       `json:"bookmarks"`
-	Tags         []string  `json:"tags"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uint        `json:"id"`
+	URL          string      `json:"url"`
+	ThumbnailURL string      `json:"thumbnail_url"`
+	PreviewURL   string      `json:"preview_url"`
+	SourceURL    string      `json:"source_url"`
+	Artist       string      `json:"artist"`
+	Title        string      `json:"title"`
+	Description  string      `json:"description"`
+	License      string      `json:"license"`
+	Rating       string      `json:"rating"`
+	Status       string      `json:"status"`
+	Snippet      string      `json:"snippet,omitempty"` // 关键词检索时的高亮摘要（HTML，已转义）
+	Views        int         `json:"views"`
+	Likes        int         `json:"likes"`
+	Bookmarks    int         `json:"bookmarks"`
+	CommentCount int         `json:"comment_count"`
+	Tags         []string    `json:"tags"`
+	TagDetails   []TagDetail `json:"tag_details"` // 结构化标签，命名空间标签在前
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
 }
 
 // 转换为响应格式
@@ -71,15 +170,30 @@ func (a *Artwork) ToResponse() ArtworkResponse {
 		tags = []string{}
 	}
 
+	details := make([]TagDetail, 0, len(tags))
+	for _, tag := range tags {
+		details = append(details, TagDetail{Name: tag, Value: tag})
+	}
+
 	return ArtworkResponse{
 		ID:           a.ID,
 		URL:          a.URL,
 		ThumbnailURL: a.ThumbnailURL,
 		PreviewURL:   a.PreviewURL,
+		SourceURL:    a.SourceURL,
+		Artist:       a.Artist,
+		Title:        a.Title,
+		Description:  a.Description,
+		License:      a.License,
+		Rating:       a.Rating,
+		Status:       a.Status,
+		Snippet:      a.Snippet,
 		Views:        a.Views,
 		Likes:        a.Likes,
 		Bookmarks:    a.Bookmarks,
+		CommentCount: a.CommentCount,
 		Tags:         tags,
+		TagDetails:   details,
 		CreatedAt:    a.CreatedAt,
 		UpdatedAt:    a.UpdatedAt,
 	}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// 审计操作
+const (
+	AuditArtworkUpdate  = "artwork.update"  // 修改作品信息
+	AuditArtworkTags    = "artwork.tags"    // 修改、批量修改或回滚标签
+	AuditArtworkDelete  = "artwork.delete"  // 删除作品
+	AuditArtworkApprove = "artwork.approve" // 审核通过
+	AuditArtworkReject  = "artwork.reject"  // 驳回
+
+	AuditAPIKeyCreate = "apikey.create" // 创建 API Key
+	AuditAPIKeyRotate = "apikey.rotate" // 轮换 API Key
+	AuditAPIKeyRevoke = "apikey.revoke" // 吊销 API Key
+	AuditUserCreate   = "user.create"   // 创建用户，包括单点登录首次登录
+	AuditUserUpdate   = "user.update"   // 修改用户角色、禁用状态或重置密码
+	AuditUserDelete   = "user.delete"   // 删除用户
+)
+
+// auditIgnored 不计入差异的字段
+var auditIgnored = map[string]bool{"updated_at": true, "tag_details": true, "snippet": true}
+
+// AuditLog 管理操作的审计记录
+type AuditLog struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Action    string    `gorm:"index;not null" json:"action"`
+	Actor     string    `gorm:"index" json:"actor"`
+	ArtworkID uint      `gorm:"index;not null" json:"artwork_id"` // 非作品操作为 0
+	Target    string    `gorm:"index" json:"target,omitempty"`    // 非作品操作的对象，如 apikey:3、user:5
+	RequestID string    `gorm:"index" json:"request_id"`
+	IP        string    `json:"ip"`
+	Diff      string    `gorm:"type:text" json:"-"` // 字段差异（JSON 对象）
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditChange 单个字段的变化，新增或删除时另一侧为 null
+type AuditChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// NewAuditLog 比较 before 与 after 的 JSON 字段创建审计记录，任一侧可以为 nil；
+// 没有差异时返回 nil
+func NewAuditLog(artworkID uint, action string, before, after any) (*AuditLog, error) {
+	b, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := map[string]AuditChange{}
+	for key, from := range b {
+		if to, ok := a[key]; !ok || !reflect.DeepEqual(from, to) {
+			diff[key] = AuditChange{From: from, To: a[key]}
+		}
+	}
+	for key, to := range a {
+		if _, ok := b[key]; !ok {
+			diff[key] = AuditChange{To: to}
+		}
+	}
+	if len(diff) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(diff)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{ArtworkID: artworkID, Action: action, Diff: string(data)}, nil
+}
+
+func auditFields(v any) (map[string]any, error) {
+	fields := map[string]any{}
+	if v == nil {
+		return fields, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key := range auditIgnored {
+		delete(fields, key)
+	}
+	return fields, nil
+}
+
+// ToResponse 转换为响应，解析字段差异
+func (l *AuditLog) ToResponse() AuditLogResponse {
+	diff := map[string]AuditChange{}
+	if l.Diff != "" {
+		_ = json.Unmarshal([]byte(l.Diff), &diff)
+	}
+	return AuditLogResponse{AuditLog: *l, Diff: diff}
+}
+
+// AuditLogResponse 审计记录响应
+type AuditLogResponse struct {
+	AuditLog
+	Diff map[string]AuditChange `json:"diff"`
+}
+
+// AuditLogQuery 审计记录查询条件，零值表示不过滤
+type AuditLogQuery struct {
+	ArtworkID uint
+	Target    string
+	Actor     string
+	Action    string
+	RequestID string
+	Since     *time.Time
+	Until     *time.Time
+	Offset    int
+	Limit     int
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// BookmarkFolder 收藏夹，按客户端标识区分所有者
+type BookmarkFolder struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Owner     string    `gorm:"uniqueIndex:idx_bookmark_folder;not null" json:"-"` // 与 Reaction.Client 相同
+	Name      string    `gorm:"uniqueIndex:idx_bookmark_folder;not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (BookmarkFolder) TableName() string {
+	return "bookmark_folders"
+}
+
+// BookmarkFolderResponse 收藏夹及其中的作品数
+type BookmarkFolderResponse struct {
+	BookmarkFolder
+	Count int64 `json:"count"`
+}
+
+// BookmarkFolderRequest 创建或重命名收藏夹
+type BookmarkFolderRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// BookmarkItem 收藏的作品
+type BookmarkItem struct {
+	ArtworkResponse
+	FolderID     *uint     `json:"folder_id"`
+	BookmarkedAt time.Time `json:"bookmarked_at"`
+}
+
+// BookmarkMoveRequest 将收藏移动到收藏夹，folder_id 为空表示移出收藏夹
+type BookmarkMoveRequest struct {
+	FolderID *uint `json:"folder_id"`
+}
+
+// LocalBookmark 前端 localStorage 中 artwork:bookmarks 的单条记录
+type LocalBookmark struct {
+	ArtworkID  uint  `json:"artworkId"`
+	Bookmarked bool  `json:"bookmarked"`
+	Timestamp  int64 `json:"timestamp"` // 毫秒
+}
+
+// BookmarkImportResult 导入本地收藏的结果
+type BookmarkImportResult struct {
+	Imported int    `json:"imported"` // 新增的收藏
+	Existing int    `json:"existing"` // 已收藏，保持不变
+	Skipped  []uint `json:"skipped"`  // 作品不存在或未公开
+	Deferred []uint `json:"deferred"` // 超出限流未导入，稍后重新导入即可
+}
@@ -0,0 +1,97 @@
+package models
+
+import "time"
+
+// Collection 手动整理的作品集，作品按 CollectionItem.Position 排序
+type Collection struct {
+	ID             uint      `gorm:"primarykey" json:"id"`
+	Title          string    `gorm:"not null" json:"title"`
+	Description    string    `gorm:"type:text" json:"description"`
+	CoverArtworkID *uint     `json:"cover_artwork_id"` // 封面作品，为空时使用第一个作品
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Collection) TableName() string {
+	return "collections"
+}
+
+// CollectionItem 作品集中的一个作品
+type CollectionItem struct {
+	ID           uint      `gorm:"primarykey" json:"id"`
+	CollectionID uint      `gorm:"uniqueIndex:idx_collection_artwork;index:idx_collection_position,priority:1;not null" json:"collection_id"`
+	ArtworkID    uint      `gorm:"uniqueIndex:idx_collection_artwork;index;not null" json:"artwork_id"`
+	Position     int       `gorm:"index:idx_collection_position,priority:2;not null" json:"position"` // 从 0 开始
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (CollectionItem) TableName() string {
+	return "collection_items"
+}
+
+// CollectionStats 作品集及其统计信息（不含已删除的作品）
+type CollectionStats struct {
+	Collection     `gorm:"embedded"`
+	ArtworkCount   int64 `gorm:"column:artwork_count"`
+	FirstArtworkID *uint `gorm:"column:first_artwork_id"`
+}
+
+// CollectionCover 作品集封面
+type CollectionCover struct {
+	ArtworkID    uint   `json:"artwork_id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	PreviewURL   string `json:"preview_url"`
+}
+
+// CollectionResponse 作品集响应
+type CollectionResponse struct {
+	ID             uint             `json:"id"`
+	Title          string           `json:"title"`
+	Description    string           `json:"description"`
+	CoverArtworkID *uint            `json:"cover_artwork_id"`
+	Cover          *CollectionCover `json:"cover"` // 实际使用的封面，作品集为空时为 null
+	ArtworkCount   int64            `json:"artwork_count"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+}
+
+// CollectionRequest 创建作品集请求
+type CollectionRequest struct {
+	Title          string `json:"title" binding:"required"`
+	Description    string `json:"description"`
+	CoverArtworkID *uint  `json:"cover_artwork_id"`
+	ArtworkIDs     []uint `json:"artwork_ids"` // 初始作品，按顺序加入
+}
+
+// CollectionUpdateRequest 更新作品集请求，cover_artwork_id 为 0 表示恢复默认封面
+type CollectionUpdateRequest struct {
+	Title          *string `json:"title"`
+	Description    *string `json:"description"`
+	CoverArtworkID *uint   `json:"cover_artwork_id"`
+}
+
+// CollectionItemsRequest 批量加入作品请求
+type CollectionItemsRequest struct {
+	ArtworkIDs []uint `json:"artwork_ids" binding:"required"`
+	Position   *int   `json:"position"` // 插入位置，从 0 开始；为空时追加到末尾
+}
+
+// CollectionRemoveRequest 批量移除作品请求
+type CollectionRemoveRequest struct {
+	ArtworkIDs []uint `json:"artwork_ids" binding:"required"`
+}
+
+// CollectionReorderRequest 调整顺序请求：按新顺序给出作品集中全部作品
+type CollectionReorderRequest struct {
+	ArtworkIDs []uint `json:"artwork_ids" binding:"required"`
+}
+
+// CollectionItemsResult 加入或移除作品的结果
+type CollectionItemsResult struct {
+	Added        int   `json:"added,omitempty"`   // 新加入的作品数（已存在的会被忽略）
+	Removed      int   `json:"removed,omitempty"` // 移除的作品数
+	ArtworkCount int64 `json:"artwork_count"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Comment 作品评论，正文保存原始的 Markdown 文本，渲染在返回时进行
+type Comment struct {
+	ID        uint           `gorm:"primarykey" json:"id"`
+	ArtworkID uint           `gorm:"index;not null" json:"artwork_id"`
+	Author    string         `gorm:"not null" json:"author"`         // 显示名称，登录用户为用户名
+	Client    string         `gorm:"index;not null" json:"-"`        // 客户端标识，与 Reaction.Client 相同，用于作者删除自己的评论
+	IP        string         `json:"-"`                              // 发表时的客户端 IP
+	Body      string         `gorm:"type:text;not null" json:"body"` // Markdown 原文
+	Hidden    bool           `gorm:"index;not null;default:false" json:"hidden"`
+	HiddenBy  string         `json:"hidden_by"`
+	HiddenAt  *time.Time     `json:"hidden_at"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// TableName 指定表名
+func (Comment) TableName() string {
+	return "comments"
+}
+
+// CommentResponse 评论及渲染后的 HTML
+type CommentResponse struct {
+	Comment
+	HTML string `json:"html"` // 渲染后的正文，已转义，只包含少量安全标签
+	Mine bool   `json:"mine"` // 是否为当前客户端发表，可以删除
+}
+
+// CommentCreateRequest 发表评论请求
+type CommentCreateRequest struct {
+	Body   string `json:"body" binding:"required"`
+	Author string `json:"author"` // 未登录时的昵称，为空时显示为匿名；登录用户固定使用用户名
+}
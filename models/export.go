@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// ExportVersion 导出格式版本
+const ExportVersion = 1
+
+// ExportManifest 导出归档中的 manifest.json
+type ExportManifest struct {
+	Version    int            `json:"version"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Filters    map[string]any `json:"filters,omitempty"` // 导出时使用的过滤条件
+	Items      []ExportItem   `json:"items"`
+}
+
+// ExportItem 导出的单个作品
+type ExportItem struct {
+	File        string    `json:"file"` // 归档内的原图路径
+	Hash        string    `json:"hash"`
+	PHash       int64     `json:"phash"`
+	SourceURL   string    `json:"source_url,omitempty"`
+	Artist      string    `json:"artist,omitempty"`
+	Title       string    `json:"title,omitempty"`
+	Description string    `json:"description,omitempty"`
+	License     string    `json:"license,omitempty"`
+	Rating      string    `json:"rating,omitempty"`
+	Status      string    `json:"status,omitempty"` // 审核状态，旧版本的清单没有该字段，视为已公开
+	Tags        []string  `json:"tags"`
+	Views       int       `json:"views"`
+	Likes       int       `json:"likes"`
+	Bookmarks   int       `json:"bookmarks"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// 入库结果状态
+const (
+	IngestStatusCreated   = "created"
+	IngestStatusDuplicate = "duplicate"
+	IngestStatusSimilar   = "similar"
+	IngestStatusRejected  = "rejected"
+)
+
+// IngestResult 单个文件的入库结果
+type IngestResult struct {
+	File       string `json:"file"`
+	Status     string `json:"status"`                // created / duplicate / similar / rejected
+	ArtworkID  uint   `json:"artwork_id,omitempty"`  // 新建作品的 ID
+	ExistingID uint   `json:"existing_id,omitempty"` // 重复或相似时已存在作品的 ID
+	Reason     string `json:"reason,omitempty"`      // 被拒绝的原因
+}
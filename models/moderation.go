@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// 作品审核状态
+const (
+	StatusPending  = "pending"  // 待审核，不在公开接口中出现
+	StatusApproved = "approved" // 已公开
+	StatusRejected = "rejected" // 已驳回，作品被删除且文件已清理
+
+	StatusAll = "all" // 过滤条件：不限审核状态
+)
+
+// 审核操作
+const (
+	ModerationApprove = "approve"
+	ModerationReject  = "reject"
+)
+
+// ModerationItem 审核队列中的作品
+type ModerationItem struct {
+	ArtworkResponse
+	RejectReason string     `json:"reject_reason,omitempty"`
+	ModeratedBy  string     `json:"moderated_by,omitempty"`
+	ModeratedAt  *time.Time `json:"moderated_at,omitempty"`
+}
+
+// ToModerationItem 转换为审核队列响应
+func (a *Artwork) ToModerationItem() ModerationItem {
+	return ModerationItem{
+		ArtworkResponse: a.ToResponse(),
+		RejectReason:    a.RejectReason,
+		ModeratedBy:     a.ModeratedBy,
+		ModeratedAt:     a.ModeratedAt,
+	}
+}
+
+// ModerationRejectRequest 驳回请求
+type ModerationRejectRequest struct {
+	Reason string `json:"reason"`
+}
+
+// ModerationBulkRequest 批量审核请求
+type ModerationBulkRequest struct {
+	IDs    []uint `json:"ids" binding:"required"`
+	Action string `json:"action" binding:"required" enums:"approve,reject"`
+	Reason string `json:"reason"` // 驳回原因，仅 reject 时使用
+}
+
+// ModerationResult 批量审核结果
+type ModerationResult struct {
+	Approved []uint `json:"approved"` // 审核通过的作品
+	Rejected []uint `json:"rejected"` // 驳回的作品
+	Skipped  []uint `json:"skipped"`  // 不存在或不在待审核状态的作品
+}
@@ -0,0 +1,27 @@
+package models
+
+import "slices"
+
+// 内容分级，按从低到高排列
+const (
+	RatingSafe         = "safe"
+	RatingQuestionable = "questionable"
+	RatingExplicit     = "explicit"
+)
+
+// Ratings 全部分级，从低到高
+var Ratings = []string{RatingSafe, RatingQuestionable, RatingExplicit}
+
+// ValidRating 是否为有效分级
+func ValidRating(rating string) bool {
+	return slices.Contains(Ratings, rating)
+}
+
+// RatingsUpTo 不高于 max 的全部分级；max 无效时只返回 safe
+func RatingsUpTo(max string) []string {
+	i := slices.Index(Ratings, max)
+	if i < 0 {
+		return []string{RatingSafe}
+	}
+	return slices.Clone(Ratings[:i+1])
+}
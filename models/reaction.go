@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// 互动类型
+const (
+	ReactionLike     = "like"
+	ReactionBookmark = "bookmark"
+)
+
+// Reaction 客户端对作品的一次点赞或收藏，同一客户端对同一作品每种互动只有一条记录
+type Reaction struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ArtworkID uint      `gorm:"uniqueIndex:idx_reaction,priority:2;not null" json:"artwork_id"`
+	Client    string    `gorm:"uniqueIndex:idx_reaction,priority:1;not null" json:"client"` // 客户端标识，如 cid:…、apikey:3
+	Kind      string    `gorm:"uniqueIndex:idx_reaction,priority:3;not null" json:"kind"`
+	FolderID  *uint     `gorm:"index" json:"folder_id"` // 收藏所在的收藏夹，为空表示未分类
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Reaction) TableName() string {
+	return "reactions"
+}
+
+// ReactionState 当前客户端对作品的互动状态
+type ReactionState struct {
+	ArtworkID  uint `json:"artwork_id"`
+	Liked      bool `json:"liked"`
+	Bookmarked bool `json:"bookmarked"`
+}
+
+// ReactionResult 点赞或收藏操作的结果
+type ReactionResult struct {
+	ReactionState
+	Changed   bool `json:"changed"` // 为 false 表示状态本来如此，计数未变化
+	Likes     int  `json:"likes"`
+	Bookmarks int  `json:"bookmarks"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// SmartCollection 智能作品集：保存的检索条件，作品在查询时动态计算
+type SmartCollection struct {
+	ID          uint         `gorm:"primarykey" json:"id"`
+	Name        string       `gorm:"uniqueIndex;not null" json:"name"` // 用于 URL 的名称，如 wallpapers
+	Title       string       `json:"title"`
+	Description string       `gorm:"type:text" json:"description"`
+	Query       ArtworkQuery `gorm:"type:text;serializer:json" json:"query"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (SmartCollection) TableName() string {
+	return "smart_collections"
+}
+
+// SmartCollectionResponse 智能作品集响应
+type SmartCollectionResponse struct {
+	SmartCollection
+	ArtworkCount int64 `json:"artwork_count"` // 当前匹配的作品数
+}
+
+// SmartCollectionRequest 创建智能作品集请求
+type SmartCollectionRequest struct {
+	Name        string       `json:"name" binding:"required"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Query       ArtworkQuery `json:"query"`
+}
+
+// SmartCollectionUpdateRequest 更新智能作品集请求（名称不可修改）
+type SmartCollectionUpdateRequest struct {
+	Title       *string       `json:"title"`
+	Description *string       `json:"description"`
+	Query       *ArtworkQuery `json:"query"`
+}
@@ -0,0 +1,124 @@
+package models
+
+import "time"
+
+// TagAlias 标签别名：写入与检索时 Alias 会被替换为 Tag
+type TagAlias struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Alias     string    `gorm:"uniqueIndex;not null" json:"alias"` // 别名，如 cats、neko
+	Tag       string    `gorm:"index;not null" json:"tag"`         // 规范标签，如 cat
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (TagAlias) TableName() string {
+	return "tag_aliases"
+}
+
+// TagImplication 标签蕴含：带有 Tag 的作品自动补充 Implies
+type TagImplication struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Tag       string    `gorm:"uniqueIndex:idx_tag_implication;not null" json:"tag"`     // 如 persian_cat
+	Implies   string    `gorm:"uniqueIndex:idx_tag_implication;not null" json:"implies"` // 如 cat
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (TagImplication) TableName() string {
+	return "tag_implications"
+}
+
+// TagNamespace 标签命名空间，如 artist:foo 中的 artist
+type TagNamespace struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	Name      string    `gorm:"uniqueIndex;not null" json:"name"` // 小写字母、数字或下划线
+	Title     string    `json:"title"`                            // 显示名称
+	Color     string    `json:"color"`                            // 显示颜色，如 #e67e22
+	SortOrder int       `gorm:"default:0" json:"sort_order"`      // 越小越靠前
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (TagNamespace) TableName() string {
+	return "tag_namespaces"
+}
+
+// DefaultTagNamespaces 首次启动时创建的命名空间
+var DefaultTagNamespaces = []TagNamespace{
+	{Name: "artist", Title: "作者", Color: "#e67e22", SortOrder: 10},
+	{Name: "character", Title: "角色", Color: "#27ae60", SortOrder: 20},
+	{Name: "series", Title: "作品", Color: "#8e44ad", SortOrder: 30},
+	{Name: "meta", Title: "元信息", Color: "#7f8c8d", SortOrder: 40},
+}
+
+// TagDetail 结构化标签
+type TagDetail struct {
+	Name      string `json:"name"`                // 完整标签，如 artist:foo
+	Namespace string `json:"namespace,omitempty"` // 命名空间，普通标签为空
+	Value     string `json:"value"`               // 去掉命名空间后的部分
+	Color     string `json:"color,omitempty"`
+	SortOrder int    `json:"sort_order"`
+}
+
+// TagCount 标签及其使用次数
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// TagUsage 标签目录中的条目
+type TagUsage struct {
+	TagDetail
+	Count int64 `json:"count"` // 使用该标签的作品数
+}
+
+// TagSuggestion 自动补全建议
+type TagSuggestion struct {
+	TagUsage
+	Alias string `json:"alias,omitempty"` // 通过别名匹配时的别名
+}
+
+// TagListQuery 标签目录查询条件
+type TagListQuery struct {
+	Prefix    string // 标签前缀
+	Namespace string // 只列出该命名空间下的标签
+	Sort      string // count 或 name
+	Desc      bool
+	Ratings   []string // 只统计这些分级的作品，nil 表示不限制
+	Offset    int
+	Limit     int
+}
+
+// TagNamespaceRequest 创建命名空间请求
+type TagNamespaceRequest struct {
+	Name      string `json:"name" binding:"required"`
+	Title     string `json:"title"`
+	Color     string `json:"color"`
+	SortOrder int    `json:"sort_order"`
+}
+
+// TagNamespaceUpdateRequest 更新命名空间请求（名称不可修改）
+type TagNamespaceUpdateRequest struct {
+	Title     *string `json:"title"`
+	Color     *string `json:"color"`
+	SortOrder *int    `json:"sort_order"`
+}
+
+// TagAliasRequest 创建别名请求
+type TagAliasRequest struct {
+	Alias string `json:"alias" binding:"required"`
+	Tag   string `json:"tag" binding:"required"`
+}
+
+// TagImplicationRequest 创建蕴含请求
+type TagImplicationRequest struct {
+	Tag     string `json:"tag" binding:"required"`
+	Implies string `json:"implies" binding:"required"`
+}
+
+// TagReapplyResult 重新应用标签规则的结果
+type TagReapplyResult struct {
+	Scanned int `json:"scanned"` // 检查的作品数
+	Updated int `json:"updated"` // 标签发生变化的作品数
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// 标签修改来源
+const (
+	TagRevisionUpdate  = "update"  // PUT /artworks/:id
+	TagRevisionPatch   = "patch"   // PATCH /artworks/:id/tags
+	TagRevisionBulk    = "bulk"    // 批量修改
+	TagRevisionReapply = "reapply" // 重新应用标签规则
+	TagRevisionRevert  = "revert"  // 回滚
+)
+
+// TagRevision 作品标签的一次修改记录
+type TagRevision struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	ArtworkID uint      `gorm:"index;not null" json:"artwork_id"`
+	Action    string    `gorm:"index;not null" json:"action"`
+	Actor     string    `gorm:"index" json:"actor"`      // 操作者，如 apikey:3、cli
+	Before    string    `gorm:"type:text" json:"before"` // 修改前的标签（JSON 数组）
+	After     string    `gorm:"type:text" json:"after"`  // 修改后的标签（JSON 数组）
+	RevertOf  *uint     `json:"revert_of,omitempty"`     // 回滚时被回滚的记录
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 指定表名
+func (TagRevision) TableName() string {
+	return "tag_revisions"
+}
+
+// NewTagRevision 根据修改前后的标签创建修改记录
+func NewTagRevision(artworkID uint, action, actor string, before, after []string) (*TagRevision, error) {
+	b, err := json.Marshal(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := json.Marshal(after)
+	if err != nil {
+		return nil, err
+	}
+	return &TagRevision{
+		ArtworkID: artworkID,
+		Action:    action,
+		Actor:     actor,
+		Before:    string(b),
+		After:     string(a),
+	}, nil
+}
+
+// BeforeTags 修改前的标签
+func (r *TagRevision) BeforeTags() []string {
+	return decodeTags(r.Before)
+}
+
+// AfterTags 修改后的标签
+func (r *TagRevision) AfterTags() []string {
+	return decodeTags(r.After)
+}
+
+// ToResponse 转换为响应，并计算增删的标签
+func (r *TagRevision) ToResponse() TagRevisionResponse {
+	before, after := r.BeforeTags(), r.AfterTags()
+
+	added := []string{}
+	for _, tag := range after {
+		if !slices.Contains(before, tag) {
+			added = append(added, tag)
+		}
+	}
+	removed := []string{}
+	for _, tag := range before {
+		if !slices.Contains(after, tag) {
+			removed = append(removed, tag)
+		}
+	}
+
+	return TagRevisionResponse{
+		ID:        r.ID,
+		ArtworkID: r.ArtworkID,
+		Action:    r.Action,
+		Actor:     r.Actor,
+		Before:    before,
+		After:     after,
+		Added:     added,
+		Removed:   removed,
+		RevertOf:  r.RevertOf,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func decodeTags(data string) []string {
+	var tags []string
+	if data != "" {
+		_ = json.Unmarshal([]byte(data), &tags)
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	return tags
+}
+
+// TagRevisionResponse 标签修改记录响应
+type TagRevisionResponse struct {
+	ID        uint      `json:"id"`
+	ArtworkID uint      `json:"artwork_id"`
+	Action    string    `json:"action"`
+	Actor     string    `json:"actor"`
+	Before    []string  `json:"before"`
+	After     []string  `json:"after"`
+	Added     []string  `json:"added"`
+	Removed   []string  `json:"removed"`
+	RevertOf  *uint     `json:"revert_of,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TagRevisionQuery 最近修改查询条件
+type TagRevisionQuery struct {
+	ArtworkID uint   // 只看该作品
+	Actor     string // 只看该操作者
+	Action    string // 只看该来源
+	Offset    int
+	Limit     int
+}
+
+// TagRevertRequest 回滚请求
+type TagRevertRequest struct {
+	RevisionID uint `json:"revision_id" binding:"required"` // 恢复到该记录修改前的标签
+}
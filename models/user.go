@@ -0,0 +1,111 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// 用户角色，权限依次递增
+const (
+	RoleViewer    = "viewer"    // 登录后可查看全部分级的内容
+	RoleUploader  = "uploader"  // 上传无需审核，可编辑作品与作品集
+	RoleModerator = "moderator" // 审核、删除作品，维护标签规则
+	RoleAdmin     = "admin"     // 用户管理、备份与导入导出
+)
+
+// Roles 全部角色，按权限从低到高排列
+var Roles = []string{RoleViewer, RoleUploader, RoleModerator, RoleAdmin}
+
+// ValidRole 是否为有效的角色
+func ValidRole(role string) bool {
+	return slices.Contains(Roles, role)
+}
+
+// RoleAtLeast role 的权限是否不低于 min，无效的角色没有任何权限
+func RoleAtLeast(role, min string) bool {
+	i := slices.Index(Roles, role)
+	return i >= 0 && i >= slices.Index(Roles, min)
+}
+
+// User 登录用户
+type User struct {
+	ID           uint       `gorm:"primarykey" json:"id"`
+	Username     string     `gorm:"uniqueIndex;not null" json:"username"`
+	PasswordHash string     `gorm:"not null" json:"-"`
+	Role         string     `gorm:"not null;default:viewer" json:"role"`
+	Disabled     bool       `gorm:"not null;default:false" json:"disabled"` // 禁用后无法登录，已有会话失效
+	ExternalID   *string    `gorm:"uniqueIndex" json:"external_id"`         // 单点登录用户的 issuer 与 subject，本地用户为空
+	LastLoginAt  *time.Time `json:"last_login_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (User) TableName() string {
+	return "users"
+}
+
+// Session 登录会话，只保存令牌的 SHA-256
+type Session struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	TokenHash string    `gorm:"uniqueIndex;not null" json:"-"`
+	UserID    uint      `gorm:"index;not null" json:"user_id"`
+	UserAgent string    `json:"user_agent"`
+	IP        string    `json:"ip"`
+	ExpiresAt time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// LoginRequest 登录请求
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// LoginResponse 登录结果。浏览器使用 Cookie 即可，其他客户端以 Authorization: Bearer <token> 携带令牌
+type LoginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      User      `json:"user"`
+}
+
+// UserCreateRequest 创建用户请求
+type UserCreateRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+	Role     string `json:"role" enums:"viewer,uploader,moderator,admin"` // 为空时为 viewer
+}
+
+// UserUpdateRequest 更新用户请求，字段为 nil 时不修改；修改密码或禁用会使该用户的会话全部失效
+type UserUpdateRequest struct {
+	Password *string `json:"password"`
+	Role     *string `json:"role" enums:"viewer,uploader,moderator,admin"`
+	Disabled *bool   `json:"disabled"`
+}
+
+// ExternalIdentity 单点登录得到的身份，角色由用户组映射
+type ExternalIdentity struct {
+	ExternalID string
+	Username   string
+	Role       string
+}
+
+// OIDCInfo 单点登录配置，供前端显示登录入口
+type OIDCInfo struct {
+	Enabled  bool   `json:"enabled"`
+	Name     string `json:"name"`
+	LoginURL string `json:"login_url,omitempty"`
+}
+
+// CurrentIdentity 当前请求的身份
+type CurrentIdentity struct {
+	Actor  string   `json:"actor"` // 操作者，如 user:alice、apikey:3
+	Role   string   `json:"role"`
+	User   *User    `json:"user,omitempty"`   // API Key 请求为空
+	Scopes []string `json:"scopes,omitempty"` // API Key 的权限范围，登录用户为空
+}
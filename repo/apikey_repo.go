@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"time"
+
+	"pln/models"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepo interface {
+	// List 列出全部 API Key，包括已吊销的
+	List() ([]models.APIKey, error)
+	Count() (int64, error)
+	GetByID(id uint) (*models.APIKey, error)
+	GetByHash(keyHash string) (*models.APIKey, error)
+	Create(key *models.APIKey) error
+	Update(key *models.APIKey) error
+	// TouchLastUsed 更新最后使用时间
+	TouchLastUsed(id uint, t time.Time) error
+	// CreateAuditLog 审计记录，与密钥修改放在同一事务中写入
+	CreateAuditLog(log *models.AuditLog) error
+
+	// Transaction 在事务中执行 fn，fn 内应使用传入的 repo
+	Transaction(fn func(repo APIKeyRepo) error) error
+}
+
+type apiKeyRepo struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepo(db *gorm.DB) APIKeyRepo {
+	return &apiKeyRepo{db: db}
+}
+
+func (r *apiKeyRepo) List() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := r.db.Order("id").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepo) Count() (int64, error) {
+	var total int64
+	err := r.db.Model(&models.APIKey{}).Count(&total).Error
+	return total, err
+}
+
+func (r *apiKeyRepo) GetByID(id uint) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.First(&key, id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepo) GetByHash(keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepo) Create(key *models.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *apiKeyRepo) Update(key *models.APIKey) error {
+	return r.db.Save(key).Error
+}
+
+func (r *apiKeyRepo) TouchLastUsed(id uint, t time.Time) error {
+	return r.db.Model(&models.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", t).Error
+}
+
+func (r *apiKeyRepo) Transaction(fn func(repo APIKeyRepo) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&apiKeyRepo{db: tx})
+	})
+}
@@ -1,6 +1,9 @@
 package repo
 
 import (
+	"encoding/json"
+	"strings"
+
 	"pln/models"
 
 	"gorm.io/gorm"
@@ -10,24 +13,52 @@ type ArtworkRepo interface {
 	Create(artwork *models.Artwork) error
 	GetByID(id uint) (*models.Artwork, error)
 	GetByHash(hash string, artwork *models.Artwork) error
+	GetByIDs(ids []uint) ([]models.Artwork, error)
 	GetAll(offset, limit int, filters map[string]any) ([]models.Artwork, int64, error)
+	FindAll(filters map[string]any, limit int) ([]models.Artwork, error)
+	Count(filters map[string]any) (int64, error)
+	CountByStatus() (map[string]int64, error)
 	GetAllWithPHash() ([]models.Artwork, error)
 	GetRandom(limit int, filters map[string]any) ([]models.Artwork, error)
 	Update(id uint, artwork *models.Artwork) error
+	UpdateFields(id uint, fields map[string]any) error
+	UpdateColumns(id uint, columns map[string]any) error
 	Delete(id uint) error
 	IncrementViews(id uint) error
 	IncrementLikes(id uint) error
 	DecrementLikes(id uint) error
 	IncrementBookmarks(id uint) error
 	DecrementBookmarks(id uint) error
+
+	// 标签修改记录，与作品修改放在同一事务中写入
+	CreateTagRevision(revision *models.TagRevision) error
+	GetTagRevision(id uint) (*models.TagRevision, error)
+	ListTagRevisions(query models.TagRevisionQuery) ([]models.TagRevision, int64, error)
+
+	// CreateAuditLog 审计记录，与作品修改放在同一事务中写入
+	CreateAuditLog(log *models.AuditLog) error
+
+	// 点赞与收藏记录，与计数放在同一事务中修改
+	AddReaction(reaction *models.Reaction) (bool, error)
+	RemoveReaction(artworkID uint, client, kind string) (bool, error)
+	ListReactions(client string, artworkIDs []uint) ([]models.Reaction, error)
+
+	// ListByStatus 审核队列，按审核状态列出作品
+	ListByStatus(status string, offset, limit int) ([]models.Artwork, int64, error)
+	// GetRejectedByHash 驳回的作品已被删除，入库去重时需单独查询
+	GetRejectedByHash(hash string) (*models.Artwork, error)
+
+	// Transaction 在事务中执行 fn，fn 内应使用传入的 repo
+	Transaction(fn func(repo ArtworkRepo) error) error
 }
 
 type artworkRepo struct {
-	db *gorm.DB
+	db  *gorm.DB
+	fts bool // 全文索引是否可用
 }
 
 func NewArtworkRepo(db *gorm.DB) ArtworkRepo {
-	return &artworkRepo{db: db}
+	return &artworkRepo{db: db, fts: ftsReady(db)}
 }
 
 func (r *artworkRepo) Create(artwork *models.Artwork) error {
@@ -43,6 +74,17 @@ func (r *artworkRepo) GetByID(id uint) (*models.Artwork, error) {
 	return &artwork, nil
 }
 
+func (r *artworkRepo) GetByIDs(ids []uint) ([]models.Artwork, error) {
+	var artworks []models.Artwork
+	if len(ids) == 0 {
+		return artworks, nil
+	}
+	if err := r.db.Where("id IN ?", ids).Order("id").Find(&artworks).Error; err != nil {
+		return nil, err
+	}
+	return artworks, nil
+}
+
 func (r *artworkRepo) GetByHash(hash string, artwork *models.Artwork) error {
 	return r.db.Where("hash = ?", hash).First(artwork).Error
 }
@@ -59,55 +101,84 @@ func (r *artworkRepo) GetAll(offset, limit int, filters map[string]any) ([]model
 	var artworks []models.Artwork
 	var total int64
 
-	query := r.db.Model(&models.Artwork{})
-
-	if tags, ok := filters["tags"]; ok {
-		switch v := tags.(type) {
-		case string:
-			if v != "" {
-				query = query.Where("tags LIKE ?", "%"+v+"%")
-			}
-		case []string:
-			for _, tag := range v {
-				if tag != "" {
-					query = query.Where("tags LIKE ?", "%"+tag+"%")
-				}
-			}
-		}
-	}
+	query, ranked := r.applyFilters(r.db.Model(&models.Artwork{}), filters)
 
 	// 计算总数
 	if err := query.Model(&models.Artwork{}).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
+	// 全文检索时按相关度排序并生成高亮摘要
+	if ranked {
+		var hits []searchHit
+		if err := query.
+			Select("artworks.*, snippet(artworks_fts, -1, char(2), char(3), '…', 64) AS snippet").
+			Order(ftsRank).Order("artworks.created_at DESC").
+			Offset(offset).Limit(limit).Find(&hits).Error; err != nil {
+			return nil, 0, err
+		}
+
+		artworks = make([]models.Artwork, len(hits))
+		for i, hit := range hits {
+			artworks[i] = hit.Artwork
+			artworks[i].Snippet = highlightSnippet(hit.Snippet)
+		}
+		return artworks, total, nil
+	}
+
 	// 获取数据
-	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&artworks).Error; err != nil {
+	if err := query.Offset(offset).Limit(limit).Order("artworks.created_at DESC").Find(&artworks).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return artworks, total, nil
 }
 
-func (r *artworkRepo) GetRandom(limit int, filters map[string]any) ([]models.Artwork, error) {
+// FindAll 查询满足过滤条件的全部作品，最多 limit 条
+func (r *artworkRepo) FindAll(filters map[string]any, limit int) ([]models.Artwork, error) {
 	var artworks []models.Artwork
 
-	query := r.db.Model(&models.Artwork{})
-
-	if tags, ok := filters["tags"]; ok {
-		switch v := tags.(type) {
-		case string:
-			if v != "" {
-				query = query.Where("tags LIKE ?", "%"+v+"%")
-			}
-		case []string:
-			for _, tag := range v {
-				if tag != "" {
-					query = query.Where("tags LIKE ?", "%"+tag+"%")
-				}
-			}
-		}
+	query, _ := r.applyFilters(r.db.Model(&models.Artwork{}), filters)
+	if err := query.Order("artworks.id").Limit(limit).Find(&artworks).Error; err != nil {
+		return nil, err
+	}
+
+	return artworks, nil
+}
+
+func (r *artworkRepo) Count(filters map[string]any) (int64, error) {
+	var total int64
+	query, _ := r.applyFilters(r.db.Model(&models.Artwork{}), filters)
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// CountByStatus 各审核状态的作品数，不含已删除的作品
+func (r *artworkRepo) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Total  int64
+	}
+	if err := r.db.Model(&models.Artwork{}).
+		Select("status, COUNT(*) AS total").
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Total
 	}
+	return counts, nil
+}
+
+func (r *artworkRepo) GetRandom(limit int, filters map[string]any) ([]models.Artwork, error) {
+	var artworks []models.Artwork
+
+	query, _ := r.applyFilters(r.db.Model(&models.Artwork{}), filters)
 
 	// 随机排序并限制数量
 	if err := query.Order("RANDOM()").Limit(limit).Find(&artworks).Error; err != nil {
@@ -117,10 +188,100 @@ func (r *artworkRepo) GetRandom(limit int, filters map[string]any) ([]models.Art
 	return artworks, nil
 }
 
+// applyFilters 应用列表与随机接口共用的过滤条件，
+// 返回值 ranked 表示是否使用了全文索引（可按相关度排序）
+func (r *artworkRepo) applyFilters(query *gorm.DB, filters map[string]any) (*gorm.DB, bool) {
+	// 标签精确匹配：tags 列为 JSON 数组，按带引号的 JSON 字符串查找；
+	// ns:* 匹配该命名空间下的任意标签
+	var tags []string
+	switch v := filters["tags"].(type) {
+	case string:
+		tags = []string{v}
+	case []string:
+		tags = v
+	}
+	for _, tag := range tags {
+		if tag == "" {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(tag, ":*"); ok && prefix != "" {
+			encoded, _ := json.Marshal(prefix + ":")
+			pattern := strings.TrimSuffix(string(encoded), `"`)
+			query = query.Where("artworks.tags LIKE ? ESCAPE '\\'", "%"+escapeLike(pattern)+"%")
+			continue
+		}
+		encoded, _ := json.Marshal(tag)
+		query = query.Where("artworks.tags LIKE ? ESCAPE '\\'", "%"+escapeLike(string(encoded))+"%")
+	}
+
+	// 手动作品集中的作品
+	if id, ok := filters["collection_id"].(uint); ok {
+		query = query.Where("artworks.id IN (?)",
+			r.db.Model(&models.CollectionItem{}).Select("artwork_id").Where("collection_id = ?", id))
+	}
+
+	// 审核状态：默认只包含已公开的作品
+	switch status, _ := filters["status"].(string); status {
+	case models.StatusAll:
+	case "":
+		query = query.Where("artworks.status = ?", models.StatusApproved)
+	default:
+		query = query.Where("artworks.status = ?", status)
+	}
+
+	if ratings, ok := filters["ratings"].([]string); ok && len(ratings) > 0 {
+		query = query.Where("artworks.rating IN ?", ratings)
+	}
+
+	if artist, ok := filters["artist"].(string); ok && artist != "" {
+		query = query.Where("artworks.artist = ?", artist)
+	}
+	if license, ok := filters["license"].(string); ok && license != "" {
+		query = query.Where("artworks.license = ?", license)
+	}
+
+	// 关键词：匹配标题、描述、作者与标签
+	q, _ := filters["q"].(string)
+	if q = strings.TrimSpace(q); q == "" {
+		return query, false
+	}
+
+	if match := ftsQuery(q); r.fts && match != "" {
+		return query.
+			Joins("JOIN artworks_fts ON artworks_fts.rowid = artworks.id").
+			Where("artworks_fts MATCH ?", match), true
+	}
+
+	// 无法使用全文索引时逐词 LIKE 匹配
+	for _, term := range strings.Fields(q) {
+		like := "%" + escapeLike(term) + "%"
+		query = query.Where(
+			"(artworks.title LIKE ? ESCAPE '\\' OR artworks.description LIKE ? ESCAPE '\\' OR artworks.artist LIKE ? ESCAPE '\\' OR artworks.tags LIKE ? ESCAPE '\\')",
+			like, like, like, like,
+		)
+	}
+	return query, false
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 func (r *artworkRepo) Update(id uint, artwork *models.Artwork) error {
 	return r.db.Model(&models.Artwork{}).Where("id = ?", id).Updates(artwork).Error
 }
 
+// UpdateFields 按列更新（允许写入零值），并刷新 updated_at
+func (r *artworkRepo) UpdateFields(id uint, fields map[string]any) error {
+	return r.db.Model(&models.Artwork{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// UpdateColumns 直接更新指定列（不触发钩子，也不自动刷新 updated_at）
+func (r *artworkRepo) UpdateColumns(id uint, columns map[string]any) error {
+	return r.db.Model(&models.Artwork{}).Where("id = ?", id).UpdateColumns(columns).Error
+}
+
 func (r *artworkRepo) Delete(id uint) error {
 	return r.db.Delete(&models.Artwork{}, id).Error
 }
@@ -148,3 +309,9 @@ func (r *artworkRepo) IncrementBookmarks(id uint) error {
 func (r *artworkRepo) DecrementBookmarks(id uint) error {
 	return r.db.Model(&models.Artwork{}).Where("id = ?", id).Update("bookmarks", gorm.Expr("CASE WHEN bookmarks > 0 THEN bookmarks - 1 ELSE 0 END")).Error
 }
+
+func (r *artworkRepo) Transaction(fn func(repo ArtworkRepo) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&artworkRepo{db: tx, fts: r.fts})
+	})
+}
@@ -0,0 +1,85 @@
+package repo
+
+import (
+	"time"
+
+	"pln/models"
+
+	"gorm.io/gorm"
+)
+
+// AuditWriter 写入审计记录，由各业务 repo 实现，以便与修改放在同一事务中
+type AuditWriter interface {
+	CreateAuditLog(log *models.AuditLog) error
+}
+
+type AuditRepo interface {
+	// List 按时间倒序列出审计记录
+	List(query models.AuditLogQuery) ([]models.AuditLog, int64, error)
+	// DeleteBefore 删除早于 t 的审计记录，返回删除的条数
+	DeleteBefore(t time.Time) (int64, error)
+}
+
+type auditRepo struct {
+	db *gorm.DB
+}
+
+func NewAuditRepo(db *gorm.DB) AuditRepo {
+	return &auditRepo{db: db}
+}
+
+func (r *auditRepo) List(query models.AuditLogQuery) ([]models.AuditLog, int64, error) {
+	logs := []models.AuditLog{}
+	var total int64
+
+	db := r.db.Model(&models.AuditLog{})
+	if query.ArtworkID != 0 {
+		db = db.Where("artwork_id = ?", query.ArtworkID)
+	}
+	if query.Target != "" {
+		db = db.Where("target = ?", query.Target)
+	}
+	if query.Actor != "" {
+		db = db.Where("actor = ?", query.Actor)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.RequestID != "" {
+		db = db.Where("request_id = ?", query.RequestID)
+	}
+	if query.Since != nil {
+		db = db.Where("created_at >= ?", *query.Since)
+	}
+	if query.Until != nil {
+		db = db.Where("created_at < ?", *query.Until)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Order("id DESC").Offset(query.Offset).Limit(query.Limit).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+func (r *auditRepo) DeleteBefore(t time.Time) (int64, error) {
+	result := r.db.Where("created_at < ?", t).Delete(&models.AuditLog{})
+	return result.RowsAffected, result.Error
+}
+
+// CreateAuditLog 与作品修改在同一事务中写入审计记录
+func (r *artworkRepo) CreateAuditLog(log *models.AuditLog) error {
+	return r.db.Create(log).Error
+}
+
+// CreateAuditLog 与 API Key 修改在同一事务中写入审计记录
+func (r *apiKeyRepo) CreateAuditLog(log *models.AuditLog) error {
+	return r.db.Create(log).Error
+}
+
+// CreateAuditLog 与用户修改在同一事务中写入审计记录
+func (r *userRepo) CreateAuditLog(log *models.AuditLog) error {
+	return r.db.Create(log).Error
+}
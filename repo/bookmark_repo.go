@@ -0,0 +1,142 @@
+package repo
+
+import (
+	"time"
+
+	"pln/models"
+
+	"gorm.io/gorm"
+)
+
+// BookmarkRow 收藏列表的查询结果
+type BookmarkRow struct {
+	models.Artwork
+	FolderID     *uint
+	BookmarkedAt time.Time
+}
+
+type BookmarkRepo interface {
+	ListFolders(owner string) ([]models.BookmarkFolderResponse, error)
+	GetFolder(owner string, id uint) (*models.BookmarkFolder, error)
+	GetFolderByName(owner, name string) (*models.BookmarkFolder, error)
+	CreateFolder(folder *models.BookmarkFolder) error
+	UpdateFolder(folder *models.BookmarkFolder) error
+	// DeleteFolder 删除收藏夹，其中的收藏变为未分类
+	DeleteFolder(owner string, id uint) error
+
+	// ListBookmarks 按收藏时间倒序列出已公开的收藏作品。
+	// folderID 为 nil 时列出全部，指向 0 时只列出未分类的收藏
+	ListBookmarks(owner string, folderID *uint, ratings []string, offset, limit int) ([]BookmarkRow, int64, error)
+	// MoveBookmark 设置收藏所在的收藏夹，返回该收藏是否存在
+	MoveBookmark(owner string, artworkID uint, folderID *uint) (bool, error)
+
+	// Transaction 在事务中执行 fn，fn 内应使用传入的 repo
+	Transaction(fn func(repo BookmarkRepo) error) error
+}
+
+type bookmarkRepo struct {
+	db *gorm.DB
+}
+
+func NewBookmarkRepo(db *gorm.DB) BookmarkRepo {
+	return &bookmarkRepo{db: db}
+}
+
+func (r *bookmarkRepo) ListFolders(owner string) ([]models.BookmarkFolderResponse, error) {
+	folders := []models.BookmarkFolderResponse{}
+	err := r.db.Model(&models.BookmarkFolder{}).
+		Select("bookmark_folders.*, COUNT(reactions.id) AS count").
+		Joins("LEFT JOIN reactions ON reactions.folder_id = bookmark_folders.id AND reactions.client = bookmark_folders.owner AND reactions.kind = ?", models.ReactionBookmark).
+		Where("bookmark_folders.owner = ?", owner).
+		Group("bookmark_folders.id").
+		Order("bookmark_folders.name").
+		Scan(&folders).Error
+	if err != nil {
+		return nil, err
+	}
+	return folders, nil
+}
+
+func (r *bookmarkRepo) GetFolder(owner string, id uint) (*models.BookmarkFolder, error) {
+	var folder models.BookmarkFolder
+	if err := r.db.Where("owner = ? AND id = ?", owner, id).First(&folder).Error; err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *bookmarkRepo) GetFolderByName(owner, name string) (*models.BookmarkFolder, error) {
+	var folder models.BookmarkFolder
+	if err := r.db.Where("owner = ? AND name = ?", owner, name).First(&folder).Error; err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *bookmarkRepo) CreateFolder(folder *models.BookmarkFolder) error {
+	return r.db.Create(folder).Error
+}
+
+func (r *bookmarkRepo) UpdateFolder(folder *models.BookmarkFolder) error {
+	return r.db.Save(folder).Error
+}
+
+func (r *bookmarkRepo) DeleteFolder(owner string, id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("owner = ? AND id = ?", owner, id).Delete(&models.BookmarkFolder{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&models.Reaction{}).
+			Where("client = ? AND folder_id = ?", owner, id).
+			Update("folder_id", nil).Error
+	})
+}
+
+func (r *bookmarkRepo) ListBookmarks(owner string, folderID *uint, ratings []string, offset, limit int) ([]BookmarkRow, int64, error) {
+	var rows []BookmarkRow
+	var total int64
+
+	query := r.db.Model(&models.Artwork{}).
+		Joins("JOIN reactions ON reactions.artwork_id = artworks.id").
+		Where("reactions.client = ? AND reactions.kind = ? AND artworks.status = ?", owner, models.ReactionBookmark, models.StatusApproved)
+	switch {
+	case folderID == nil:
+	case *folderID == 0:
+		query = query.Where("reactions.folder_id IS NULL")
+	default:
+		query = query.Where("reactions.folder_id = ?", *folderID)
+	}
+	if len(ratings) > 0 {
+		query = query.Where("artworks.rating IN ?", ratings)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.
+		Select("artworks.*, reactions.folder_id AS folder_id, reactions.created_at AS bookmarked_at").
+		Order("reactions.created_at DESC, reactions.id DESC").
+		Offset(offset).Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+func (r *bookmarkRepo) MoveBookmark(owner string, artworkID uint, folderID *uint) (bool, error) {
+	result := r.db.Model(&models.Reaction{}).
+		Where("client = ? AND artwork_id = ? AND kind = ?", owner, artworkID, models.ReactionBookmark).
+		Update("folder_id", folderID)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *bookmarkRepo) Transaction(fn func(repo BookmarkRepo) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&bookmarkRepo{db: tx})
+	})
+}
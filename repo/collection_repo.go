@@ -0,0 +1,165 @@
+package repo
+
+import (
+	"time"
+
+	"pln/models"
+
+	"gorm.io/gorm"
+)
+
+type CollectionRepo interface {
+	// List 与 GetByID 的统计信息只计入分级在 ratings 中的作品，ratings 为空时不限制
+	List(offset, limit int, ratings []string) ([]models.CollectionStats, int64, error)
+	GetByID(id uint, ratings []string) (*models.CollectionStats, error)
+	Create(collection *models.Collection) error
+	Update(collection *models.Collection) error
+	Delete(id uint) error
+
+	// ItemIDs 作品集中全部作品 ID（按顺序，与 ListArtworks 一致，不含已删除或未审核通过的作品）
+	ItemIDs(collectionID uint) ([]uint, error)
+	// SetItems 以 artworkIDs 的顺序重写作品集内容，不在其中的记录（包括已删除作品的记录）一并清除
+	SetItems(collectionID uint, artworkIDs []uint) error
+	// ListArtworks 按顺序分页列出作品集中的作品（不含已删除的作品）
+	ListArtworks(collectionID uint, offset, limit int, ratings []string) ([]models.Artwork, int64, error)
+
+	// Transaction 在事务中执行 fn，fn 内应使用传入的 repo
+	Transaction(fn func(repo CollectionRepo) error) error
+}
+
+type collectionRepo struct {
+	db *gorm.DB
+}
+
+func NewCollectionRepo(db *gorm.DB) CollectionRepo {
+	return &collectionRepo{db: db}
+}
+
+// collectionStatsSelect 附带作品数与第一个作品，已删除或未公开的作品不计入
+const collectionStatsSelect = `collections.*,
+(SELECT COUNT(*) FROM collection_items i JOIN artworks a ON a.id = i.artwork_id AND a.deleted_at IS NULL AND a.status = 'approved'
+	WHERE i.collection_id = collections.id AND (? OR a.rating IN ?)) AS artwork_count,
+(SELECT i.artwork_id FROM collection_items i JOIN artworks a ON a.id = i.artwork_id AND a.deleted_at IS NULL AND a.status = 'approved'
+	WHERE i.collection_id = collections.id AND (? OR a.rating IN ?) ORDER BY i.position LIMIT 1) AS first_artwork_id`
+
+// selectStats 选择作品集及统计信息
+func (r *collectionRepo) selectStats(ratings []string) *gorm.DB {
+	all := len(ratings) == 0
+	if all {
+		ratings = []string{""}
+	}
+	return r.db.Model(&models.Collection{}).Select(collectionStatsSelect, all, ratings, all, ratings)
+}
+
+func (r *collectionRepo) List(offset, limit int, ratings []string) ([]models.CollectionStats, int64, error) {
+	var collections []models.CollectionStats
+	var total int64
+
+	if err := r.db.Model(&models.Collection{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.selectStats(ratings).
+		Order("collections.updated_at DESC, collections.id DESC").
+		Offset(offset).Limit(limit).
+		Scan(&collections).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return collections, total, nil
+}
+
+func (r *collectionRepo) GetByID(id uint, ratings []string) (*models.CollectionStats, error) {
+	var collections []models.CollectionStats
+	err := r.selectStats(ratings).
+		Where("collections.id = ?", id).
+		Limit(1).
+		Scan(&collections).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(collections) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &collections[0], nil
+}
+
+func (r *collectionRepo) Create(collection *models.Collection) error {
+	return r.db.Create(collection).Error
+}
+
+func (r *collectionRepo) Update(collection *models.Collection) error {
+	return r.db.Save(collection).Error
+}
+
+func (r *collectionRepo) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Collection{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("collection_id = ?", id).Delete(&models.CollectionItem{}).Error
+	})
+}
+
+func (r *collectionRepo) ItemIDs(collectionID uint) ([]uint, error) {
+	ids := []uint{}
+	err := r.db.Model(&models.CollectionItem{}).
+		Joins("JOIN artworks ON artworks.id = collection_items.artwork_id AND artworks.deleted_at IS NULL").
+		Where("collection_items.collection_id = ? AND artworks.status = ?", collectionID, models.StatusApproved).
+		Order("collection_items.position").
+		Pluck("collection_items.artwork_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *collectionRepo) SetItems(collectionID uint, artworkIDs []uint) error {
+	if err := r.db.Where("collection_id = ?", collectionID).Delete(&models.CollectionItem{}).Error; err != nil {
+		return err
+	}
+	if len(artworkIDs) > 0 {
+		items := make([]models.CollectionItem, len(artworkIDs))
+		for i, id := range artworkIDs {
+			items[i] = models.CollectionItem{CollectionID: collectionID, ArtworkID: id, Position: i}
+		}
+		if err := r.db.CreateInBatches(items, 200).Error; err != nil {
+			return err
+		}
+	}
+
+	// 内容变化时更新作品集的修改时间
+	return r.db.Model(&models.Collection{}).
+		Where("id = ?", collectionID).
+		Update("updated_at", time.Now()).Error
+}
+
+func (r *collectionRepo) ListArtworks(collectionID uint, offset, limit int, ratings []string) ([]models.Artwork, int64, error) {
+	var artworks []models.Artwork
+	var total int64
+
+	query := r.db.Model(&models.Artwork{}).
+		Joins("JOIN collection_items ON collection_items.artwork_id = artworks.id").
+		Where("collection_items.collection_id = ? AND artworks.status = ?", collectionID, models.StatusApproved)
+	if len(ratings) > 0 {
+		query = query.Where("artworks.rating IN ?", ratings)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("collection_items.position").Offset(offset).Limit(limit).Find(&artworks).Error; err != nil {
+		return nil, 0, err
+	}
+	return artworks, total, nil
+}
+
+func (r *collectionRepo) Transaction(fn func(repo CollectionRepo) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&collectionRepo{db: tx})
+	})
+}
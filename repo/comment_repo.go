@@ -0,0 +1,78 @@
+package repo
+
+import (
+	"pln/models"
+
+	"gorm.io/gorm"
+)
+
+type CommentRepo interface {
+	// List 按发表时间列出作品的评论，withHidden 为 false 时不包含已隐藏的评论
+	List(artworkID uint, withHidden bool, offset, limit int) ([]models.Comment, int64, error)
+	GetByID(id uint) (*models.Comment, error)
+	Create(comment *models.Comment) error
+	Update(comment *models.Comment) error
+	Delete(id uint) error
+	// AdjustCount 调整作品的评论数，不会小于 0
+	AdjustCount(artworkID uint, delta int) error
+
+	// Transaction 在事务中执行 fn，fn 内应使用传入的 repo
+	Transaction(fn func(repo CommentRepo) error) error
+}
+
+type commentRepo struct {
+	db *gorm.DB
+}
+
+func NewCommentRepo(db *gorm.DB) CommentRepo {
+	return &commentRepo{db: db}
+}
+
+func (r *commentRepo) List(artworkID uint, withHidden bool, offset, limit int) ([]models.Comment, int64, error) {
+	query := r.db.Model(&models.Comment{}).Where("artwork_id = ?", artworkID)
+	if !withHidden {
+		query = query.Where("hidden = ?", false)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	comments := []models.Comment{}
+	if err := query.Order("created_at, id").Offset(offset).Limit(limit).Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+func (r *commentRepo) GetByID(id uint) (*models.Comment, error) {
+	var comment models.Comment
+	if err := r.db.Where("id = ?", id).First(&comment).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *commentRepo) Create(comment *models.Comment) error {
+	return r.db.Create(comment).Error
+}
+
+func (r *commentRepo) Update(comment *models.Comment) error {
+	return r.db.Save(comment).Error
+}
+
+func (r *commentRepo) Delete(id uint) error {
+	return r.db.Delete(&models.Comment{}, id).Error
+}
+
+func (r *commentRepo) AdjustCount(artworkID uint, delta int) error {
+	return r.db.Model(&models.Artwork{}).Where("id = ?", artworkID).
+		Update("comment_count", gorm.Expr("MAX(comment_count + ?, 0)", delta)).Error
+}
+
+func (r *commentRepo) Transaction(fn func(repo CommentRepo) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&commentRepo{db: tx})
+	})
+}
@@ -0,0 +1,38 @@
+package repo
+
+import "pln/models"
+
+// GetRejectedByHash 查找 Hash 相同且已被驳回（已删除）的作品
+func (r *artworkRepo) GetRejectedByHash(hash string) (*models.Artwork, error) {
+	var artwork models.Artwork
+	err := r.db.Unscoped().
+		Where("hash = ? AND status = ?", hash, models.StatusRejected).
+		Order("moderated_at DESC").
+		First(&artwork).Error
+	if err != nil {
+		return nil, err
+	}
+	return &artwork, nil
+}
+
+// ListByStatus 列出指定审核状态的作品；已驳回的作品已被删除，需包含已删除的记录
+func (r *artworkRepo) ListByStatus(status string, offset, limit int) ([]models.Artwork, int64, error) {
+	var artworks []models.Artwork
+	var total int64
+
+	db := r.db.Model(&models.Artwork{})
+	order := "artworks.created_at, artworks.id" // 待审核的作品先进先出
+	if status == models.StatusRejected {
+		db = db.Unscoped()
+		order = "artworks.moderated_at DESC, artworks.id DESC"
+	}
+	db = db.Where("artworks.status = ?", status)
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Order(order).Offset(offset).Limit(limit).Find(&artworks).Error; err != nil {
+		return nil, 0, err
+	}
+	return artworks, total, nil
+}
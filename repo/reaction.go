@@ -0,0 +1,36 @@
+package repo
+
+import (
+	"pln/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// AddReaction 记录互动，已存在时不做修改，返回是否新增
+func (r *artworkRepo) AddReaction(reaction *models.Reaction) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RemoveReaction 删除互动，返回是否存在
+func (r *artworkRepo) RemoveReaction(artworkID uint, client, kind string) (bool, error) {
+	result := r.db.
+		Where("artwork_id = ? AND client = ? AND kind = ?", artworkID, client, kind).
+		Delete(&models.Reaction{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListReactions 客户端对指定作品的全部互动
+func (r *artworkRepo) ListReactions(client string, artworkIDs []uint) ([]models.Reaction, error) {
+	var reactions []models.Reaction
+	if len(artworkIDs) == 0 {
+		return reactions, nil
+	}
+	err := r.db.
+		Where("client = ? AND artwork_id IN ?", client, artworkIDs).
+		Find(&reactions).Error
+	if err != nil {
+		return nil, err
+	}
+	return reactions, nil
+}
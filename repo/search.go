@@ -0,0 +1,156 @@
+package repo
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"pln/models"
+
+	"gorm.io/gorm"
+)
+
+// 全文索引：artworks_fts 为 FTS5 外部内容表，通过触发器与 artworks 保持同步。
+// 使用 trigram 分词以支持中文等无空格分隔的文本，因此每个检索词至少需要 3 个字符，
+// 更短的检索词退化为 LIKE 匹配。
+// 需要使用 -tags sqlite_fts5 编译，否则整体退化为 LIKE 匹配。
+
+const ftsTable = "artworks_fts"
+
+// 各列在 bm25 排序中的权重：title, description, artist, tags
+const ftsRank = "bm25(artworks_fts, 10.0, 2.0, 5.0, 3.0)"
+
+// 摘要中高亮片段的占位符，转义后替换为 <mark>
+const (
+	snippetStart = "\x02"
+	snippetEnd   = "\x03"
+)
+
+// searchHit 全文检索结果：作品及其摘要
+type searchHit struct {
+	models.Artwork
+	Snippet string
+}
+
+var ftsTriggers = map[string]string{
+	"artworks_fts_ai": `CREATE TRIGGER artworks_fts_ai AFTER INSERT ON artworks BEGIN
+	INSERT INTO artworks_fts(rowid, title, description, artist, tags)
+	VALUES (new.id, new.title, new.description, new.artist, new.tags);
+END`,
+	"artworks_fts_ad": `CREATE TRIGGER artworks_fts_ad AFTER DELETE ON artworks BEGIN
+	INSERT INTO artworks_fts(artworks_fts, rowid, title, description, artist, tags)
+	VALUES ('delete', old.id, old.title, old.description, old.artist, old.tags);
+END`,
+	"artworks_fts_au": `CREATE TRIGGER artworks_fts_au AFTER UPDATE OF title, description, artist, tags ON artworks BEGIN
+	INSERT INTO artworks_fts(artworks_fts, rowid, title, description, artist, tags)
+	VALUES ('delete', old.id, old.title, old.description, old.artist, old.tags);
+	INSERT INTO artworks_fts(rowid, title, description, artist, tags)
+	VALUES (new.id, new.title, new.description, new.artist, new.tags);
+END`,
+}
+
+// FTSAvailable 当前 SQLite 是否编译了 FTS5
+func FTSAvailable(db *gorm.DB) bool {
+	var used int
+	if err := db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&used).Error; err != nil {
+		return false
+	}
+	return used == 1
+}
+
+// SetupFTS 创建全文索引表与同步触发器，返回 FTS5 是否可用。
+// FTS5 不可用时会移除触发器，避免写入 artworks 时报错；
+// 触发器缺失（首次创建或曾经不可用）时会重建索引。
+func SetupFTS(db *gorm.DB) (bool, error) {
+	if !FTSAvailable(db) {
+		for name := range ftsTriggers {
+			if err := db.Exec("DROP TRIGGER IF EXISTS " + name).Error; err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS artworks_fts USING fts5(
+	title, description, artist, tags,
+	content='artworks', content_rowid='id', tokenize='trigram'
+)`).Error; err != nil {
+			return err
+		}
+
+		stale := false
+		for name, ddl := range ftsTriggers {
+			var count int64
+			if err := tx.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'trigger' AND name = ?", name).Scan(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+			if err := tx.Exec(ddl).Error; err != nil {
+				return err
+			}
+			stale = true
+		}
+
+		if stale {
+			return rebuildFTS(tx)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// RebuildFTS 根据 artworks 表重建全文索引
+func RebuildFTS(db *gorm.DB) error {
+	return rebuildFTS(db)
+}
+
+func rebuildFTS(db *gorm.DB) error {
+	return db.Exec("INSERT INTO artworks_fts(artworks_fts) VALUES ('rebuild')").Error
+}
+
+// ftsReady 全文索引表是否可以查询
+func ftsReady(db *gorm.DB) bool {
+	if !FTSAvailable(db) {
+		return false
+	}
+	var count int64
+	if err := db.Raw("SELECT count(*) FROM sqlite_master WHERE name = ?", ftsTable).Scan(&count).Error; err != nil {
+		return false
+	}
+	return count > 0
+}
+
+// ftsQuery 将用户输入转换为 FTS5 查询：每个词作为短语并以 AND 连接。
+// 任一检索词不足 3 个字符时返回空字符串，由调用方退化为 LIKE。
+func ftsQuery(q string) string {
+	terms := strings.Fields(q)
+	if len(terms) == 0 {
+		return ""
+	}
+
+	phrases := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if utf8.RuneCountInString(term) < 3 {
+			return ""
+		}
+		phrases = append(phrases, `"`+strings.ReplaceAll(term, `"`, `""`)+`"`)
+	}
+	return strings.Join(phrases, " ")
+}
+
+// highlightSnippet 转义摘要文本，并将高亮占位符替换为 <mark> 标签
+func highlightSnippet(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = html.EscapeString(s)
+	s = strings.ReplaceAll(s, snippetStart, "<mark>")
+	return strings.ReplaceAll(s, snippetEnd, "</mark>")
+}
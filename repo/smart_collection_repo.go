@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"pln/models"
+
+	"gorm.io/gorm"
+)
+
+type SmartCollectionRepo interface {
+	List(offset, limit int) ([]models.SmartCollection, int64, error)
+	GetByName(name string) (*models.SmartCollection, error)
+	Create(collection *models.SmartCollection) error
+	Update(collection *models.SmartCollection) error
+	Delete(name string) error
+}
+
+type smartCollectionRepo struct {
+	db *gorm.DB
+}
+
+func NewSmartCollectionRepo(db *gorm.DB) SmartCollectionRepo {
+	return &smartCollectionRepo{db: db}
+}
+
+func (r *smartCollectionRepo) List(offset, limit int) ([]models.SmartCollection, int64, error) {
+	var collections []models.SmartCollection
+	var total int64
+
+	if err := r.db.Model(&models.SmartCollection{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.db.Order("name").Offset(offset).Limit(limit).Find(&collections).Error; err != nil {
+		return nil, 0, err
+	}
+	return collections, total, nil
+}
+
+func (r *smartCollectionRepo) GetByName(name string) (*models.SmartCollection, error) {
+	var collection models.SmartCollection
+	if err := r.db.Where("name = ?", name).First(&collection).Error; err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+func (r *smartCollectionRepo) Create(collection *models.SmartCollection) error {
+	return r.db.Create(collection).Error
+}
+
+func (r *smartCollectionRepo) Update(collection *models.SmartCollection) error {
+	return r.db.Save(collection).Error
+}
+
+func (r *smartCollectionRepo) Delete(name string) error {
+	result := r.db.Where("name = ?", name).Delete(&models.SmartCollection{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
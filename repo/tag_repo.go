@@ -0,0 +1,229 @@
+package repo
+
+import (
+	"pln/models"
+
+	"gorm.io/gorm"
+)
+
+type TagRepo interface {
+	ListAliases() ([]models.TagAlias, error)
+	CreateAlias(alias *models.TagAlias) error
+	DeleteAlias(id uint) error
+
+	ListImplications() ([]models.TagImplication, error)
+	CreateImplication(implication *models.TagImplication) error
+	DeleteImplication(id uint) error
+
+	ListNamespaces() ([]models.TagNamespace, error)
+	GetNamespace(id uint) (*models.TagNamespace, error)
+	CreateNamespace(namespace *models.TagNamespace) error
+	UpdateNamespace(namespace *models.TagNamespace) error
+	DeleteNamespace(id uint) error
+
+	// 以下统计只计入分级在 ratings 之内的作品，ratings 为 nil 表示不限制
+	ListTagCounts(query models.TagListQuery) ([]models.TagCount, int64, error)
+	SearchTagCounts(prefix string, limit int, ratings []string) ([]models.TagCount, error)
+	CountTags(names []string, ratings []string) ([]models.TagCount, error)
+	RelatedTagCounts(tag string, limit int, ratings []string) ([]models.TagCount, error)
+}
+
+type tagRepo struct {
+	db *gorm.DB
+}
+
+func NewTagRepo(db *gorm.DB) TagRepo {
+	return &tagRepo{db: db}
+}
+
+func (r *tagRepo) ListAliases() ([]models.TagAlias, error) {
+	var aliases []models.TagAlias
+	if err := r.db.Order("tag, alias").Find(&aliases).Error; err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+func (r *tagRepo) CreateAlias(alias *models.TagAlias) error {
+	return r.db.Create(alias).Error
+}
+
+func (r *tagRepo) DeleteAlias(id uint) error {
+	result := r.db.Delete(&models.TagAlias{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *tagRepo) ListImplications() ([]models.TagImplication, error) {
+	var implications []models.TagImplication
+	if err := r.db.Order("tag, implies").Find(&implications).Error; err != nil {
+		return nil, err
+	}
+	return implications, nil
+}
+
+func (r *tagRepo) CreateImplication(implication *models.TagImplication) error {
+	return r.db.Create(implication).Error
+}
+
+func (r *tagRepo) DeleteImplication(id uint) error {
+	result := r.db.Delete(&models.TagImplication{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *tagRepo) ListNamespaces() ([]models.TagNamespace, error) {
+	var namespaces []models.TagNamespace
+	if err := r.db.Order("sort_order, name").Find(&namespaces).Error; err != nil {
+		return nil, err
+	}
+	return namespaces, nil
+}
+
+func (r *tagRepo) GetNamespace(id uint) (*models.TagNamespace, error) {
+	var namespace models.TagNamespace
+	if err := r.db.First(&namespace, id).Error; err != nil {
+		return nil, err
+	}
+	return &namespace, nil
+}
+
+func (r *tagRepo) CreateNamespace(namespace *models.TagNamespace) error {
+	return r.db.Create(namespace).Error
+}
+
+func (r *tagRepo) UpdateNamespace(namespace *models.TagNamespace) error {
+	return r.db.Save(namespace).Error
+}
+
+func (r *tagRepo) DeleteNamespace(id uint) error {
+	result := r.db.Delete(&models.TagNamespace{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// tagCountsSQL 统计每个标签被多少已公开的作品使用（tags 列为 JSON 数组），
+// 参数为可见分级的数量（0 表示不限制）与分级列表
+const tagCountsSQL = `SELECT j.value AS name, COUNT(*) AS count
+FROM artworks a
+JOIN json_each(CASE WHEN json_valid(a.tags) THEN a.tags ELSE '[]' END) j
+WHERE a.deleted_at IS NULL AND a.status = 'approved' AND (? = 0 OR a.rating IN ?)
+GROUP BY j.value`
+
+func (r *tagRepo) tagCounts(ratings []string) *gorm.DB {
+	return r.db.Table("(?) AS t", r.db.Raw(tagCountsSQL, len(ratings), ratingsArg(ratings)))
+}
+
+// ratingsArg IN 子句的参数，空切片会生成无效的 SQL，以空字符串占位
+func ratingsArg(ratings []string) []string {
+	if len(ratings) == 0 {
+		return []string{""}
+	}
+	return ratings
+}
+
+func (r *tagRepo) ListTagCounts(query models.TagListQuery) ([]models.TagCount, int64, error) {
+	var counts []models.TagCount
+	var total int64
+
+	db := r.tagCounts(query.Ratings)
+	if query.Namespace != "" {
+		db = db.Where("name LIKE ? ESCAPE '\\'", escapeLike(query.Namespace+":")+"%")
+	}
+	if query.Prefix != "" {
+		db = db.Where("name LIKE ? ESCAPE '\\'", escapeLike(query.Prefix)+"%")
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "count DESC, name"
+	switch {
+	case query.Sort == "name" && query.Desc:
+		order = "name DESC"
+	case query.Sort == "name":
+		order = "name"
+	case !query.Desc:
+		order = "count, name"
+	}
+
+	if err := db.Order(order).Offset(query.Offset).Limit(query.Limit).Scan(&counts).Error; err != nil {
+		return nil, 0, err
+	}
+	return counts, total, nil
+}
+
+// SearchTagCounts 按前缀查找标签，同时匹配命名空间之后的部分（ali 可匹配 artist:alice）
+func (r *tagRepo) SearchTagCounts(prefix string, limit int, ratings []string) ([]models.TagCount, error) {
+	var counts []models.TagCount
+	like := escapeLike(prefix) + "%"
+	err := r.tagCounts(ratings).
+		Where("name LIKE ? ESCAPE '\\' OR name LIKE ? ESCAPE '\\'", like, "%:"+like).
+		Order("count DESC, name").
+		Limit(limit).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+func (r *tagRepo) CountTags(names []string, ratings []string) ([]models.TagCount, error) {
+	var counts []models.TagCount
+	if len(names) == 0 {
+		return counts, nil
+	}
+	if err := r.tagCounts(ratings).Where("name IN ?", names).Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// RelatedTagCounts 统计与 tag 同时出现的标签
+func (r *tagRepo) RelatedTagCounts(tag string, limit int, ratings []string) ([]models.TagCount, error) {
+	var counts []models.TagCount
+	err := r.db.Raw(`SELECT o.value AS name, COUNT(*) AS count
+FROM artworks a
+JOIN json_each(CASE WHEN json_valid(a.tags) THEN a.tags ELSE '[]' END) t
+JOIN json_each(CASE WHEN json_valid(a.tags) THEN a.tags ELSE '[]' END) o
+WHERE a.deleted_at IS NULL AND a.status = 'approved' AND (? = 0 OR a.rating IN ?)
+	AND t.value = ? AND o.value != ?
+GROUP BY o.value
+ORDER BY count DESC, name
+LIMIT ?`, len(ratings), ratingsArg(ratings), tag, tag, limit).Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// SeedTagNamespaces 命名空间表为空时写入默认命名空间
+func SeedTagNamespaces(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&models.TagNamespace{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	namespaces := make([]models.TagNamespace, len(models.DefaultTagNamespaces))
+	copy(namespaces, models.DefaultTagNamespaces)
+	return db.Create(&namespaces).Error
+}
@@ -0,0 +1,40 @@
+package repo
+
+import "pln/models"
+
+func (r *artworkRepo) CreateTagRevision(revision *models.TagRevision) error {
+	return r.db.Create(revision).Error
+}
+
+func (r *artworkRepo) GetTagRevision(id uint) (*models.TagRevision, error) {
+	var revision models.TagRevision
+	if err := r.db.First(&revision, id).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// ListTagRevisions 按时间倒序列出标签修改记录
+func (r *artworkRepo) ListTagRevisions(query models.TagRevisionQuery) ([]models.TagRevision, int64, error) {
+	var revisions []models.TagRevision
+	var total int64
+
+	db := r.db.Model(&models.TagRevision{})
+	if query.ArtworkID != 0 {
+		db = db.Where("artwork_id = ?", query.ArtworkID)
+	}
+	if query.Actor != "" {
+		db = db.Where("actor = ?", query.Actor)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Order("id DESC").Offset(query.Offset).Limit(query.Limit).Find(&revisions).Error; err != nil {
+		return nil, 0, err
+	}
+	return revisions, total, nil
+}
@@ -0,0 +1,136 @@
+package repo
+
+import (
+	"time"
+
+	"pln/models"
+
+	"gorm.io/gorm"
+)
+
+type UserRepo interface {
+	List(offset, limit int) ([]models.User, int64, error)
+	Count() (int64, error)
+	GetByID(id uint) (*models.User, error)
+	GetByUsername(username string) (*models.User, error)
+	GetByExternalID(externalID string) (*models.User, error)
+	Create(user *models.User) error
+	Update(user *models.User) error
+	// Delete 删除用户及其全部会话
+	Delete(id uint) error
+
+	CreateSession(session *models.Session) error
+	// GetSession 按令牌哈希查找未过期的会话
+	GetSession(tokenHash string, now time.Time) (*models.Session, error)
+	DeleteSession(tokenHash string) error
+	DeleteUserSessions(userID uint) error
+	DeleteExpiredSessions(now time.Time) error
+	// CreateAuditLog 审计记录，与用户修改放在同一事务中写入
+	CreateAuditLog(log *models.AuditLog) error
+
+	// Transaction 在事务中执行 fn，fn 内应使用传入的 repo
+	Transaction(fn func(repo UserRepo) error) error
+}
+
+type userRepo struct {
+	db *gorm.DB
+}
+
+func NewUserRepo(db *gorm.DB) UserRepo {
+	return &userRepo{db: db}
+}
+
+func (r *userRepo) List(offset, limit int) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	if err := r.db.Model(&models.User{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.db.Order("id").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (r *userRepo) Count() (int64, error) {
+	var total int64
+	err := r.db.Model(&models.User{}).Count(&total).Error
+	return total, err
+}
+
+func (r *userRepo) GetByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepo) GetByUsername(username string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepo) GetByExternalID(externalID string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("external_id = ?", externalID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepo) Create(user *models.User) error {
+	return r.db.Create(user).Error
+}
+
+func (r *userRepo) Update(user *models.User) error {
+	return r.db.Save(user).Error
+}
+
+func (r *userRepo) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.User{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("user_id = ?", id).Delete(&models.Session{}).Error
+	})
+}
+
+func (r *userRepo) CreateSession(session *models.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *userRepo) GetSession(tokenHash string, now time.Time) (*models.Session, error) {
+	var session models.Session
+	err := r.db.Where("token_hash = ? AND expires_at > ?", tokenHash, now).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *userRepo) DeleteSession(tokenHash string) error {
+	return r.db.Where("token_hash = ?", tokenHash).Delete(&models.Session{}).Error
+}
+
+func (r *userRepo) DeleteUserSessions(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.Session{}).Error
+}
+
+func (r *userRepo) DeleteExpiredSessions(now time.Time) error {
+	return r.db.Where("expires_at <= ?", now).Delete(&models.Session{}).Error
+}
+
+func (r *userRepo) Transaction(fn func(repo UserRepo) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&userRepo{db: tx})
+	})
+}
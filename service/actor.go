@@ -0,0 +1,40 @@
+package service
+
+import "context"
+
+type actorKey struct{}
+
+// 未指定操作者时记录的名称
+const SystemActor = "system"
+
+// WithActor 在 context 中记录操作者，用于标签修改记录等审计信息
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom 读取 context 中的操作者
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
+
+type requestKey struct{}
+
+// RequestInfo 发起操作的请求，写入审计记录
+type RequestInfo struct {
+	ID string // 请求 ID，由 requestid 中间件生成
+	IP string // 客户端 IP
+}
+
+// WithRequest 在 context 中记录请求信息
+func WithRequest(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestKey{}, info)
+}
+
+// RequestFrom 读取 context 中的请求信息，命令行等没有请求时为空
+func RequestFrom(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestKey{}).(RequestInfo)
+	return info
+}
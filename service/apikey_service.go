@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"pln/models"
+	"pln/repo"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidAPIKey = errors.New("无效的 API Key 信息")
+	// ErrAPIKeyUnusable 密钥不存在、已过期或已吊销
+	ErrAPIKeyUnusable = errors.New("API Key 无效、已过期或已吊销")
+	ErrAPIKeyRevoked  = errors.New("API Key 已吊销")
+)
+
+// apiKeyPrefix 新密钥的固定前缀，便于在日志与代码扫描中识别
+const apiKeyPrefix = "pln_"
+
+// lastUsedInterval 最后使用时间的更新间隔，避免每个请求都写数据库
+const lastUsedInterval = time.Minute
+
+type APIKeyService interface {
+	List() ([]models.APIKeyResponse, error)
+	// Create 创建密钥，明文只在返回值中出现一次
+	Create(ctx context.Context, req *models.APIKeyCreateRequest) (*models.APIKeyCreated, error)
+	// Rotate 为密钥生成新的明文，旧明文立即失效，名称与权限范围不变
+	Rotate(ctx context.Context, id uint) (*models.APIKeyCreated, error)
+	Revoke(ctx context.Context, id uint) (*models.APIKeyResponse, error)
+	// Authenticate 校验明文密钥，并更新最后使用时间
+	Authenticate(key string) (*models.APIKey, error)
+	// Bootstrap 迁移旧版明文密钥文件；数据库中没有任何密钥时创建一个 admin 密钥并返回明文
+	Bootstrap(legacyFile string) (string, error)
+}
+
+type apiKeyService struct {
+	repo repo.APIKeyRepo
+}
+
+func NewAPIKeyService(repo repo.APIKeyRepo) APIKeyService {
+	return &apiKeyService{repo: repo}
+}
+
+func (s *apiKeyService) List() ([]models.APIKeyResponse, error) {
+	keys, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		result = append(result, keys[i].ToResponse())
+	}
+	return result, nil
+}
+
+func (s *apiKeyService) Create(ctx context.Context, req *models.APIKeyCreateRequest) (*models.APIKeyCreated, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: 名称不能为空", ErrInvalidAPIKey)
+	}
+	scopes, err := normalizeScopes(req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: 过期时间必须晚于当前时间", ErrInvalidAPIKey)
+	}
+
+	plain, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &models.APIKey{
+		Name:      name,
+		Prefix:    plain[len(apiKeyPrefix) : len(apiKeyPrefix)+8],
+		KeyHash:   hashToken(plain),
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: ActorFrom(ctx),
+	}
+	if err := key.SetScopes(scopes); err != nil {
+		return nil, err
+	}
+	err = s.repo.Transaction(func(r repo.APIKeyRepo) error {
+		if err := r.Create(key); err != nil {
+			return err
+		}
+		return recordTargetAudit(ctx, r, apiKeyTarget(key.ID), models.AuditAPIKeyCreate, nil, key.ToResponse())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.APIKeyCreated{APIKeyResponse: key.ToResponse(), Key: plain}, nil
+}
+
+func (s *apiKeyService) Rotate(ctx context.Context, id uint) (*models.APIKeyCreated, error) {
+	plain, err := newAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var key *models.APIKey
+	err = s.repo.Transaction(func(r repo.APIKeyRepo) error {
+		var err error
+		key, err = r.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if key.RevokedAt != nil {
+			return ErrAPIKeyRevoked
+		}
+
+		before := key.ToResponse()
+		key.Prefix = plain[len(apiKeyPrefix) : len(apiKeyPrefix)+8]
+		key.KeyHash = hashToken(plain)
+		key.LastUsedAt = nil
+		if err := r.Update(key); err != nil {
+			return err
+		}
+		return recordTargetAudit(ctx, r, apiKeyTarget(id), models.AuditAPIKeyRotate, before, key.ToResponse())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.APIKeyCreated{APIKeyResponse: key.ToResponse(), Key: plain}, nil
+}
+
+func (s *apiKeyService) Revoke(ctx context.Context, id uint) (*models.APIKeyResponse, error) {
+	var key *models.APIKey
+	err := s.repo.Transaction(func(r repo.APIKeyRepo) error {
+		var err error
+		key, err = r.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if key.RevokedAt != nil {
+			return ErrAPIKeyRevoked
+		}
+
+		before := key.ToResponse()
+		now := time.Now()
+		key.RevokedAt = &now
+		if err := r.Update(key); err != nil {
+			return err
+		}
+		return recordTargetAudit(ctx, r, apiKeyTarget(id), models.AuditAPIKeyRevoke, before, key.ToResponse())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := key.ToResponse()
+	return &resp, nil
+}
+
+func (s *apiKeyService) Authenticate(plain string) (*models.APIKey, error) {
+	key, err := s.repo.GetByHash(hashToken(plain))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAPIKeyUnusable
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && !key.ExpiresAt.After(now)) {
+		return nil, ErrAPIKeyUnusable
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedInterval {
+		if err := s.repo.TouchLastUsed(key.ID, now); err != nil {
+			log.Warn().Err(err).Uint("api_key_id", key.ID).Msg("更新 API Key 最后使用时间失败")
+		}
+		key.LastUsedAt = &now
+	}
+	return key, nil
+}
+
+func (s *apiKeyService) Bootstrap(legacyFile string) (string, error) {
+	if err := s.migrateLegacy(legacyFile); err != nil {
+		return "", err
+	}
+
+	total, err := s.repo.Count()
+	if err != nil || total > 0 {
+		return "", err
+	}
+
+	created, err := s.Create(WithActor(context.Background(), SystemActor), &models.APIKeyCreateRequest{
+		Name:   "default",
+		Scopes: []string{models.ScopeAdmin},
+	})
+	if err != nil {
+		return "", err
+	}
+	return created.Key, nil
+}
+
+// migrateLegacy 将旧版明文保存的密钥写入数据库并删除文件，以 admin 权限导入，
+// 前缀沿用旧版的 SHA-256 指纹；与其他 Key 一样以 apikey:<id> 作为操作者标识
+func (s *apiKeyService) migrateLegacy(legacyFile string) error {
+	data, err := os.ReadFile(legacyFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取旧版 API Key 文件失败: %w", err)
+	}
+
+	plain := strings.TrimSpace(string(data))
+	if plain != "" {
+		keyHash := hashToken(plain)
+		if _, err := s.repo.GetByHash(keyHash); errors.Is(err, gorm.ErrRecordNotFound) {
+			key := &models.APIKey{
+				Name:      "legacy",
+				Prefix:    keyHash[:8],
+				KeyHash:   keyHash,
+				CreatedBy: SystemActor,
+			}
+			if err := key.SetScopes([]string{models.ScopeAdmin}); err != nil {
+				return err
+			}
+			if err := s.repo.Create(key); err != nil {
+				return fmt.Errorf("迁移旧版 API Key 失败: %w", err)
+			}
+			log.Warn().Uint("api_key_id", key.ID).Msg("已迁移旧版 API Key，其强度较低，建议尽快轮换或吊销")
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if err := os.Remove(legacyFile); err != nil {
+		return fmt.Errorf("删除旧版 API Key 文件失败: %w", err)
+	}
+	return nil
+}
+
+// apiKeyTarget 审计记录中 API Key 的对象标识
+func apiKeyTarget(id uint) string {
+	return fmt.Sprintf("apikey:%d", id)
+}
+
+// normalizeScopes 校验并去重权限范围
+func normalizeScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: 至少需要一个权限范围", ErrInvalidAPIKey)
+	}
+
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !models.ValidScope(scope) {
+			return nil, fmt.Errorf("%w: 权限范围可选值为 %s", ErrInvalidAPIKey, strings.Join(models.Scopes, ", "))
+		}
+		if !slices.Contains(result, scope) {
+			result = append(result, scope)
+		}
+	}
+	return result, nil
+}
+
+// newAPIKey 生成 pln_ 前缀加 32 字节随机数的密钥
+func newAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + hex.EncodeToString(b), nil
+}
@@ -1,9 +1,16 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"maps"
+	"math/bits"
+	"pln/conf"
 	"pln/models"
 	"pln/repo"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
@@ -11,28 +18,46 @@ import (
 
 type ArtworkService interface {
 	CreateArtwork(req *models.ArtworkCreateRequest) (*models.ArtworkResponse, error)
-	GetArtwork(id uint) (*models.ArtworkResponse, error)
+	// GetArtwork 获取作品详情，ratings 非空时分级不在其中的作品视为不存在；
+	// public 为 true 时未审核通过的作品同样视为不存在
+	GetArtwork(id uint, ratings []string, public bool) (*models.ArtworkResponse, error)
 	GetByPHashSimilarity(int64, int) ([]models.ArtworkResponse, error)
 	GetByHash(hash string) (*models.Artwork, error)
+	GetRejectedByHash(hash string) (*models.Artwork, error)
 	GetArtworks(page, pageSize int, filters map[string]any) ([]models.ArtworkResponse, int64, error)
 	GetRandomArtworks(limit int, filters map[string]any) ([]models.ArtworkResponse, error)
-
-	UpdateArtwork(id uint, req *models.ArtworkUpdateRequest) (*models.ArtworkResponse, error)
-	DeleteArtwork(id uint) error
+	CountArtworks(filters map[string]any) (int64, error)
+	// ResolveFilters 将请求的过滤条件转为查询条件（展开作品集、解析 ns:value、替换标签别名），
+	// 作品集不存在时返回 ErrCollectionNotFound
+	ResolveFilters(filters map[string]any) (map[string]any, error)
+
+	UpdateArtwork(ctx context.Context, id uint, req *models.ArtworkUpdateRequest) (*models.ArtworkResponse, error)
+	PatchTags(ctx context.Context, id uint, req *models.ArtworkTagPatchRequest) (*models.ArtworkResponse, error)
+	BulkEditTags(ctx context.Context, req *models.BulkTagRequest) (*models.BulkTagResult, error)
+	RevertTags(ctx context.Context, id, revisionID uint) (*models.ArtworkResponse, error)
+	// TagHistory 作品的标签修改记录，非 moderator 只能查看已审核通过的作品
+	TagHistory(id uint, moderator bool, page, pageSize int) ([]models.TagRevisionResponse, int64, error)
+	ListTagChanges(query models.TagRevisionQuery, page, pageSize int) ([]models.TagRevisionResponse, int64, error)
+	DeleteArtwork(ctx context.Context, id uint) error
 
 	IncrementViews(id uint) error
-	IncrementLikes(id uint) error
-	DecrementLikes(id uint) error
-	IncrementBookmarks(id uint) error
-	DecrementBookmarks(id uint) error
 }
 
+var (
+	// ErrCollectionNotFound 过滤条件中的智能作品集不存在
+	ErrCollectionNotFound = errors.New("作品集不存在")
+	ErrInvalidRating      = errors.New("无效的内容分级，可选值: safe, questionable, explicit")
+)
+
 type artworkService struct {
-	repo repo.ArtworkRepo
+	repo    repo.ArtworkRepo
+	tags    TagService
+	smart   repo.SmartCollectionRepo
+	content conf.ContentConfig
 }
 
-func NewArtworkService(repo repo.ArtworkRepo) ArtworkService {
-	return &artworkService{repo: repo}
+func NewArtworkService(repo repo.ArtworkRepo, tags TagService, smart repo.SmartCollectionRepo, content conf.ContentConfig) ArtworkService {
+	return &artworkService{repo: repo, tags: tags, smart: smart, content: content}
 }
 
 func (s *artworkService) CreateArtwork(req *models.ArtworkCreateRequest) (*models.ArtworkResponse, error) {
@@ -40,6 +65,13 @@ func (s *artworkService) CreateArtwork(req *models.ArtworkCreateRequest) (*model
 		URL:          req.URL,
 		ThumbnailURL: req.ThumbnailURL,
 		PreviewURL:   req.PreviewURL,
+		SourceURL:    req.SourceURL,
+		Artist:       req.Artist,
+		Title:        req.Title,
+		Description:  req.Description,
+		License:      req.License,
+		Rating:       req.Rating,
+		Status:       req.Status,
 		Hash:         req.Hash,
 		PHash:        req.PHash,
 		FileID:       req.FileID,
@@ -48,10 +80,21 @@ func (s *artworkService) CreateArtwork(req *models.ArtworkCreateRequest) (*model
 		Bookmarks:    0,
 	}
 
-	// 设置 tags，如果为空则设置为空数组
-	tags := req.Tags
-	if len(tags) == 0 {
-		tags = []string{}
+	// 未指定分级时使用实例配置的默认值，上传、URL 导入与导出包导入都经过这里
+	if artwork.Rating == "" {
+		artwork.Rating = s.content.DefaultRating
+	}
+	if !models.ValidRating(artwork.Rating) {
+		return nil, ErrInvalidRating
+	}
+	if artwork.Status == "" {
+		artwork.Status = models.StatusApproved
+	}
+
+	// 设置 tags（应用别名与蕴含规则），如果为空则设置为空数组
+	tags, err := s.tags.Canonicalize(req.Tags)
+	if err != nil {
+		return nil, err
 	}
 	if err := artwork.SetTags(tags); err != nil {
 		return nil, err
@@ -61,11 +104,11 @@ func (s *artworkService) CreateArtwork(req *models.ArtworkCreateRequest) (*model
 		return nil, err
 	}
 
-	resp := artwork.ToResponse()
+	resp := s.toResponse(artwork)
 	return &resp, nil
 }
 
-func (s *artworkService) GetArtwork(id uint) (*models.ArtworkResponse, error) {
+func (s *artworkService) GetArtwork(id uint, ratings []string, public bool) (*models.ArtworkResponse, error) {
 	artwork, err := s.repo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -73,11 +116,17 @@ func (s *artworkService) GetArtwork(id uint) (*models.ArtworkResponse, error) {
 		}
 		return nil, err
 	}
+	if len(ratings) > 0 && !slices.Contains(ratings, artwork.Rating) {
+		return nil, errors.New("artwork not found")
+	}
+	if public && artwork.Status != models.StatusApproved {
+		return nil, errors.New("artwork not found")
+	}
 
 	// 增加浏览次数
 	_ = s.repo.IncrementViews(id)
 
-	resp := artwork.ToResponse()
+	resp := s.toResponse(artwork)
 	return &resp, nil
 }
 
@@ -89,6 +138,10 @@ func (s *artworkService) GetByHash(hash string) (*models.Artwork, error) {
 	return &artwork, nil
 }
 
+func (s *artworkService) GetRejectedByHash(hash string) (*models.Artwork, error) {
+	return s.repo.GetRejectedByHash(hash)
+}
+
 // GetByPHashSimilarity 通过 pHash 相似度查询相似的作品
 func (s *artworkService) GetByPHashSimilarity(pHash int64, threshold int) ([]models.ArtworkResponse, error) {
 	logger := log.With().Str("component", "ArtworkService").Logger()
@@ -152,14 +205,8 @@ func (s *artworkService) GetByPHashSimilarity(pHash int64, threshold int) ([]mod
 
 // hammingDistance 计算两个 pHash 的汉明距离
 func hammingDistance(hash1, hash2 int64) int {
-	// XOR 后统计 1 的个数
-	xor := hash1 ^ hash2
-	distance := 0
-	for xor > 0 {
-		distance += int(xor & 1)
-		xor >>= 1
-	}
-	return distance
+	// XOR 后统计 1 的个数（按无符号处理，避免最高位为 1 时被当作负数）
+	return bits.OnesCount64(uint64(hash1 ^ hash2))
 }
 
 func (s *artworkService) GetArtworks(page, pageSize int, filters map[string]any) ([]models.ArtworkResponse, int64, error) {
@@ -172,6 +219,11 @@ func (s *artworkService) GetArtworks(page, pageSize int, filters map[string]any)
 
 	offset := (page - 1) * pageSize
 
+	filters, err := s.canonicalFilters(filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	artworks, total, err := s.repo.GetAll(offset, pageSize, filters)
 	if err != nil {
 		return nil, 0, err
@@ -179,7 +231,7 @@ func (s *artworkService) GetArtworks(page, pageSize int, filters map[string]any)
 
 	var responses []models.ArtworkResponse
 	for _, artwork := range artworks {
-		responses = append(responses, artwork.ToResponse())
+		responses = append(responses, s.toResponse(&artwork))
 	}
 
 	return responses, total, nil
@@ -193,6 +245,11 @@ func (s *artworkService) GetRandomArtworks(limit int, filters map[string]any) ([
 		limit = 100
 	}
 
+	filters, err := s.canonicalFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
 	artworks, err := s.repo.GetRandom(limit, filters)
 	if err != nil {
 		return nil, err
@@ -200,92 +257,199 @@ func (s *artworkService) GetRandomArtworks(limit int, filters map[string]any) ([
 
 	var responses []models.ArtworkResponse
 	for _, artwork := range artworks {
-		responses = append(responses, artwork.ToResponse())
+		responses = append(responses, s.toResponse(&artwork))
 	}
 
 	return responses, nil
 }
 
-func (s *artworkService) UpdateArtwork(id uint, req *models.ArtworkUpdateRequest) (*models.ArtworkResponse, error) {
-	artwork := &models.Artwork{}
+// toResponse 转换为响应并附带结构化标签
+func (s *artworkService) toResponse(artwork *models.Artwork) models.ArtworkResponse {
+	resp := artwork.ToResponse()
+	resp.TagDetails = s.tags.Describe(resp.Tags)
+	return resp
+}
 
-	if req.URL != "" {
-		artwork.URL = req.URL
+// canonicalFilters 展开作品集条件，将关键词中的 ns:value 转为标签过滤，并将标签中的别名替换为规范标签
+func (s *artworkService) canonicalFilters(filters map[string]any) (map[string]any, error) {
+	result := maps.Clone(filters)
+	if result == nil {
+		result = make(map[string]any)
 	}
-	if len(req.Tags) > 0 {
-		artwork.SetTags(req.Tags)
+
+	if name, ok := result["collection"].(string); ok {
+		delete(result, "collection")
+		if err := s.expandCollection(result, name); err != nil {
+			return nil, err
+		}
+	}
+	var tags []string
+	if v, ok := result["tags"].([]string); ok {
+		tags = append(tags, v...)
 	}
 
-	if err := s.repo.Update(id, artwork); err != nil {
-		return nil, err
+	if q, ok := result["q"].(string); ok && q != "" {
+		nsTags, text, err := s.tags.SplitQuery(q)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, nsTags...)
+		if text == "" {
+			delete(result, "q")
+		} else {
+			result["q"] = text
+		}
 	}
 
-	// 重新获取更新后的数据
-	updated, err := s.repo.GetByID(id)
-	if err != nil {
-		return nil, err
+	if len(tags) == 0 {
+		return result, nil
 	}
 
-	resp := updated.ToResponse()
-	return &resp, nil
-}
+	canonical := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		t, err := s.tags.CanonicalTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		canonical = append(canonical, t)
+	}
+	result["tags"] = canonical
 
-func (s *artworkService) DeleteArtwork(id uint) error {
-	return s.repo.Delete(id)
+	return result, nil
 }
 
-func (s *artworkService) IncrementViews(id uint) error {
-	return s.repo.IncrementViews(id)
-}
+// expandCollection 将作品集条件合并到 filters：数字为手动作品集 ID，否则为智能作品集名称。
+// 智能作品集的标签与关键词与请求条件同时生效，artist / license 以请求为准。
+func (s *artworkService) expandCollection(filters map[string]any, name string) error {
+	if name == "" {
+		return nil
+	}
+	if id, err := strconv.ParseUint(name, 10, 32); err == nil {
+		filters["collection_id"] = uint(id)
+		return nil
+	}
 
-func (s *artworkService) IncrementLikes(id uint) error {
-	artwork, err := s.repo.GetByID(id)
+	collection, err := s.smart.GetByName(name)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCollectionNotFound
+		}
 		return err
 	}
 
-	if artwork == nil {
-		return errors.New("artwork not found")
+	for key, value := range collection.Query.Filters() {
+		switch key {
+		case "tags":
+			existing, _ := filters["tags"].([]string)
+			filters["tags"] = append(slices.Clone(existing), value.([]string)...)
+		case "q":
+			if q, _ := filters["q"].(string); q != "" {
+				filters["q"] = q + " " + value.(string)
+			} else {
+				filters["q"] = value
+			}
+		default:
+			if _, ok := filters[key]; !ok {
+				filters[key] = value
+			}
+		}
 	}
+	return nil
+}
 
-	return s.repo.IncrementLikes(id)
+func (s *artworkService) ResolveFilters(filters map[string]any) (map[string]any, error) {
+	return s.canonicalFilters(filters)
 }
 
-func (s *artworkService) DecrementLikes(id uint) error {
-	artwork, err := s.repo.GetByID(id)
+func (s *artworkService) CountArtworks(filters map[string]any) (int64, error) {
+	filters, err := s.canonicalFilters(filters)
 	if err != nil {
-		return err
+		return 0, err
 	}
-
-	if artwork == nil {
-		return errors.New("artwork not found")
-	}
-
-	return s.repo.DecrementLikes(id)
+	return s.repo.Count(filters)
 }
 
-func (s *artworkService) IncrementBookmarks(id uint) error {
-	artwork, err := s.repo.GetByID(id)
-	if err != nil {
-		return err
+func (s *artworkService) UpdateArtwork(ctx context.Context, id uint, req *models.ArtworkUpdateRequest) (*models.ArtworkResponse, error) {
+	fields := map[string]any{}
+
+	if req.URL != "" {
+		fields["url"] = req.URL
 	}
 
-	if artwork == nil {
-		return errors.New("artwork not found")
+	// 来源与署名字段允许清空
+	for column, value := range map[string]*string{
+		"source_url":  req.SourceURL,
+		"artist":      req.Artist,
+		"title":       req.Title,
+		"description": req.Description,
+		"license":     req.License,
+	} {
+		if value != nil {
+			fields[column] = strings.TrimSpace(*value)
+		}
+	}
+	if req.Rating != nil {
+		if !models.ValidRating(*req.Rating) {
+			return nil, ErrInvalidRating
+		}
+		fields["rating"] = *req.Rating
 	}
 
-	return s.repo.IncrementBookmarks(id)
-}
+	var updated *models.Artwork
+	err := s.repo.Transaction(func(r repo.ArtworkRepo) error {
+		original, err := r.GetByID(id)
+		if err != nil {
+			return err
+		}
 
-func (s *artworkService) DecrementBookmarks(id uint) error {
-	artwork, err := s.repo.GetByID(id)
+		if len(fields) > 0 {
+			if err := r.UpdateFields(id, fields); err != nil {
+				return err
+			}
+		}
+
+		// 重新获取更新后的数据
+		artwork, err := r.GetByID(id)
+		if err != nil {
+			return err
+		}
+
+		if req.Tags != nil {
+			changed, err := s.editArtworkTags(ctx, r, artwork, tagEdit{set: req.Tags}, models.TagRevisionUpdate)
+			if err != nil {
+				return err
+			}
+			if changed {
+				if artwork, err = r.GetByID(id); err != nil {
+					return err
+				}
+			}
+		}
+
+		updated = artwork
+		return recordAudit(ctx, r, id, models.AuditArtworkUpdate, original.ToResponse(), artwork.ToResponse())
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if artwork == nil {
-		return errors.New("artwork not found")
-	}
+	resp := s.toResponse(updated)
+	return &resp, nil
+}
+
+func (s *artworkService) DeleteArtwork(ctx context.Context, id uint) error {
+	return s.repo.Transaction(func(r repo.ArtworkRepo) error {
+		artwork, err := r.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err := r.Delete(id); err != nil {
+			return err
+		}
+		return recordAudit(ctx, r, id, models.AuditArtworkDelete, artwork.ToResponse(), nil)
+	})
+}
 
-	return s.repo.DecrementBookmarks(id)
+func (s *artworkService) IncrementViews(id uint) error {
+	return s.repo.IncrementViews(id)
 }
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"pln/models"
+	"pln/repo"
+
+	"gorm.io/gorm"
+)
+
+// 单次批量标签操作最多影响的作品数
+const maxBulkTagArtworks = 1000
+
+var (
+	ErrInvalidTagEdit   = errors.New("无效的标签操作")
+	ErrTooManyArtworks  = fmt.Errorf("命中的作品超过 %d 个，请缩小范围", maxBulkTagArtworks)
+	ErrArtworksNotFound = errors.New("部分作品不存在")
+	ErrRevisionMismatch = errors.New("修改记录不属于该作品")
+)
+
+// tagEdit 一次标签修改：先替换（可选），再添加、删除
+type tagEdit struct {
+	set    *[]string
+	add    []string
+	remove []string
+}
+
+// applyTagEdit 计算修改后的标签，结果会应用别名与蕴含规则。
+// 删除的标签若仍被其他标签蕴含，会重新出现。
+func (s *artworkService) applyTagEdit(current []string, edit tagEdit) ([]string, error) {
+	base := current
+	if edit.set != nil {
+		base = *edit.set
+	}
+
+	tags, err := s.tags.Canonicalize(append(slices.Clone(base), edit.add...))
+	if err != nil {
+		return nil, err
+	}
+	if len(edit.remove) == 0 {
+		return tags, nil
+	}
+
+	removed := make(map[string]bool, len(edit.remove))
+	for _, tag := range edit.remove {
+		canonical, err := s.tags.CanonicalTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		removed[canonical] = true
+	}
+
+	kept := tags[:0:0]
+	for _, tag := range tags {
+		if !removed[tag] {
+			kept = append(kept, tag)
+		}
+	}
+	return s.tags.Canonicalize(kept)
+}
+
+// editArtworkTags 修改单个作品的标签并写入修改记录，返回标签是否发生变化
+func (s *artworkService) editArtworkTags(ctx context.Context, r repo.ArtworkRepo, artwork *models.Artwork, edit tagEdit, action string) (bool, error) {
+	before := artwork.ToResponse().Tags
+	after, err := s.applyTagEdit(before, edit)
+	if err != nil {
+		return false, err
+	}
+	if slices.Equal(before, after) {
+		return false, nil
+	}
+
+	if err := s.saveTags(ctx, r, artwork, before, after, action, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// saveTags 写入作品的新标签，并在同一 repo（事务）中记录修改
+func (s *artworkService) saveTags(ctx context.Context, r repo.ArtworkRepo, artwork *models.Artwork, before, after []string, action string, revertOf *uint) error {
+	if err := artwork.SetTags(after); err != nil {
+		return err
+	}
+	if err := r.UpdateFields(artwork.ID, map[string]any{"tags": artwork.Tags}); err != nil {
+		return err
+	}
+
+	revision, err := models.NewTagRevision(artwork.ID, action, ActorFrom(ctx), before, after)
+	if err != nil {
+		return err
+	}
+	revision.RevertOf = revertOf
+	if err := r.CreateTagRevision(revision); err != nil {
+		return err
+	}
+
+	// 通过 PUT 修改的标签计入 artwork.update 的审计记录
+	if action == models.TagRevisionUpdate {
+		return nil
+	}
+	return recordAudit(ctx, r, artwork.ID, models.AuditArtworkTags,
+		map[string]any{"tags": before}, map[string]any{"tags": after})
+}
+
+func (s *artworkService) PatchTags(ctx context.Context, id uint, req *models.ArtworkTagPatchRequest) (*models.ArtworkResponse, error) {
+	edit := tagEdit{set: req.Set, add: req.Add, remove: req.Remove}
+	if req.Clear {
+		if req.Set != nil {
+			return nil, fmt.Errorf("%w: clear 与 set 不能同时使用", ErrInvalidTagEdit)
+		}
+		edit.set = &[]string{}
+	}
+
+	var updated *models.Artwork
+	err := s.repo.Transaction(func(r repo.ArtworkRepo) error {
+		artwork, err := r.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if _, err := s.editArtworkTags(ctx, r, artwork, edit, models.TagRevisionPatch); err != nil {
+			return err
+		}
+		updated, err = r.GetByID(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := s.toResponse(updated)
+	return &resp, nil
+}
+
+func (s *artworkService) BulkEditTags(ctx context.Context, req *models.BulkTagRequest) (*models.BulkTagResult, error) {
+	if (len(req.IDs) > 0) == (req.Query != nil) {
+		return nil, fmt.Errorf("%w: ids 与 query 必须且只能指定一个", ErrInvalidTagEdit)
+	}
+	if req.Replace != nil && (len(req.Add) > 0 || len(req.Remove) > 0) {
+		return nil, fmt.Errorf("%w: replace 不能与 add / remove 同时使用", ErrInvalidTagEdit)
+	}
+	if req.Replace == nil && len(req.Add) == 0 && len(req.Remove) == 0 {
+		return nil, fmt.Errorf("%w: 未指定任何操作", ErrInvalidTagEdit)
+	}
+	if len(req.IDs) > maxBulkTagArtworks {
+		return nil, ErrTooManyArtworks
+	}
+
+	var filters map[string]any
+	if req.Query != nil {
+		var err error
+		if filters, err = s.canonicalFilters(req.Query.Filters()); err != nil {
+			return nil, err
+		}
+		if len(filters) == 0 {
+			return nil, fmt.Errorf("%w: 检索条件不能为空", ErrInvalidTagEdit)
+		}
+		// 管理操作同样作用于待审核的作品
+		filters["status"] = models.StatusAll
+	}
+
+	ids := slices.Compact(slices.Sorted(slices.Values(req.IDs)))
+	edit := tagEdit{set: req.Replace, add: req.Add, remove: req.Remove}
+	result := &models.BulkTagResult{UpdatedIDs: []uint{}}
+
+	// 全部作品在同一事务中修改，任一失败则整体回滚
+	err := s.repo.Transaction(func(r repo.ArtworkRepo) error {
+		var artworks []models.Artwork
+		var err error
+		if req.Query != nil {
+			artworks, err = r.FindAll(filters, maxBulkTagArtworks+1)
+		} else {
+			artworks, err = r.GetByIDs(ids)
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(artworks) > maxBulkTagArtworks {
+			return ErrTooManyArtworks
+		}
+		if req.Query == nil && len(artworks) != len(ids) {
+			return ErrArtworksNotFound
+		}
+
+		result.Matched = len(artworks)
+		for i := range artworks {
+			changed, err := s.editArtworkTags(ctx, r, &artworks[i], edit, models.TagRevisionBulk)
+			if err != nil {
+				return err
+			}
+			if changed {
+				result.Updated++
+				result.UpdatedIDs = append(result.UpdatedIDs, artworks[i].ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *artworkService) TagHistory(id uint, moderator bool, page, pageSize int) ([]models.TagRevisionResponse, int64, error) {
+	artwork, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !moderator && artwork.Status != models.StatusApproved {
+		return nil, 0, gorm.ErrRecordNotFound
+	}
+	return s.ListTagChanges(models.TagRevisionQuery{ArtworkID: id}, page, pageSize)
+}
+
+func (s *artworkService) ListTagChanges(query models.TagRevisionQuery, page, pageSize int) ([]models.TagRevisionResponse, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	query.Offset = (page - 1) * pageSize
+	query.Limit = pageSize
+
+	revisions, total, err := s.repo.ListTagRevisions(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]models.TagRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		responses = append(responses, revision.ToResponse())
+	}
+	return responses, total, nil
+}
+
+// RevertTags 将作品标签恢复到指定修改之前的状态，回滚本身也会记录为一次修改
+func (s *artworkService) RevertTags(ctx context.Context, id, revisionID uint) (*models.ArtworkResponse, error) {
+	var updated *models.Artwork
+	err := s.repo.Transaction(func(r repo.ArtworkRepo) error {
+		revision, err := r.GetTagRevision(revisionID)
+		if err != nil {
+			return err
+		}
+		if revision.ArtworkID != id {
+			return ErrRevisionMismatch
+		}
+
+		artwork, err := r.GetByID(id)
+		if err != nil {
+			return err
+		}
+
+		before := artwork.ToResponse().Tags
+		after, err := s.tags.Canonicalize(revision.BeforeTags())
+		if err != nil {
+			return err
+		}
+
+		if !slices.Equal(before, after) {
+			if err := s.saveTags(ctx, r, artwork, before, after, models.TagRevisionRevert, &revision.ID); err != nil {
+				return err
+			}
+		}
+
+		updated, err = r.GetByID(id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := s.toResponse(updated)
+	return &resp, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"pln/models"
+	"pln/repo"
+)
+
+type AuditService interface {
+	// List 按时间倒序查询审计记录
+	List(query models.AuditLogQuery, page, pageSize int) ([]models.AuditLogResponse, int64, error)
+	// Prune 删除超过保留期限的审计记录，返回删除的条数
+	Prune(retention time.Duration) (int64, error)
+}
+
+type auditService struct {
+	repo repo.AuditRepo
+}
+
+func NewAuditService(repo repo.AuditRepo) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) List(query models.AuditLogQuery, page, pageSize int) ([]models.AuditLogResponse, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	query.Offset = (page - 1) * pageSize
+	query.Limit = pageSize
+
+	logs, total, err := s.repo.List(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]models.AuditLogResponse, 0, len(logs))
+	for i := range logs {
+		result = append(result, logs[i].ToResponse())
+	}
+	return result, total, nil
+}
+
+func (s *auditService) Prune(retention time.Duration) (int64, error) {
+	return s.repo.DeleteBefore(time.Now().Add(-retention))
+}
+
+// recordAudit 在同一 repo（事务）中写入审计记录，操作者与请求信息取自 ctx；
+// before 与 after 没有差异时不记录
+func recordAudit(ctx context.Context, r repo.AuditWriter, artworkID uint, action string, before, after any) error {
+	entry, err := models.NewAuditLog(artworkID, action, before, after)
+	if err != nil || entry == nil {
+		return err
+	}
+	return writeAudit(ctx, r, entry)
+}
+
+// recordTargetAudit 记录 API Key、用户等非作品对象的操作，target 形如 apikey:3
+func recordTargetAudit(ctx context.Context, r repo.AuditWriter, target, action string, before, after any) error {
+	entry, err := models.NewAuditLog(0, action, before, after)
+	if err != nil || entry == nil {
+		return err
+	}
+	entry.Target = target
+	return writeAudit(ctx, r, entry)
+}
+
+func writeAudit(ctx context.Context, r repo.AuditWriter, entry *models.AuditLog) error {
+	info := RequestFrom(ctx)
+	entry.Actor = ActorFrom(ctx)
+	entry.RequestID = info.ID
+	entry.IP = info.IP
+	return r.CreateAuditLog(entry)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"pln/models"
+	"pln/repo"
+)
+
+func TestAdminActionsAudited(t *testing.T) {
+	db := newTestDB(t)
+	ctx := WithRequest(WithActor(t.Context(), "user:admin"), RequestInfo{ID: "req-1", IP: "10.0.0.1"})
+	keys := NewAPIKeyService(repo.NewAPIKeyRepo(db))
+	users := NewUserService(repo.NewUserRepo(db), 0)
+	audit := NewAuditService(repo.NewAuditRepo(db))
+
+	created, err := keys.Create(ctx, &models.APIKeyCreateRequest{Name: "ci", Scopes: []string{models.ScopeUpload}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := keys.Rotate(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := keys.Revoke(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := users.CreateUser(ctx, &models.UserCreateRequest{Username: "bob", Password: "password123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	role := models.RoleAdmin
+	if _, err := users.UpdateUser(ctx, user.ID, &models.UserUpdateRequest{Role: &role}); err != nil {
+		t.Fatal(err)
+	}
+
+	logs, _, err := audit.List(models.AuditLogQuery{}, 1, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{models.AuditUserUpdate, models.AuditUserCreate, models.AuditAPIKeyRevoke, models.AuditAPIKeyRotate, models.AuditAPIKeyCreate}
+	if len(logs) != len(want) {
+		t.Fatalf("got %d audit logs, want %d", len(logs), len(want))
+	}
+	for i, entry := range logs {
+		if entry.Action != want[i] {
+			t.Errorf("log %d: action %q, want %q", i, entry.Action, want[i])
+		}
+		if entry.Actor != "user:admin" || entry.RequestID != "req-1" || entry.IP != "10.0.0.1" {
+			t.Errorf("log %d: actor %q request %q ip %q", i, entry.Actor, entry.RequestID, entry.IP)
+		}
+		// 审计记录不能包含密钥明文或哈希
+		for _, secret := range []string{created.Key, rotated.Key, hashToken(created.Key), hashToken(rotated.Key)} {
+			if strings.Contains(entry.AuditLog.Diff, secret) {
+				t.Errorf("log %d leaks key material", i)
+			}
+		}
+	}
+
+	if got := logs[0].Target; got != userTarget(user.ID) {
+		t.Errorf("role change target %q", got)
+	}
+	if change := logs[0].Diff["role"]; change.From != models.RoleViewer || change.To != models.RoleAdmin {
+		t.Errorf("role change diff %+v", change)
+	}
+	if change, ok := logs[3].Diff["prefix"]; !ok || change.To != rotated.Prefix {
+		t.Errorf("rotate diff %+v", logs[3].Diff)
+	}
+}
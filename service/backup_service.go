@@ -0,0 +1,494 @@
+package service
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const (
+	backupManifestName = "manifest.json"
+	backupDatabaseName = "database.db"
+	backupFilesPrefix  = "files/"
+	backupVersion      = 1
+)
+
+// BackupManifest 备份清单，记录数据库快照与存储目录中每个文件的哈希
+type BackupManifest struct {
+	Version     int          `json:"version"`
+	CreatedAt   time.Time    `json:"created_at"`
+	Incremental bool         `json:"incremental"`            // 是否为增量备份
+	BaseCreated *time.Time   `json:"base_created,omitempty"` // 增量备份所基于的备份时间
+	Database    BackupFile   `json:"database"`
+	Files       []BackupFile `json:"files"`
+}
+
+// BackupFile 备份中的单个文件
+type BackupFile struct {
+	Path     string `json:"path"` // 相对存储目录的路径（使用 / 分隔）
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+	Included bool   `json:"included"` // 增量备份中未变化的文件不会打包，仅记录哈希
+}
+
+type BackupService struct {
+	db          *gorm.DB
+	storagePath string
+}
+
+func NewBackupService(db *gorm.DB, storagePath string) *BackupService {
+	return &BackupService{db: db, storagePath: storagePath}
+}
+
+// Backup 生成一致的数据库快照并与存储目录一起写入 tar 归档。
+// base 不为空时生成增量备份：哈希与 base 一致的文件只写入清单，不打包内容。
+func (s *BackupService) Backup(ctx context.Context, w io.Writer, base *BackupManifest) (*BackupManifest, error) {
+	logger := log.Ctx(ctx).With().Str("component", "BackupService").Logger()
+
+	tmpDir, err := os.MkdirTemp("", "pln-backup-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// VACUUM INTO 在一个读事务中完成，得到的是某一时刻的完整快照
+	snapshot := filepath.Join(tmpDir, backupDatabaseName)
+	if err := s.db.WithContext(ctx).Exec("VACUUM INTO ?", snapshot).Error; err != nil {
+		return nil, fmt.Errorf("生成数据库快照失败: %w", err)
+	}
+	logger.Debug().Str("snapshot", snapshot).Msg("数据库快照完成")
+
+	baseHashes := map[string]string{}
+	if base != nil {
+		for _, f := range base.Files {
+			baseHashes[f.Path] = f.SHA256
+		}
+	}
+
+	manifest := &BackupManifest{
+		Version:     backupVersion,
+		CreatedAt:   time.Now(),
+		Incremental: base != nil,
+	}
+	if base != nil {
+		created := base.CreatedAt
+		manifest.BaseCreated = &created
+	}
+
+	tw := tar.NewWriter(w)
+
+	dbFile, err := writeTarFile(tw, snapshot, backupDatabaseName)
+	if err != nil {
+		return nil, fmt.Errorf("写入数据库快照失败: %w", err)
+	}
+	manifest.Database = *dbFile
+
+	files, err := listStorageFiles(s.storagePath)
+	if err != nil {
+		return nil, fmt.Errorf("扫描存储目录失败: %w", err)
+	}
+
+	for _, rel := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		full := filepath.Join(s.storagePath, filepath.FromSlash(rel))
+
+		if base != nil {
+			hash, size, err := hashFile(full)
+			if err != nil {
+				// 快照之后被删除的文件直接跳过
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				return nil, err
+			}
+			if baseHashes[rel] == hash {
+				manifest.Files = append(manifest.Files, BackupFile{Path: rel, Size: size, SHA256: hash})
+				continue
+			}
+		}
+
+		f, err := writeTarFile(tw, full, backupFilesPrefix+rel)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("写入文件 %s 失败: %w", rel, err)
+		}
+		f.Path = rel
+		manifest.Files = append(manifest.Files, *f)
+	}
+
+	// 清单放在最后，此时所有哈希都已在写入过程中计算完成
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    backupManifestName,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: manifest.CreatedAt,
+	}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	logger.Info().
+		Int("files", len(manifest.Files)).
+		Bool("incremental", manifest.Incremental).
+		Msg("备份完成")
+
+	return manifest, nil
+}
+
+// RestoreBackup 校验备份归档并替换数据库与存储目录。
+// 必须在服务停止时执行；被替换的旧数据会以 .bak-<时间戳> 后缀保留。
+func RestoreBackup(r io.Reader, dbPath, storagePath string) (*BackupManifest, error) {
+	stamp := time.Now().Format("20060102150405")
+
+	// 暂存目录与目标位于同一目录下，保证最后一步 rename 不跨文件系统
+	parent := filepath.Dir(filepath.Clean(storagePath))
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return nil, err
+	}
+	staging, err := os.MkdirTemp(parent, ".restore-*")
+	if err != nil {
+		return nil, fmt.Errorf("创建暂存目录失败: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if err := extractTar(r, staging); err != nil {
+		return nil, fmt.Errorf("解压备份失败: %w", err)
+	}
+
+	manifest, err := validateBackup(staging, storagePath)
+	if err != nil {
+		return nil, fmt.Errorf("备份校验失败: %w", err)
+	}
+
+	// 增量备份中未打包的文件从当前存储目录补齐
+	stagedFiles := filepath.Join(staging, strings.TrimSuffix(backupFilesPrefix, "/"))
+	if err := os.MkdirAll(stagedFiles, 0755); err != nil {
+		return nil, err
+	}
+	for _, f := range manifest.Files {
+		if f.Included {
+			continue
+		}
+		dst := filepath.Join(stagedFiles, filepath.FromSlash(f.Path))
+		if err := copyFile(filepath.Join(storagePath, filepath.FromSlash(f.Path)), dst); err != nil {
+			return nil, fmt.Errorf("复制未变化的文件 %s 失败: %w", f.Path, err)
+		}
+	}
+
+	// 新数据库先写到目标旁边，最后一步只需 rename
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, err
+	}
+	stagedDB := dbPath + ".restore-" + stamp
+	if err := copyFile(filepath.Join(staging, backupDatabaseName), stagedDB); err != nil {
+		os.Remove(stagedDB)
+		return nil, fmt.Errorf("写入数据库失败: %w", err)
+	}
+	defer os.Remove(stagedDB)
+
+	// 先替换存储目录，失败时数据库保持不变
+	storageBak := storagePath + ".bak-" + stamp
+	_, err = os.Stat(storagePath)
+	hadStorage := err == nil
+	if hadStorage {
+		if err := os.Rename(storagePath, storageBak); err != nil {
+			return nil, fmt.Errorf("备份当前存储目录失败: %w", err)
+		}
+	}
+	restoreStorage := func() {
+		if hadStorage {
+			_ = os.RemoveAll(storagePath)
+			_ = os.Rename(storageBak, storagePath)
+		}
+	}
+	if err := os.Rename(stagedFiles, storagePath); err != nil {
+		restoreStorage()
+		return nil, fmt.Errorf("替换存储目录失败: %w", err)
+	}
+
+	// 最后替换数据库，失败时把存储目录一并还原，避免数据库与文件不一致
+	dbBak := dbPath + ".bak-" + stamp
+	_, err = os.Stat(dbPath)
+	hadDB := err == nil
+	if hadDB {
+		if err := os.Rename(dbPath, dbBak); err != nil {
+			restoreStorage()
+			return nil, fmt.Errorf("备份当前数据库失败: %w", err)
+		}
+	}
+	// WAL 文件属于旧数据库，随旧数据库一起保留
+	walSuffixes := []string{"-wal", "-shm"}
+	for _, suffix := range walSuffixes {
+		_ = os.Rename(dbPath+suffix, dbBak+suffix)
+	}
+	if err := os.Rename(stagedDB, dbPath); err != nil {
+		if hadDB {
+			_ = os.Rename(dbBak, dbPath)
+		}
+		for _, suffix := range walSuffixes {
+			_ = os.Rename(dbBak+suffix, dbPath+suffix)
+		}
+		restoreStorage()
+		return nil, fmt.Errorf("替换数据库失败: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// ReadBackupManifest 从备份归档中读取清单（用于增量备份）
+func ReadBackupManifest(r io.Reader) (*BackupManifest, error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Name == backupManifestName {
+			var manifest BackupManifest
+			if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+				return nil, fmt.Errorf("解析备份清单失败: %w", err)
+			}
+			return &manifest, nil
+		}
+	}
+	return nil, errors.New("备份中缺少清单")
+}
+
+// validateBackup 校验暂存目录中的备份：清单、文件哈希、数据库完整性
+func validateBackup(staging, storagePath string) (*BackupManifest, error) {
+	data, err := os.ReadFile(filepath.Join(staging, backupManifestName))
+	if err != nil {
+		return nil, errors.New("备份中缺少清单")
+	}
+
+	var manifest BackupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("解析备份清单失败: %w", err)
+	}
+	if manifest.Version != backupVersion {
+		return nil, fmt.Errorf("不支持的备份版本: %d", manifest.Version)
+	}
+
+	if err := verifyFile(filepath.Join(staging, backupDatabaseName), manifest.Database); err != nil {
+		return nil, fmt.Errorf("数据库快照: %w", err)
+	}
+
+	for _, f := range manifest.Files {
+		if !isSafeRelPath(f.Path) {
+			return nil, fmt.Errorf("非法的文件路径: %s", f.Path)
+		}
+		src := filepath.Join(staging, filepath.FromSlash(backupFilesPrefix+f.Path))
+		if !f.Included {
+			// 增量备份依赖当前存储目录中的文件
+			src = filepath.Join(storagePath, filepath.FromSlash(f.Path))
+		}
+		if err := verifyFile(src, f); err != nil {
+			return nil, fmt.Errorf("文件 %s: %w", f.Path, err)
+		}
+	}
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(staging, backupDatabaseName)), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("打开数据库快照失败: %w", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	defer sqlDB.Close()
+
+	var result string
+	if err := db.Raw("PRAGMA integrity_check").Scan(&result).Error; err != nil {
+		return nil, fmt.Errorf("数据库完整性检查失败: %w", err)
+	}
+	if result != "ok" {
+		return nil, fmt.Errorf("数据库完整性检查失败: %s", result)
+	}
+	if !db.Migrator().HasTable("artworks") {
+		return nil, errors.New("数据库快照中缺少 artworks 表")
+	}
+
+	return &manifest, nil
+}
+
+func verifyFile(p string, expected BackupFile) error {
+	hash, size, err := hashFile(p)
+	if err != nil {
+		return err
+	}
+	if size != expected.Size || hash != expected.SHA256 {
+		return errors.New("哈希不匹配")
+	}
+	return nil
+}
+
+// writeTarFile 将文件写入 tar，并在写入过程中计算哈希
+func writeTarFile(tw *tar.Writer, src, name string) (*BackupFile, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    stat.Size(),
+		ModTime: stat.ModTime(),
+	}); err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	if _, err := io.CopyN(io.MultiWriter(tw, h), f, stat.Size()); err != nil {
+		return nil, err
+	}
+
+	return &BackupFile{
+		Path:     name,
+		Size:     stat.Size(),
+		SHA256:   hex.EncodeToString(h.Sum(nil)),
+		Included: true,
+	}, nil
+}
+
+func extractTar(r io.Reader, dst string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if !isSafeRelPath(hdr.Name) {
+			return fmt.Errorf("非法的归档路径: %s", hdr.Name)
+		}
+
+		target := filepath.Join(dst, filepath.FromSlash(hdr.Name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+	}
+}
+
+// listStorageFiles 列出存储目录下的所有文件（相对路径，使用 / 分隔）
+func listStorageFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+func hashFile(p string) (string, int64, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func isSafeRelPath(p string) bool {
+	if p == "" || strings.HasPrefix(p, "/") || strings.Contains(p, "\\") {
+		return false
+	}
+	clean := path.Clean(p)
+	return clean == p && clean != ".." && !strings.HasPrefix(clean, "../")
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"pln/models"
+	"pln/repo"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxBookmarkFolders    = 100 // 每个客户端的收藏夹上限
+	maxBookmarkFolderName = 64  // 收藏夹名称的字符数上限
+	maxBookmarkImport     = 500 // 单次导入的收藏上限
+)
+
+var (
+	ErrInvalidBookmark      = errors.New("无效的收藏信息")
+	ErrBookmarkFolderExists = errors.New("收藏夹已存在")
+	// ErrBookmarkNotFound 作品未被当前客户端收藏
+	ErrBookmarkNotFound = errors.New("收藏不存在")
+)
+
+// BookmarkService 按客户端标识（登录用户、API Key 或浏览器标识）管理收藏与收藏夹。
+// 收藏本身即 bookmark 类型的 Reaction，收藏与取消收藏仍通过 ReactionService
+type BookmarkService interface {
+	ListFolders(client string) ([]models.BookmarkFolderResponse, error)
+	CreateFolder(client string, req *models.BookmarkFolderRequest) (*models.BookmarkFolder, error)
+	RenameFolder(client string, id uint, req *models.BookmarkFolderRequest) (*models.BookmarkFolder, error)
+	// DeleteFolder 删除收藏夹，其中的收藏变为未分类
+	DeleteFolder(client string, id uint) error
+
+	// List 按收藏时间倒序列出收藏，folderID 为 nil 时列出全部，指向 0 时只列出未分类的收藏
+	List(client string, folderID *uint, ratings []string, page, pageSize int) ([]models.BookmarkItem, int64, error)
+	// Move 将收藏移动到收藏夹，folderID 为 nil 表示移出收藏夹
+	Move(client string, artworkID uint, folderID *uint) error
+	// Import 导入前端 localStorage 中的收藏记录，已收藏的作品保持不变。
+	// 每新增一条收藏前调用 charge，返回错误时其余记录不再导入，在结果中列为 deferred
+	Import(client string, records map[string]models.LocalBookmark, folderID *uint, charge func(n int) error) (*models.BookmarkImportResult, error)
+}
+
+type bookmarkService struct {
+	repo     repo.BookmarkRepo
+	artworks repo.ArtworkRepo
+	tags     TagService
+}
+
+func NewBookmarkService(repo repo.BookmarkRepo, artworks repo.ArtworkRepo, tags TagService) BookmarkService {
+	return &bookmarkService{repo: repo, artworks: artworks, tags: tags}
+}
+
+func (s *bookmarkService) ListFolders(client string) ([]models.BookmarkFolderResponse, error) {
+	if client == "" {
+		return []models.BookmarkFolderResponse{}, nil
+	}
+	return s.repo.ListFolders(client)
+}
+
+func (s *bookmarkService) CreateFolder(client string, req *models.BookmarkFolderRequest) (*models.BookmarkFolder, error) {
+	if client == "" {
+		return nil, ErrNoClient
+	}
+	name, err := folderName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	folder := &models.BookmarkFolder{Owner: client, Name: name}
+	err = s.repo.Transaction(func(r repo.BookmarkRepo) error {
+		folders, err := r.ListFolders(client)
+		if err != nil {
+			return err
+		}
+		if len(folders) >= maxBookmarkFolders {
+			return fmt.Errorf("%w: 收藏夹最多 %d 个", ErrInvalidBookmark, maxBookmarkFolders)
+		}
+		if err := checkFolderName(r, client, name, 0); err != nil {
+			return err
+		}
+		return r.CreateFolder(folder)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+func (s *bookmarkService) RenameFolder(client string, id uint, req *models.BookmarkFolderRequest) (*models.BookmarkFolder, error) {
+	if client == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	name, err := folderName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var folder *models.BookmarkFolder
+	err = s.repo.Transaction(func(r repo.BookmarkRepo) error {
+		folder, err = r.GetFolder(client, id)
+		if err != nil {
+			return err
+		}
+		if err := checkFolderName(r, client, name, id); err != nil {
+			return err
+		}
+		folder.Name = name
+		return r.UpdateFolder(folder)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+func (s *bookmarkService) DeleteFolder(client string, id uint) error {
+	if client == "" {
+		return gorm.ErrRecordNotFound
+	}
+	return s.repo.DeleteFolder(client, id)
+}
+
+func (s *bookmarkService) List(client string, folderID *uint, ratings []string, page, pageSize int) ([]models.BookmarkItem, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	if client == "" {
+		return []models.BookmarkItem{}, 0, nil
+	}
+
+	rows, total, err := s.repo.ListBookmarks(client, folderID, ratings, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]models.BookmarkItem, 0, len(rows))
+	for i := range rows {
+		resp := rows[i].ToResponse()
+		resp.TagDetails = s.tags.Describe(resp.Tags)
+		items = append(items, models.BookmarkItem{
+			ArtworkResponse: resp,
+			FolderID:        rows[i].FolderID,
+			BookmarkedAt:    rows[i].BookmarkedAt,
+		})
+	}
+	return items, total, nil
+}
+
+func (s *bookmarkService) Move(client string, artworkID uint, folderID *uint) error {
+	if client == "" {
+		return ErrBookmarkNotFound
+	}
+	if err := s.checkFolder(client, folderID); err != nil {
+		return err
+	}
+
+	found, err := s.repo.MoveBookmark(client, artworkID, folderID)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+func (s *bookmarkService) Import(client string, records map[string]models.LocalBookmark, folderID *uint, charge func(n int) error) (*models.BookmarkImportResult, error) {
+	if client == "" {
+		return nil, ErrNoClient
+	}
+	if len(records) > maxBookmarkImport {
+		return nil, fmt.Errorf("%w: 单次最多导入 %d 条收藏", ErrInvalidBookmark, maxBookmarkImport)
+	}
+	if err := s.checkFolder(client, folderID); err != nil {
+		return nil, err
+	}
+
+	// 记录以作品 ID 为键，artworkId 缺失时以键为准；按收藏时间导入，保持原有顺序
+	bookmarks := make([]models.LocalBookmark, 0, len(records))
+	for key, record := range records {
+		if !record.Bookmarked {
+			continue
+		}
+		if record.ArtworkID == 0 {
+			id, err := strconv.ParseUint(key, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%w: 无效的作品 ID %q", ErrInvalidBookmark, key)
+			}
+			record.ArtworkID = uint(id)
+		}
+		bookmarks = append(bookmarks, record)
+	}
+	slices.SortFunc(bookmarks, func(a, b models.LocalBookmark) int {
+		return cmp.Or(cmp.Compare(a.Timestamp, b.Timestamp), cmp.Compare(a.ArtworkID, b.ArtworkID))
+	})
+
+	result := &models.BookmarkImportResult{Skipped: []uint{}, Deferred: []uint{}}
+	now := time.Now()
+	err := s.artworks.Transaction(func(r repo.ArtworkRepo) error {
+		ids := make([]uint, 0, len(bookmarks))
+		for _, b := range bookmarks {
+			ids = append(ids, b.ArtworkID)
+		}
+		reactions, err := r.ListReactions(client, ids)
+		if err != nil {
+			return err
+		}
+		bookmarked := make(map[uint]bool, len(reactions))
+		for _, reaction := range reactions {
+			if reaction.Kind == models.ReactionBookmark {
+				bookmarked[reaction.ArtworkID] = true
+			}
+		}
+
+		limited := false
+		for _, b := range bookmarks {
+			if bookmarked[b.ArtworkID] {
+				result.Existing++
+				continue
+			}
+			if limited {
+				result.Deferred = append(result.Deferred, b.ArtworkID)
+				continue
+			}
+
+			artwork, err := r.GetByID(b.ArtworkID)
+			if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && artwork.Status != models.StatusApproved) {
+				result.Skipped = append(result.Skipped, b.ArtworkID)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			// 只有新增的收藏计入限流，超出后其余记录留待下次导入
+			if charge != nil && charge(1) != nil {
+				limited = true
+				result.Deferred = append(result.Deferred, b.ArtworkID)
+				continue
+			}
+
+			reaction := &models.Reaction{ArtworkID: b.ArtworkID, Client: client, Kind: models.ReactionBookmark, FolderID: folderID}
+			if at := time.UnixMilli(b.Timestamp); b.Timestamp > 0 && at.Before(now) {
+				reaction.CreatedAt = at
+			}
+			added, err := r.AddReaction(reaction)
+			if err != nil {
+				return err
+			}
+			if !added {
+				result.Existing++
+				continue
+			}
+			if err := r.IncrementBookmarks(b.ArtworkID); err != nil {
+				return err
+			}
+			result.Imported++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkFolder 收藏夹必须属于当前客户端
+func (s *bookmarkService) checkFolder(client string, folderID *uint) error {
+	if folderID == nil {
+		return nil
+	}
+	if _, err := s.repo.GetFolder(client, *folderID); errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: 收藏夹 %d 不存在", ErrInvalidBookmark, *folderID)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// folderName 校验并规范化收藏夹名称
+func folderName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxBookmarkFolderName {
+		return "", fmt.Errorf("%w: 收藏夹名称不能为空且不超过 %d 个字符", ErrInvalidBookmark, maxBookmarkFolderName)
+	}
+	return name, nil
+}
+
+// checkFolderName 同一客户端的收藏夹不能重名，exceptID 为正在重命名的收藏夹
+func checkFolderName(r repo.BookmarkRepo, client, name string, exceptID uint) error {
+	existing, err := r.GetFolderByName(client, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != exceptID {
+		return ErrBookmarkFolderExists
+	}
+	return nil
+}
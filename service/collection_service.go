@@ -0,0 +1,390 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"pln/models"
+	"pln/repo"
+)
+
+// 单次加入作品集的作品数上限
+const maxCollectionBatch = 1000
+
+var (
+	ErrInvalidCollection = errors.New("无效的作品集操作")
+	ErrCoverNotInItems   = errors.New("封面作品不在作品集中")
+)
+
+type CollectionService interface {
+	// ListCollections 与 GetCollection 的作品数与封面只计入分级在 ratings 中的作品，ratings 为空时不限制
+	ListCollections(page, pageSize int, ratings []string) ([]models.CollectionResponse, int64, error)
+	GetCollection(id uint, ratings []string) (*models.CollectionResponse, error)
+	CreateCollection(req *models.CollectionRequest) (*models.CollectionResponse, error)
+	UpdateCollection(id uint, req *models.CollectionUpdateRequest) (*models.CollectionResponse, error)
+	DeleteCollection(id uint) error
+
+	// ListArtworks 按作品集中的顺序分页列出作品
+	ListArtworks(id uint, page, pageSize int, ratings []string) ([]models.ArtworkResponse, int64, error)
+	// AddArtworks 批量加入作品，已在作品集中的作品会被忽略
+	AddArtworks(id uint, req *models.CollectionItemsRequest) (*models.CollectionItemsResult, error)
+	RemoveArtworks(id uint, artworkIDs []uint) (*models.CollectionItemsResult, error)
+	// Reorder 按给定顺序重排作品集，需给出作品集中的全部作品
+	Reorder(id uint, artworkIDs []uint) (*models.CollectionResponse, error)
+}
+
+type collectionService struct {
+	repo        repo.CollectionRepo
+	artworkRepo repo.ArtworkRepo
+	tags        TagService
+}
+
+func NewCollectionService(repo repo.CollectionRepo, artworkRepo repo.ArtworkRepo, tags TagService) CollectionService {
+	return &collectionService{repo: repo, artworkRepo: artworkRepo, tags: tags}
+}
+
+func (s *collectionService) ListCollections(page, pageSize int, ratings []string) ([]models.CollectionResponse, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	collections, total, err := s.repo.List((page-1)*pageSize, pageSize, ratings)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses, err := s.toResponses(collections, ratings)
+	if err != nil {
+		return nil, 0, err
+	}
+	return responses, total, nil
+}
+
+func (s *collectionService) GetCollection(id uint, ratings []string) (*models.CollectionResponse, error) {
+	collection, err := s.repo.GetByID(id, ratings)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.toResponses([]models.CollectionStats{*collection}, ratings)
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+func (s *collectionService) CreateCollection(req *models.CollectionRequest) (*models.CollectionResponse, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, fmt.Errorf("%w: 标题不能为空", ErrInvalidCollection)
+	}
+
+	ids := uniqueIDs(req.ArtworkIDs)
+	if err := s.checkArtworks(ids); err != nil {
+		return nil, err
+	}
+	if req.CoverArtworkID != nil && !slices.Contains(ids, *req.CoverArtworkID) {
+		return nil, ErrCoverNotInItems
+	}
+
+	collection := &models.Collection{
+		Title:          title,
+		Description:    strings.TrimSpace(req.Description),
+		CoverArtworkID: req.CoverArtworkID,
+	}
+	err := s.repo.Transaction(func(r repo.CollectionRepo) error {
+		if err := r.Create(collection); err != nil {
+			return err
+		}
+		return r.SetItems(collection.ID, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCollection(collection.ID, nil)
+}
+
+func (s *collectionService) UpdateCollection(id uint, req *models.CollectionUpdateRequest) (*models.CollectionResponse, error) {
+	err := s.repo.Transaction(func(r repo.CollectionRepo) error {
+		stats, err := r.GetByID(id, nil)
+		if err != nil {
+			return err
+		}
+		collection := stats.Collection
+
+		if req.Title != nil {
+			title := strings.TrimSpace(*req.Title)
+			if title == "" {
+				return fmt.Errorf("%w: 标题不能为空", ErrInvalidCollection)
+			}
+			collection.Title = title
+		}
+		if req.Description != nil {
+			collection.Description = strings.TrimSpace(*req.Description)
+		}
+		if req.CoverArtworkID != nil {
+			if *req.CoverArtworkID == 0 {
+				collection.CoverArtworkID = nil
+			} else {
+				ids, err := r.ItemIDs(id)
+				if err != nil {
+					return err
+				}
+				if !slices.Contains(ids, *req.CoverArtworkID) {
+					return ErrCoverNotInItems
+				}
+				collection.CoverArtworkID = req.CoverArtworkID
+			}
+		}
+
+		return r.Update(&collection)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCollection(id, nil)
+}
+
+func (s *collectionService) DeleteCollection(id uint) error {
+	return s.repo.Delete(id)
+}
+
+func (s *collectionService) ListArtworks(id uint, page, pageSize int, ratings []string) ([]models.ArtworkResponse, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	if _, err := s.repo.GetByID(id, nil); err != nil {
+		return nil, 0, err
+	}
+
+	artworks, total, err := s.repo.ListArtworks(id, (page-1)*pageSize, pageSize, ratings)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]models.ArtworkResponse, 0, len(artworks))
+	for _, artwork := range artworks {
+		resp := artwork.ToResponse()
+		resp.TagDetails = s.tags.Describe(resp.Tags)
+		responses = append(responses, resp)
+	}
+	return responses, total, nil
+}
+
+func (s *collectionService) AddArtworks(id uint, req *models.CollectionItemsRequest) (*models.CollectionItemsResult, error) {
+	ids := uniqueIDs(req.ArtworkIDs)
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: 未指定作品", ErrInvalidCollection)
+	}
+	if err := s.checkArtworks(ids); err != nil {
+		return nil, err
+	}
+
+	result := &models.CollectionItemsResult{}
+	err := s.repo.Transaction(func(r repo.CollectionRepo) error {
+		if _, err := r.GetByID(id, nil); err != nil {
+			return err
+		}
+		current, err := r.ItemIDs(id)
+		if err != nil {
+			return err
+		}
+
+		added := make([]uint, 0, len(ids))
+		for _, artworkID := range ids {
+			if !slices.Contains(current, artworkID) {
+				added = append(added, artworkID)
+			}
+		}
+		result.Added = len(added)
+		if len(added) == 0 {
+			return nil
+		}
+
+		pos := len(current)
+		if req.Position != nil {
+			pos = min(max(*req.Position, 0), len(current))
+		}
+		return r.SetItems(id, slices.Insert(current, pos, added...))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.itemsResult(id, result)
+}
+
+func (s *collectionService) RemoveArtworks(id uint, artworkIDs []uint) (*models.CollectionItemsResult, error) {
+	if len(artworkIDs) == 0 {
+		return nil, fmt.Errorf("%w: 未指定作品", ErrInvalidCollection)
+	}
+
+	result := &models.CollectionItemsResult{}
+	err := s.repo.Transaction(func(r repo.CollectionRepo) error {
+		stats, err := r.GetByID(id, nil)
+		if err != nil {
+			return err
+		}
+		current, err := r.ItemIDs(id)
+		if err != nil {
+			return err
+		}
+
+		kept := slices.DeleteFunc(slices.Clone(current), func(artworkID uint) bool {
+			return slices.Contains(artworkIDs, artworkID)
+		})
+		result.Removed = len(current) - len(kept)
+		if result.Removed == 0 {
+			return nil
+		}
+
+		// 封面被移除时恢复默认封面
+		collection := stats.Collection
+		if collection.CoverArtworkID != nil && !slices.Contains(kept, *collection.CoverArtworkID) {
+			collection.CoverArtworkID = nil
+			if err := r.Update(&collection); err != nil {
+				return err
+			}
+		}
+		return r.SetItems(id, kept)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.itemsResult(id, result)
+}
+
+func (s *collectionService) Reorder(id uint, artworkIDs []uint) (*models.CollectionResponse, error) {
+	err := s.repo.Transaction(func(r repo.CollectionRepo) error {
+		if _, err := r.GetByID(id, nil); err != nil {
+			return err
+		}
+		current, err := r.ItemIDs(id)
+		if err != nil {
+			return err
+		}
+
+		if len(artworkIDs) != len(current) ||
+			!slices.Equal(slices.Sorted(slices.Values(artworkIDs)), slices.Sorted(slices.Values(current))) {
+			return fmt.Errorf("%w: 需按新顺序给出作品集中的全部作品且不能重复", ErrInvalidCollection)
+		}
+		if slices.Equal(artworkIDs, current) {
+			return nil
+		}
+		return r.SetItems(id, artworkIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetCollection(id, nil)
+}
+
+// checkArtworks 确认作品均存在且已审核通过，未公开的作品不会出现在作品集列表中
+func (s *collectionService) checkArtworks(ids []uint) error {
+	if len(ids) > maxCollectionBatch {
+		return fmt.Errorf("%w: 单次最多加入 %d 个作品", ErrInvalidCollection, maxCollectionBatch)
+	}
+	artworks, err := s.artworkRepo.GetByIDs(ids)
+	if err != nil {
+		return err
+	}
+	if len(artworks) != len(ids) {
+		return ErrArtworksNotFound
+	}
+	for _, artwork := range artworks {
+		if artwork.Status != models.StatusApproved {
+			return fmt.Errorf("%w: 作品 %d 尚未审核通过", ErrInvalidCollection, artwork.ID)
+		}
+	}
+	return nil
+}
+
+func (s *collectionService) itemsResult(id uint, result *models.CollectionItemsResult) (*models.CollectionItemsResult, error) {
+	collection, err := s.repo.GetByID(id, nil)
+	if err != nil {
+		return nil, err
+	}
+	result.ArtworkCount = collection.ArtworkCount
+	return result, nil
+}
+
+// toResponses 转换为响应，封面作品一次性查询。
+// 指定的封面未审核通过或分级不在 ratings 中时改用第一个可见的作品。
+func (s *collectionService) toResponses(collections []models.CollectionStats, ratings []string) ([]models.CollectionResponse, error) {
+	coverIDs := make([]uint, 0, len(collections)*2)
+	for _, c := range collections {
+		if c.CoverArtworkID != nil {
+			coverIDs = append(coverIDs, *c.CoverArtworkID)
+		}
+		if c.FirstArtworkID != nil {
+			coverIDs = append(coverIDs, *c.FirstArtworkID)
+		}
+	}
+
+	artworks, err := s.artworkRepo.GetByIDs(uniqueIDs(coverIDs))
+	if err != nil {
+		return nil, err
+	}
+	covers := make(map[uint]*models.CollectionCover, len(artworks))
+	for _, a := range artworks {
+		if a.Status != models.StatusApproved {
+			continue
+		}
+		if len(ratings) > 0 && !slices.Contains(ratings, a.Rating) {
+			continue
+		}
+		covers[a.ID] = &models.CollectionCover{
+			ArtworkID:    a.ID,
+			URL:          a.URL,
+			ThumbnailURL: a.ThumbnailURL,
+			PreviewURL:   a.PreviewURL,
+		}
+	}
+
+	responses := make([]models.CollectionResponse, 0, len(collections))
+	for _, c := range collections {
+		resp := models.CollectionResponse{
+			ID:             c.ID,
+			Title:          c.Title,
+			Description:    c.Description,
+			CoverArtworkID: c.CoverArtworkID,
+			ArtworkCount:   c.ArtworkCount,
+			CreatedAt:      c.CreatedAt,
+			UpdatedAt:      c.UpdatedAt,
+		}
+		if c.CoverArtworkID != nil {
+			resp.Cover = covers[*c.CoverArtworkID]
+		}
+		if resp.Cover == nil && c.FirstArtworkID != nil {
+			resp.Cover = covers[*c.FirstArtworkID]
+		}
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// uniqueIDs 去重并保持原有顺序
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	result := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
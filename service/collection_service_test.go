@@ -0,0 +1,63 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"pln/models"
+	"pln/repo"
+)
+
+func TestCollectionReorderAfterMemberRemoved(t *testing.T) {
+	db := newTestDB(t)
+	artworkRepo := repo.NewArtworkRepo(db)
+	tags := NewTagService(repo.NewTagRepo(db), artworkRepo)
+	collections := NewCollectionService(repo.NewCollectionRepo(db), artworkRepo, tags)
+
+	ids := make([]uint, 3)
+	for i := range ids {
+		artwork := &models.Artwork{FileID: fmt.Sprintf("f%d", i), Hash: fmt.Sprintf("h%d", i), Status: models.StatusApproved}
+		if err := artworkRepo.Create(artwork); err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = artwork.ID
+	}
+
+	collection, err := collections.CreateCollection(&models.CollectionRequest{Title: "c", ArtworkIDs: ids})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 删除作品后，作品集列表中不再出现该作品，按列表内容提交的顺序应当有效
+	if err := artworkRepo.Delete(ids[1]); err != nil {
+		t.Fatal(err)
+	}
+	order := []uint{ids[2], ids[0]}
+	if _, err := collections.Reorder(collection.ID, order); err != nil {
+		t.Fatalf("reorder visible items: %v", err)
+	}
+
+	listed, _, err := collections.ListArtworks(collection.ID, 1, 20, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]uint, 0, len(listed))
+	for _, a := range listed {
+		got = append(got, a.ID)
+	}
+	if !slices.Equal(got, order) {
+		t.Fatalf("listed %v, want %v", got, order)
+	}
+
+	// 待审核的作品不能加入作品集
+	pending := &models.Artwork{FileID: "pending", Hash: "pending", Status: models.StatusPending}
+	if err := artworkRepo.Create(pending); err != nil {
+		t.Fatal(err)
+	}
+	_, err = collections.AddArtworks(collection.ID, &models.CollectionItemsRequest{ArtworkIDs: []uint{pending.ID}})
+	if !errors.Is(err, ErrInvalidCollection) {
+		t.Fatalf("err = %v, want ErrInvalidCollection", err)
+	}
+}
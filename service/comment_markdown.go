@@ -0,0 +1,82 @@
+package service
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 评论支持的 Markdown 子集：`代码`、**粗体**、*斜体*、~~删除线~~、[文字](链接) 与裸链接，
+// 空行分段、单个换行保留。先整体转义 HTML，再生成少量固定的标签，因此输出中不会出现用户提供的标签或属性
+var (
+	mdCode    = regexp.MustCompile("`([^`\n]+)`")
+	mdLink    = regexp.MustCompile(`\[([^\]\n]+)\]\((https?://(?:[^\s)&\x00]|&amp;)+)\)`)
+	mdAutoURL = regexp.MustCompile(`https?://(?:[^\s&\x00]|&amp;)+`) // 引号等转义后的字符不属于链接
+	mdBold    = regexp.MustCompile(`\*\*([^*\n]+)\*\*`)
+	mdItalic  = regexp.MustCompile(`\*([^*\n]+)\*`)
+	mdStrike  = regexp.MustCompile(`~~([^~\n]+)~~`)
+	mdHold    = regexp.MustCompile("\x00([0-9]+)\x00")
+	mdParas   = regexp.MustCompile(`\n{2,}`)
+)
+
+// renderComment 将评论原文渲染为安全的 HTML
+func renderComment(body string) string {
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	body = strings.ReplaceAll(body, "\x00", "")
+	body = html.EscapeString(strings.TrimSpace(body))
+
+	// 代码与链接先替换为占位符，避免其中的内容再被解析
+	var held []string
+	hold := func(s string) string {
+		held = append(held, s)
+		return "\x00" + strconv.Itoa(len(held)-1) + "\x00"
+	}
+
+	body = mdCode.ReplaceAllStringFunc(body, func(m string) string {
+		return hold("<code>" + mdCode.FindStringSubmatch(m)[1] + "</code>")
+	})
+	body = mdLink.ReplaceAllStringFunc(body, func(m string) string {
+		// 链接文字内的强调在占位前渲染，不会与链接外的标记配对
+		sub := mdLink.FindStringSubmatch(m)
+		return hold(commentLink(sub[2], commentEmphasis(sub[1])))
+	})
+	body = mdAutoURL.ReplaceAllStringFunc(body, func(m string) string {
+		// 句末标点不属于链接
+		url := strings.TrimRight(m, ".,;:!?)")
+		return hold(commentLink(url, url)) + m[len(url):]
+	})
+
+	body = commentEmphasis(body)
+
+	// 链接文字中可能还有代码占位符，逐层还原
+	for range 3 {
+		body = mdHold.ReplaceAllStringFunc(body, func(m string) string {
+			i, _ := strconv.Atoi(mdHold.FindStringSubmatch(m)[1])
+			return held[i]
+		})
+	}
+
+	paras := mdParas.Split(body, -1)
+	var b strings.Builder
+	for _, p := range paras {
+		if p = strings.Trim(p, "\n"); p == "" {
+			continue
+		}
+		b.WriteString("<p>" + strings.ReplaceAll(p, "\n", "<br>") + "</p>")
+	}
+	return b.String()
+}
+
+// commentEmphasis 渲染粗体、斜体与删除线
+func commentEmphasis(s string) string {
+	s = mdBold.ReplaceAllString(s, "<strong>$1</strong>")
+	s = mdItalic.ReplaceAllString(s, "<em>$1</em>")
+	return mdStrike.ReplaceAllString(s, "<del>$1</del>")
+}
+
+// commentLink 生成外部链接，url 与 text 均已转义
+func commentLink(url, text string) string {
+	return fmt.Sprintf(`<a href="%s" rel="nofollow ugc noopener noreferrer" target="_blank">%s</a>`, url, text)
+}
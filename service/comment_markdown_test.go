@@ -0,0 +1,54 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderComment(t *testing.T) {
+	link := func(href, text string) string {
+		return `<a href="` + href + `" rel="nofollow ugc noopener noreferrer" target="_blank">` + text + `</a>`
+	}
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"script tag", "<script>alert(1)</script>", "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{"event attribute", `<img src=x onerror="alert(1)">`, "<p>&lt;img src=x onerror=&#34;alert(1)&#34;&gt;</p>"},
+		{"javascript link", "[x](javascript:alert(1))", "<p>[x](javascript:alert(1))</p>"},
+		{"mixed case javascript link", "[x](JaVaScRiPt:alert(1))", "<p>[x](JaVaScRiPt:alert(1))</p>"},
+		{"data link", "[x](data:text/html,<b>)", "<p>[x](data:text/html,&lt;b&gt;)</p>"},
+		{"mixed case http scheme", "[x](HtTpS://example.com)", "<p>[x](HtTpS://example.com)</p>"},
+		{"quote ends link url", `[x](https://e.com/"onmouseover=alert(1))`,
+			"<p>[x](" + link("https://e.com/", "https://e.com/") + "&#34;onmouseover=alert(1))</p>"},
+		{"quote ends bare url", `https://e.com/a"><img src=x>`,
+			"<p>" + link("https://e.com/a", "https://e.com/a") + "&#34;&gt;&lt;img src=x&gt;</p>"},
+		{"angle bracket ends url", "https://e.com/a>b", "<p>" + link("https://e.com/a", "https://e.com/a") + "&gt;b</p>"},
+		{"ampersand in url", "https://e.com/?a=1&b=2",
+			"<p>" + link("https://e.com/?a=1&amp;b=2", "https://e.com/?a=1&amp;b=2") + "</p>"},
+		{"trailing punctuation", "see https://e.com/x.", "<p>see " + link("https://e.com/x", "https://e.com/x") + ".</p>"},
+		{"emphasis inside link", "[**bold** and *it*](https://e.com)",
+			"<p>" + link("https://e.com", "<strong>bold</strong> and <em>it</em>") + "</p>"},
+		{"code inside link", "[run `a*b*c`](https://e.com)",
+			"<p>" + link("https://e.com", "run <code>a*b*c</code>") + "</p>"},
+		{"emphasis does not cross link", "*a [b*](https://e.com)",
+			"<p>*a " + link("https://e.com", "b*") + "</p>"},
+		{"markup inside code", "`**x** [y](https://e.com) <b>`", "<p><code>**x** [y](https://e.com) &lt;b&gt;</code></p>"},
+		{"nested emphasis", "*a **b** c* ~~d~~", "<p><em>a <strong>b</strong> c</em> <del>d</del></p>"},
+		{"placeholder injection", "\x000\x00 [a](https://e.com) \x001\x00", "<p>0 " + link("https://e.com", "a") + " 1</p>"},
+		{"paragraphs", "line1\r\nline2\n\n\npara2", "<p>line1<br>line2</p><p>para2</p>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderComment(tt.in)
+			if got != tt.want {
+				t.Errorf("renderComment(%q)\n got %q\nwant %q", tt.in, got, tt.want)
+			}
+			if strings.Contains(got, "\x00") {
+				t.Errorf("renderComment(%q) leaks a placeholder: %q", tt.in, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"pln/models"
+	"pln/repo"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxCommentLength = 2000 // 评论正文的字符数上限
+	maxCommentAuthor = 32   // 昵称的字符数上限
+	anonymousAuthor  = "匿名"
+)
+
+var (
+	ErrInvalidComment = errors.New("无效的评论")
+	// ErrCommentForbidden 只有作者本人与审核员可以删除评论
+	ErrCommentForbidden = errors.New("无权删除该评论")
+)
+
+type CommentService interface {
+	// List 按发表时间列出作品的评论，moderator 为 true 时包含已隐藏的评论与未公开作品的评论
+	List(artworkID uint, client string, moderator bool, page, pageSize int) ([]models.CommentResponse, int64, error)
+	// Create 发表评论，author 为空时显示为匿名
+	Create(artworkID uint, client, author, ip string, req *models.CommentCreateRequest) (*models.CommentResponse, error)
+	// Delete 删除评论，moderator 为 false 时只能删除 client 自己发表的评论
+	Delete(id uint, client string, moderator bool) error
+	// SetHidden 隐藏或恢复评论，隐藏的评论不计入作品的评论数
+	SetHidden(ctx context.Context, id uint, hidden bool) (*models.CommentResponse, error)
+}
+
+type commentService struct {
+	repo     repo.CommentRepo
+	artworks repo.ArtworkRepo
+}
+
+func NewCommentService(repo repo.CommentRepo, artworks repo.ArtworkRepo) CommentService {
+	return &commentService{repo: repo, artworks: artworks}
+}
+
+func (s *commentService) List(artworkID uint, client string, moderator bool, page, pageSize int) ([]models.CommentResponse, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	artwork, err := s.artworks.GetByID(artworkID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !moderator && artwork.Status != models.StatusApproved {
+		return nil, 0, gorm.ErrRecordNotFound
+	}
+
+	comments, total, err := s.repo.List(artworkID, moderator, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]models.CommentResponse, 0, len(comments))
+	for i := range comments {
+		result = append(result, commentResponse(&comments[i], client))
+	}
+	return result, total, nil
+}
+
+func (s *commentService) Create(artworkID uint, client, author, ip string, req *models.CommentCreateRequest) (*models.CommentResponse, error) {
+	if client == "" {
+		return nil, ErrNoClient
+	}
+
+	body := strings.TrimSpace(req.Body)
+	if body == "" || utf8.RuneCountInString(body) > maxCommentLength {
+		return nil, fmt.Errorf("%w: 评论不能为空且不超过 %d 个字符", ErrInvalidComment, maxCommentLength)
+	}
+	author = strings.TrimSpace(author)
+	if author == "" {
+		author = anonymousAuthor
+	}
+	if utf8.RuneCountInString(author) > maxCommentAuthor {
+		return nil, fmt.Errorf("%w: 昵称不超过 %d 个字符", ErrInvalidComment, maxCommentAuthor)
+	}
+
+	artwork, err := s.artworks.GetByID(artworkID)
+	if err != nil {
+		return nil, err
+	}
+	if artwork.Status != models.StatusApproved {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	comment := &models.Comment{
+		ArtworkID: artworkID,
+		Author:    author,
+		Client:    client,
+		IP:        ip,
+		Body:      body,
+	}
+	err = s.repo.Transaction(func(r repo.CommentRepo) error {
+		if err := r.Create(comment); err != nil {
+			return err
+		}
+		return r.AdjustCount(artworkID, 1)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := commentResponse(comment, client)
+	return &resp, nil
+}
+
+func (s *commentService) Delete(id uint, client string, moderator bool) error {
+	return s.repo.Transaction(func(r repo.CommentRepo) error {
+		comment, err := r.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if !moderator && (client == "" || comment.Client != client) {
+			return ErrCommentForbidden
+		}
+
+		if err := r.Delete(id); err != nil {
+			return err
+		}
+		if comment.Hidden {
+			return nil
+		}
+		return r.AdjustCount(comment.ArtworkID, -1)
+	})
+}
+
+func (s *commentService) SetHidden(ctx context.Context, id uint, hidden bool) (*models.CommentResponse, error) {
+	var comment *models.Comment
+	err := s.repo.Transaction(func(r repo.CommentRepo) error {
+		var err error
+		comment, err = r.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if comment.Hidden == hidden {
+			return nil
+		}
+
+		comment.Hidden = hidden
+		if hidden {
+			now := time.Now()
+			comment.HiddenBy = ActorFrom(ctx)
+			comment.HiddenAt = &now
+		} else {
+			comment.HiddenBy = ""
+			comment.HiddenAt = nil
+		}
+		if err := r.Update(comment); err != nil {
+			return err
+		}
+
+		delta := 1
+		if hidden {
+			delta = -1
+		}
+		return r.AdjustCount(comment.ArtworkID, delta)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := commentResponse(comment, "")
+	return &resp, nil
+}
+
+// commentResponse 渲染正文并标记是否为 client 发表
+func commentResponse(comment *models.Comment, client string) models.CommentResponse {
+	return models.CommentResponse{
+		Comment: *comment,
+		HTML:    renderComment(comment.Body),
+		Mine:    client != "" && comment.Client == client,
+	}
+}
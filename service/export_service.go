@@ -0,0 +1,260 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"pln/models"
+	"pln/repo"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	exportManifestName = "manifest.json"
+	exportFilesDir     = "files/"
+	exportPageSize     = 100
+)
+
+// ExportService 作品库的导出与导入（zip：manifest.json + 原图）
+type ExportService struct {
+	repo        repo.ArtworkRepo
+	artworks    ArtworkService
+	fileService *FileService
+	ingest      *IngestService
+}
+
+func NewExportService(repo repo.ArtworkRepo, artworks ArtworkService, fileService *FileService, ingest *IngestService) *ExportService {
+	return &ExportService{repo: repo, artworks: artworks, fileService: fileService, ingest: ingest}
+}
+
+// Export 按与 ListArtworks 相同的过滤条件导出作品，未指定 status 时包含待审核的作品。
+// 过滤条件在写入归档之前解析，作品集不存在时返回 ErrCollectionNotFound 且不写入任何内容
+func (s *ExportService) Export(ctx context.Context, w io.Writer, filters map[string]any) (*models.ExportManifest, error) {
+	logger := log.Ctx(ctx).With().Str("component", "ExportService").Logger()
+
+	query, err := s.artworks.ResolveFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := query["status"]; !ok {
+		query["status"] = models.StatusAll
+	}
+
+	manifest := &models.ExportManifest{
+		Version:    models.ExportVersion,
+		ExportedAt: time.Now(),
+		Filters:    filters,
+		Items:      []models.ExportItem{},
+	}
+
+	zw := zip.NewWriter(w)
+
+	for offset := 0; ; offset += exportPageSize {
+		artworks, _, err := s.repo.GetAll(offset, exportPageSize, query)
+		if err != nil {
+			return nil, fmt.Errorf("查询作品失败: %w", err)
+		}
+
+		for _, artwork := range artworks {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			item, err := s.exportArtwork(ctx, zw, &artwork)
+			if err != nil {
+				// 单个文件缺失不影响整体导出
+				logger.Warn().Err(err).Uint("artwork_id", artwork.ID).Msg("导出作品失败，跳过")
+				continue
+			}
+			manifest.Items = append(manifest.Items, *item)
+		}
+
+		if len(artworks) < exportPageSize {
+			break
+		}
+	}
+
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: exportManifestName, Method: zip.Deflate, Modified: manifest.ExportedAt})
+	if err != nil {
+		return nil, err
+	}
+	enc := json.NewEncoder(mw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	logger.Info().Int("count", len(manifest.Items)).Msg("导出完成")
+
+	return manifest, nil
+}
+
+func (s *ExportService) exportArtwork(ctx context.Context, zw *zip.Writer, artwork *models.Artwork) (*models.ExportItem, error) {
+	src, info, err := s.fileService.OpenFile(ctx, artwork.FileID)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	name := exportFilesDir + artwork.FileID + strings.ToLower(filepath.Ext(info.Name))
+
+	// 图片本身已压缩，直接存储
+	fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: artwork.CreatedAt})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(fw, src); err != nil {
+		return nil, err
+	}
+
+	resp := artwork.ToResponse()
+
+	return &models.ExportItem{
+		File:        name,
+		Hash:        artwork.Hash,
+		PHash:       artwork.PHash,
+		SourceURL:   artwork.SourceURL,
+		Artist:      artwork.Artist,
+		Title:       artwork.Title,
+		Description: artwork.Description,
+		License:     artwork.License,
+		Rating:      artwork.Rating,
+		Status:      artwork.Status,
+		Tags:        resp.Tags,
+		Views:       artwork.Views,
+		Likes:       artwork.Likes,
+		Bookmarks:   artwork.Bookmarks,
+		CreatedAt:   artwork.CreatedAt,
+		UpdatedAt:   artwork.UpdatedAt,
+	}, nil
+}
+
+// Import 导入导出归档：每个文件都走标准的去重 / pHash 入库流程，
+// 成功后恢复计数与时间戳，重复或相似的条目跳过并在结果中注明
+func (s *ExportService) Import(ctx context.Context, r io.ReaderAt, size int64) ([]models.IngestResult, error) {
+	logger := log.Ctx(ctx).With().Str("component", "ExportService").Logger()
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("读取归档失败: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	mf, ok := files[exportManifestName]
+	if !ok {
+		return nil, errors.New("归档中缺少 manifest.json")
+	}
+	var manifest models.ExportManifest
+	if err := readZipJSON(mf, &manifest); err != nil {
+		return nil, fmt.Errorf("解析 manifest.json 失败: %w", err)
+	}
+	if manifest.Version != models.ExportVersion {
+		return nil, fmt.Errorf("不支持的导出版本: %d", manifest.Version)
+	}
+
+	results := make([]models.IngestResult, 0, len(manifest.Items))
+	for _, item := range manifest.Items {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		artwork, err := s.importItem(ctx, files, &item)
+		results = append(results, NewIngestResult(item.File, artwork, err))
+	}
+
+	logger.Info().Int("total", len(results)).Msg("导入完成")
+
+	return results, nil
+}
+
+func (s *ExportService) importItem(ctx context.Context, files map[string]*zip.File, item *models.ExportItem) (*models.ArtworkResponse, error) {
+	f, ok := files[path.Clean(item.File)]
+	if !ok {
+		return nil, errors.New("归档中缺少文件")
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Hash != "" {
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != item.Hash {
+			return nil, errors.New("文件哈希与清单不一致")
+		}
+	}
+
+	// 只有待审核的状态需要保留，其余按已公开导入
+	status := models.StatusApproved
+	if item.Status == models.StatusPending {
+		status = models.StatusPending
+	}
+
+	artwork, err := s.ingest.Ingest(ctx, &IngestRequest{
+		Filename:    path.Base(item.File),
+		Data:        data,
+		Tags:        item.Tags,
+		SourceURL:   item.SourceURL,
+		Artist:      item.Artist,
+		Title:       item.Title,
+		Description: item.Description,
+		License:     item.License,
+		Rating:      item.Rating,
+		Status:      status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 恢复计数与时间戳
+	columns := map[string]any{
+		"views":     item.Views,
+		"likes":     item.Likes,
+		"bookmarks": item.Bookmarks,
+	}
+	if !item.CreatedAt.IsZero() {
+		columns["created_at"] = item.CreatedAt
+	}
+	if !item.UpdatedAt.IsZero() {
+		columns["updated_at"] = item.UpdatedAt
+	}
+	if err := s.repo.UpdateColumns(artwork.ID, columns); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Uint("artwork_id", artwork.ID).Msg("恢复作品计数失败")
+	}
+
+	return artwork, nil
+}
+
+func readZipJSON(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return json.NewDecoder(rc).Decode(v)
+}
@@ -0,0 +1,474 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"pln/conf"
+	"pln/metrics"
+	"pln/models"
+
+	"github.com/corona10/goimagehash"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// 相似图片判定的汉明距离阈值
+const similarityThreshold = 5
+
+// AllowedImageExts 允许入库的图片格式
+var AllowedImageExts = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+}
+
+var ErrUnsupportedFormat = errors.New("只支持图片格式")
+
+// DuplicateError 图片已存在（Hash 完全一致）、与已有图片过于相似（pHash）或此前已被驳回
+type DuplicateError struct {
+	ArtworkID uint
+	Similar   bool
+	Rejected  bool
+}
+
+func (e *DuplicateError) Error() string {
+	if e.Rejected {
+		return "图片此前已被驳回，不能重复提交"
+	}
+	if e.Similar {
+		return fmt.Sprintf("图片过于相似，已存在，相似ID：%d", e.ArtworkID)
+	}
+	return "图片已存在"
+}
+
+// IngestRequest 入库请求
+type IngestRequest struct {
+	Filename string
+	Data     []byte
+	Tags     []string
+
+	// 来源与署名
+	SourceURL   string
+	Artist      string
+	Title       string
+	Description string
+	License     string
+	Rating      string // 为空时使用实例默认分级
+
+	Status string // 审核状态，为空时直接公开
+}
+
+// IngestService 图片入库流程：Hash 去重 → pHash 相似检测 → 存储并生成变体 → 创建记录
+type IngestService struct {
+	cfg         *conf.AppConfig
+	service     ArtworkService
+	fileService *FileService
+}
+
+func NewIngestService(cfg *conf.AppConfig, service ArtworkService, fileService *FileService) *IngestService {
+	return &IngestService{cfg: cfg, service: service, fileService: fileService}
+}
+
+// Ingest 执行完整的入库流程；重复或相似时返回 *DuplicateError
+func (s *IngestService) Ingest(ctx context.Context, req *IngestRequest) (*models.ArtworkResponse, error) {
+	artwork, err := s.ingest(ctx, req)
+
+	var dup *DuplicateError
+	switch {
+	case err == nil:
+		metrics.ObserveUpload(metrics.UploadCreated, len(req.Data))
+	case errors.As(err, &dup):
+		metrics.ObserveUpload(metrics.UploadDuplicate, len(req.Data))
+	default:
+		metrics.ObserveUpload(metrics.UploadFailed, len(req.Data))
+	}
+
+	return artwork, err
+}
+
+func (s *IngestService) ingest(ctx context.Context, req *IngestRequest) (*models.ArtworkResponse, error) {
+	logger := log.Ctx(ctx).With().
+		Str("component", "IngestService").
+		Str("filename", req.Filename).
+		Logger()
+
+	ext := strings.ToLower(filepath.Ext(req.Filename))
+	if !AllowedImageExts[ext] {
+		logger.Warn().Msg("不支持的文件格式")
+		return nil, ErrUnsupportedFormat
+	}
+	if req.Rating != "" && !models.ValidRating(req.Rating) {
+		return nil, ErrInvalidRating
+	}
+
+	// ============ 步骤 0：计算文件 Hash（提前检测重复）============
+	hash, err := CalculateFileHash(bytes.NewReader(req.Data))
+	if err != nil {
+		return nil, fmt.Errorf("计算文件 Hash 失败: %w", err)
+	}
+
+	logger.Debug().Str("hash", hash).Msg("文件 Hash 计算完成")
+
+	existing, err := s.service.GetByHash(hash)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询 Hash 失败: %w", err)
+	}
+	if existing != nil {
+		logger.Info().Str("hash", hash).Uint("artwork_id", existing.ID).Msg("文件已存在")
+		metrics.ObserveDedupe(metrics.DedupeExact)
+		return nil, &DuplicateError{ArtworkID: existing.ID}
+	}
+
+	// 驳回的作品已被删除但仍保留记录，同一文件再次提交会与其文件 ID 冲突
+	rejected, err := s.service.GetRejectedByHash(hash)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("查询 Hash 失败: %w", err)
+	}
+	if rejected != nil {
+		logger.Info().Str("hash", hash).Uint("artwork_id", rejected.ID).Msg("文件此前已被驳回")
+		metrics.ObserveDedupe(metrics.DedupeExact)
+		return nil, &DuplicateError{ArtworkID: rejected.ID, Rejected: true}
+	}
+
+	// ============ 步骤 0.5：计算 pHash（感知哈希，检测相似图片）============
+	pHash, err := CalculatePHash(bytes.NewReader(req.Data))
+	if err != nil {
+		// pHash 失败不中断流程，只记录日志
+		logger.Warn().Err(err).Msg("计算 pHash 失败，继续处理")
+	} else {
+		logger.Debug().Int64("phash", pHash).Msg("pHash 计算完成")
+
+		similar, err := s.service.GetByPHashSimilarity(pHash, similarityThreshold)
+		if err != nil {
+			logger.Warn().Err(err).Msg("查询相似图片失败")
+		} else if len(similar) > 0 {
+			logger.Info().
+				Int64("phash", pHash).
+				Int("similar_count", len(similar)).
+				Uint("similar_artwork_id", similar[0].ID).
+				Msg("发现相似图片")
+			metrics.ObserveDedupe(metrics.DedupePHash)
+			return nil, &DuplicateError{ArtworkID: similar[0].ID, Similar: true}
+		}
+	}
+
+	// ============ 步骤 1：上传本地存储 ============
+	uploadResp, err := s.fileService.UploadLocalFile(ctx, bytes.NewReader(req.Data), req.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	uploadTask := &models.UploadTask{
+		Hash:              hash,
+		PHash:             pHash,
+		FileID:            uploadResp.FileID,
+		JobID:             uploadResp.JobID,
+		StatusURL:         uploadResp.StatusURL,
+		Status:            models.UploadStatusPending,
+		CreatedAt:         time.Now().Unix(),
+		LastStatusCheckAt: time.Now().Unix(),
+	}
+
+	pollCtx, pollCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer pollCancel()
+	if err := s.PollUploadJobStatus(pollCtx, uploadTask); err != nil {
+		return nil, fmt.Errorf("等待上传任务完成失败: %w", err)
+	}
+
+	// ============ 步骤 2：根据文件信息创建作品 ============
+	info, err := s.fileService.GetFileInfo(uploadResp.FileID)
+	if err != nil {
+		return nil, fmt.Errorf("获取文件信息失败: %w", err)
+	}
+
+	var thumbnailURL, previewURL string
+	for _, v := range info.Variants {
+		switch v.Type {
+		case "thumbnail":
+			thumbnailURL = s.cfg.FileServer.BaseURL + v.AccessURL
+		case "preview":
+			previewURL = s.cfg.FileServer.BaseURL + v.AccessURL
+		}
+	}
+
+	tags := req.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	artwork, err := s.service.CreateArtwork(&models.ArtworkCreateRequest{
+		FileID:       uploadResp.FileID,
+		URL:          s.cfg.FileServer.BaseURL + info.AccessURL,
+		Hash:         hash,
+		PHash:        pHash,
+		ThumbnailURL: thumbnailURL,
+		PreviewURL:   previewURL,
+		SourceURL:    req.SourceURL,
+		Artist:       req.Artist,
+		Title:        req.Title,
+		Description:  req.Description,
+		License:      req.License,
+		Rating:       req.Rating,
+		Status:       req.Status,
+		Tags:         tags,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建条目失败: %w", err)
+	}
+
+	logger.Info().Uint("artwork_id", artwork.ID).Str("status", artwork.Status).Msg("作品入库完成")
+
+	return artwork, nil
+}
+
+// PollUploadJobStatus 轮询存储服务的上传任务，直到完成或超时
+func (s *IngestService) PollUploadJobStatus(ctx context.Context, uploadTask *models.UploadTask) error {
+	logger := log.Ctx(ctx).With().Str("component", "IngestService").Str("job_id", uploadTask.JobID).Logger()
+
+	maxRetries := 20
+	retryInterval := 1 * time.Second
+	retryCount := 0
+
+	for retryCount < maxRetries {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		progress, err := s.fileService.GetJobProgress(ctx, uploadTask.JobID)
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			logger.Error().Err(err).Msg("获取本地存储进度失败")
+			return fmt.Errorf("获取本地存储进度失败: %w", err)
+		}
+
+		if progress != nil && progress.Status == "task.completed" {
+			return nil
+		} else if progress != nil {
+			logger.Info().Str("progress", progress.Status).Msg("等待上传任务完成")
+		}
+
+		// 未完成，等待后重试
+		retryCount++
+		logger.Debug().Int("retry_count", retryCount).Msg("等待中...")
+		time.Sleep(retryInterval)
+	}
+
+	logger.Error().Int("max_retries", maxRetries).Msg("轮询超时")
+
+	return fmt.Errorf("上传任务轮询超时，超过 %d 次尝试", maxRetries)
+}
+
+// CalculateFileHash 计算文件的 SHA256
+func CalculateFileHash(file io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// CalculatePHash 计算图片的感知哈希
+func CalculatePHash(src io.Reader) (int64, error) {
+	// 解码图片
+	img, _, err := image.Decode(src)
+	if err != nil {
+		return 0, fmt.Errorf("解码图片失败: %w", err)
+	}
+
+	hash, err := goimagehash.PerceptionHash(img)
+	if err != nil {
+		return 0, fmt.Errorf("计算 pHash 失败: %w", err)
+	}
+
+	return int64(hash.GetHash()), nil
+}
+
+// NewIngestResult 将单个文件的入库结果转换为对外返回的结构
+func NewIngestResult(file string, artwork *models.ArtworkResponse, err error) models.IngestResult {
+	result := models.IngestResult{File: file}
+
+	var dup *DuplicateError
+	switch {
+	case err == nil:
+		result.Status = models.IngestStatusCreated
+		result.ArtworkID = artwork.ID
+	case errors.As(err, &dup):
+		result.Status = models.IngestStatusDuplicate
+		if dup.Similar {
+			result.Status = models.IngestStatusSimilar
+		}
+		if dup.Rejected {
+			result.Reason = dup.Error()
+		}
+		result.ExistingID = dup.ArtworkID
+	default:
+		result.Status = models.IngestStatusRejected
+		result.Reason = err.Error()
+	}
+
+	return result
+}
+
+// UploadBudget 一次批量上传中剩余可入库的文件数与字节数（压缩包内按解压后计算）。
+// 请求中的全部文件与压缩包共用同一份额度，拆分为多个文件或压缩包不能绕过上限
+type UploadBudget struct {
+	limits  conf.ArchiveConfig
+	entries int
+	bytes   int64
+	charge  func(n int) error
+}
+
+// NewUploadBudget 按 archive 配置创建一次请求的额度；charge 非空时每占用 n 个文件调用一次，
+// 返回错误时拒绝这些文件，用于按文件数限流
+func (s *IngestService) NewUploadBudget(charge func(n int) error) *UploadBudget {
+	limits := s.cfg.Archive
+	return &UploadBudget{limits: limits, entries: limits.MaxEntries, bytes: limits.MaxTotalSize, charge: charge}
+}
+
+// take 占用 n 个文件的额度，不足时不占用
+func (b *UploadBudget) take(n int) error {
+	if b.limits.MaxEntries > 0 && n > b.entries {
+		return fmt.Errorf("本次上传的文件数超过上限 %d", b.limits.MaxEntries)
+	}
+	if b.charge != nil {
+		if err := b.charge(n); err != nil {
+			return err
+		}
+	}
+	b.entries -= n
+	return nil
+}
+
+// fits 声明的总大小是否在剩余的字节额度内
+func (b *UploadBudget) fits(size uint64) bool {
+	return b.limits.MaxTotalSize <= 0 || size <= uint64(b.bytes)
+}
+
+// read 读取一个文件，size 为声明的大小；实际读取的字节以读到的为准，
+// 总量超出时本次及后续文件全部拒绝
+func (b *UploadBudget) read(r io.Reader, size uint64) ([]byte, error) {
+	maxSize := b.limits.MaxEntrySize
+	if maxSize <= 0 {
+		maxSize = math.MaxInt64 - 1
+	}
+	errEntry := fmt.Errorf("文件超过 %d 字节", maxSize)
+	errTotal := fmt.Errorf("本次上传的总大小超过上限 %d 字节", b.limits.MaxTotalSize)
+	if size > uint64(maxSize) {
+		return nil, errEntry
+	}
+
+	readLimit := maxSize
+	if b.limits.MaxTotalSize > 0 {
+		readLimit = min(readLimit, b.bytes)
+	}
+	if readLimit <= 0 {
+		return nil, errTotal
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, readLimit+1))
+	if err != nil {
+		return nil, err
+	}
+	b.bytes -= int64(len(data))
+	if int64(len(data)) > readLimit {
+		if readLimit < maxSize {
+			return nil, errTotal
+		}
+		return nil, errEntry
+	}
+	return data, nil
+}
+
+// IngestFile 在 budget 的额度内读取并入库一个上传的文件
+func (s *IngestService) IngestFile(ctx context.Context, r io.Reader, size int64, req IngestRequest, budget *UploadBudget) (*models.ArtworkResponse, error) {
+	if err := budget.take(1); err != nil {
+		return nil, err
+	}
+	data, err := budget.read(r, uint64(max(size, 0)))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Data = data
+	return s.Ingest(ctx, &req)
+}
+
+// IngestArchive 逐个处理 zip 中的图片，单个文件失败不影响其余文件；
+// meta 中的标签与署名信息应用到每个文件。文件数或解压后总大小超过 budget 的剩余额度时拒绝整个压缩包。
+func (s *IngestService) IngestArchive(ctx context.Context, r io.ReaderAt, size int64, meta IngestRequest, budget *UploadBudget) ([]models.IngestResult, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("读取压缩包失败: %w", err)
+	}
+
+	var entries []*zip.File
+	var total uint64
+	for _, f := range zr.File {
+		name := f.Name
+		base := path.Base(name)
+		// 跳过目录以及 macOS 等系统生成的隐藏文件
+		if f.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(base, ".") {
+			continue
+		}
+		entries = append(entries, f)
+		total += f.UncompressedSize64
+	}
+	if !budget.fits(total) {
+		return nil, fmt.Errorf("压缩包解压后的总大小 %d 字节超过剩余额度 %d 字节", total, budget.bytes)
+	}
+	if err := budget.take(len(entries)); err != nil {
+		return nil, fmt.Errorf("压缩包内文件数 %d 超出额度: %w", len(entries), err)
+	}
+
+	var results []models.IngestResult
+	for _, f := range entries {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		artwork, err := s.ingestArchiveEntry(ctx, f, meta, budget)
+		results = append(results, NewIngestResult(f.Name, artwork, err))
+	}
+
+	return results, nil
+}
+
+func (s *IngestService) ingestArchiveEntry(ctx context.Context, f *zip.File, req IngestRequest, budget *UploadBudget) (*models.ArtworkResponse, error) {
+	if !AllowedImageExts[strings.ToLower(path.Ext(f.Name))] {
+		return nil, ErrUnsupportedFormat
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := budget.read(rc, f.UncompressedSize64)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Filename = path.Base(f.Name)
+	req.Data = data
+	return s.Ingest(ctx, &req)
+}
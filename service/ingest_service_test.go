@@ -0,0 +1,180 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"path/filepath"
+	"testing"
+
+	"pln/conf"
+	"pln/models"
+	"pln/repo"
+	"pln/storage"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB 在临时目录中创建迁移好的 SQLite 数据库
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(
+		models.Artwork{},
+		models.TagAlias{}, models.TagImplication{}, models.TagNamespace{}, models.TagRevision{},
+		models.SmartCollection{}, models.AuditLog{},
+		models.Collection{}, models.CollectionItem{},
+		models.User{}, models.Session{}, models.APIKey{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// stripedPNG 生成内容与 seed 相关的 PNG，不同 seed 的图片 pHash 差异足够大
+func stripedPNG(t *testing.T, seed int) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, 64, 64))
+	for y := range 64 {
+		for x := range 64 {
+			if (x/(seed+2)+y/(seed+3))%2 == 0 {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newTestIngest 创建使用临时数据库与存储目录的入库服务
+func newTestIngest(t *testing.T, cfg *conf.AppConfig) (*IngestService, ModerationService) {
+	t.Helper()
+	db := newTestDB(t)
+	artworkRepo := repo.NewArtworkRepo(db)
+	tags := NewTagService(repo.NewTagRepo(db), artworkRepo)
+	artworks := NewArtworkService(artworkRepo, tags, repo.NewSmartCollectionRepo(db), cfg.Content)
+	uploader := storage.NewLocalUploader(t.TempDir(), "/api/v1/files", conf.ThumbnailOption{}, conf.ThumbnailOption{})
+	files := NewFileService(cfg, artworkRepo, uploader)
+	return NewIngestService(cfg, artworks, files), NewModerationService(artworkRepo, files, tags)
+}
+
+func TestIngestRejectedImageIsConflict(t *testing.T) {
+	cfg := &conf.AppConfig{Content: conf.ContentConfig{DefaultRating: models.RatingSafe}}
+	ingest, moderation := newTestIngest(t, cfg)
+
+	data := stripedPNG(t, 1)
+	req := IngestRequest{Filename: "a.png", Data: data, Status: models.StatusPending}
+
+	artwork, err := ingest.Ingest(t.Context(), &req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := moderation.Reject(t.Context(), artwork.ID, "spam"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 驳回后记录被软删除，再次提交同一文件应返回重复而不是创建失败
+	_, err = ingest.Ingest(t.Context(), &req)
+	var dup *DuplicateError
+	if !errors.As(err, &dup) {
+		t.Fatalf("err = %v, want *DuplicateError", err)
+	}
+	if !dup.Rejected || dup.ArtworkID != artwork.ID {
+		t.Fatalf("got %+v, want rejected duplicate of artwork %d", dup, artwork.ID)
+	}
+
+	// 其他图片不受影响
+	other := IngestRequest{Filename: "b.png", Data: stripedPNG(t, 9), Status: models.StatusPending}
+	if _, err := ingest.Ingest(t.Context(), &other); err != nil {
+		t.Fatalf("ingest other image: %v", err)
+	}
+}
+
+// testZip 将 files 打包为 zip
+func testZip(t *testing.T, files map[string][]byte) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, data := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestUploadBudgetSharedAcrossRequest(t *testing.T) {
+	t.Run("entries", func(t *testing.T) {
+		cfg := &conf.AppConfig{
+			Content: conf.ContentConfig{DefaultRating: models.RatingSafe},
+			Archive: conf.ArchiveConfig{MaxEntries: 3},
+		}
+		ingest, _ := newTestIngest(t, cfg)
+		budget := ingest.NewUploadBudget(nil)
+
+		first := testZip(t, map[string][]byte{"1.png": stripedPNG(t, 1), "2.png": stripedPNG(t, 3)})
+		results, err := ingest.IngestArchive(t.Context(), first, first.Size(), IngestRequest{}, budget)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, result := range results {
+			if result.Status != models.IngestStatusCreated {
+				t.Fatalf("first archive: %+v", results)
+			}
+		}
+
+		// 第二个压缩包的文件数超出剩余额度，整个压缩包被拒绝
+		second := testZip(t, map[string][]byte{"3.png": stripedPNG(t, 4), "4.png": stripedPNG(t, 7)})
+		if _, err := ingest.IngestArchive(t.Context(), second, second.Size(), IngestRequest{}, budget); err == nil {
+			t.Fatal("expected second archive to exceed the request budget")
+		}
+
+		if _, err := ingest.IngestFile(t.Context(), bytes.NewReader(stripedPNG(t, 8)), 0, IngestRequest{Filename: "5.png"}, budget); err != nil {
+			t.Fatalf("last file within budget: %v", err)
+		}
+		if _, err := ingest.IngestFile(t.Context(), bytes.NewReader(stripedPNG(t, 9)), 0, IngestRequest{Filename: "6.png"}, budget); err == nil {
+			t.Fatal("expected file beyond the request budget to be rejected")
+		}
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		images := [][]byte{stripedPNG(t, 1), stripedPNG(t, 3), stripedPNG(t, 4)}
+		var total int64
+		for _, data := range images {
+			total += int64(len(data))
+		}
+		cfg := &conf.AppConfig{
+			Content: conf.ContentConfig{DefaultRating: models.RatingSafe},
+			Archive: conf.ArchiveConfig{MaxTotalSize: total - 1},
+		}
+		ingest, _ := newTestIngest(t, cfg)
+		budget := ingest.NewUploadBudget(nil)
+
+		// 声明的大小为 0，实际读取的字节同样计入额度
+		for i, data := range images {
+			_, err := ingest.IngestFile(t.Context(), bytes.NewReader(data), 0, IngestRequest{Filename: fmt.Sprintf("%d.png", i)}, budget)
+			if last := i == len(images)-1; (err != nil) != last {
+				t.Fatalf("file %d: err = %v", i, err)
+			}
+		}
+	})
+}
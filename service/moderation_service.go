@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"pln/models"
+	"pln/repo"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// 单次批量审核的作品数上限
+const maxModerationBatch = 1000
+
+var (
+	ErrInvalidModeration = errors.New("无效的审核操作")
+	ErrNotPending        = errors.New("作品不在待审核状态")
+)
+
+type ModerationService interface {
+	// Queue 按审核状态（pending、rejected）分页列出作品，待审核的作品按上传顺序排列
+	Queue(status string, page, pageSize int) ([]models.ModerationItem, int64, error)
+	Approve(ctx context.Context, id uint) (*models.ModerationItem, error)
+	// Reject 驳回作品：删除作品记录并清理存储中的文件
+	Reject(ctx context.Context, id uint, reason string) (*models.ModerationItem, error)
+	// Bulk 批量审核，不存在或不在待审核状态的作品会被跳过
+	Bulk(ctx context.Context, req *models.ModerationBulkRequest) (*models.ModerationResult, error)
+}
+
+type moderationService struct {
+	repo        repo.ArtworkRepo
+	fileService *FileService
+	tags        TagService
+}
+
+func NewModerationService(repo repo.ArtworkRepo, fileService *FileService, tags TagService) ModerationService {
+	return &moderationService{repo: repo, fileService: fileService, tags: tags}
+}
+
+func (s *moderationService) Queue(status string, page, pageSize int) ([]models.ModerationItem, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	if status == "" {
+		status = models.StatusPending
+	}
+	if status != models.StatusPending && status != models.StatusRejected {
+		return nil, 0, fmt.Errorf("%w: status 可选值为 pending、rejected", ErrInvalidModeration)
+	}
+
+	artworks, total, err := s.repo.ListByStatus(status, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]models.ModerationItem, 0, len(artworks))
+	for _, artwork := range artworks {
+		items = append(items, s.toItem(&artwork))
+	}
+	return items, total, nil
+}
+
+func (s *moderationService) Approve(ctx context.Context, id uint) (*models.ModerationItem, error) {
+	artwork, err := s.moderate(ctx, id, models.ModerationApprove, "")
+	if err != nil {
+		return nil, err
+	}
+	item := s.toItem(artwork)
+	return &item, nil
+}
+
+func (s *moderationService) Reject(ctx context.Context, id uint, reason string) (*models.ModerationItem, error) {
+	artwork, err := s.moderate(ctx, id, models.ModerationReject, reason)
+	if err != nil {
+		return nil, err
+	}
+	item := s.toItem(artwork)
+	return &item, nil
+}
+
+func (s *moderationService) Bulk(ctx context.Context, req *models.ModerationBulkRequest) (*models.ModerationResult, error) {
+	if req.Action != models.ModerationApprove && req.Action != models.ModerationReject {
+		return nil, fmt.Errorf("%w: action 可选值为 approve、reject", ErrInvalidModeration)
+	}
+	ids := uniqueIDs(req.IDs)
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: 未指定作品", ErrInvalidModeration)
+	}
+	if len(ids) > maxModerationBatch {
+		return nil, fmt.Errorf("%w: 单次最多审核 %d 个作品", ErrInvalidModeration, maxModerationBatch)
+	}
+
+	result := &models.ModerationResult{Approved: []uint{}, Rejected: []uint{}, Skipped: []uint{}}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		_, err := s.moderate(ctx, id, req.Action, req.Reason)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, ErrNotPending):
+			result.Skipped = append(result.Skipped, id)
+		case err != nil:
+			return result, err
+		case req.Action == models.ModerationApprove:
+			result.Approved = append(result.Approved, id)
+		default:
+			result.Rejected = append(result.Rejected, id)
+		}
+	}
+	return result, nil
+}
+
+// moderate 审核单个待审核的作品。驳回时在事务中记录原因并删除作品，
+// 提交后再清理存储中的文件，文件清理失败只记录日志
+func (s *moderationService) moderate(ctx context.Context, id uint, action, reason string) (*models.Artwork, error) {
+	var artwork *models.Artwork
+	err := s.repo.Transaction(func(r repo.ArtworkRepo) error {
+		var err error
+		artwork, err = r.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if artwork.Status != models.StatusPending {
+			return ErrNotPending
+		}
+		before := map[string]any{"status": artwork.Status}
+
+		now := time.Now()
+		artwork.ModeratedBy = ActorFrom(ctx)
+		artwork.ModeratedAt = &now
+		if action == models.ModerationApprove {
+			artwork.Status = models.StatusApproved
+		} else {
+			artwork.Status = models.StatusRejected
+			artwork.RejectReason = strings.TrimSpace(reason)
+		}
+
+		err = r.UpdateFields(id, map[string]any{
+			"status":        artwork.Status,
+			"reject_reason": artwork.RejectReason,
+			"moderated_by":  artwork.ModeratedBy,
+			"moderated_at":  artwork.ModeratedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		auditAction := models.AuditArtworkApprove
+		after := map[string]any{"status": artwork.Status}
+		if action == models.ModerationReject {
+			auditAction = models.AuditArtworkReject
+			after["reject_reason"] = artwork.RejectReason
+		}
+		if err := recordAudit(ctx, r, id, auditAction, before, after); err != nil {
+			return err
+		}
+
+		if action == models.ModerationReject {
+			return r.Delete(id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger := log.Ctx(ctx).With().
+		Str("component", "ModerationService").
+		Uint("artwork_id", id).
+		Str("actor", artwork.ModeratedBy).
+		Logger()
+
+	if action == models.ModerationReject {
+		if _, err := s.fileService.DeleteFileByFileID(ctx, artwork.FileID); err != nil {
+			logger.Warn().Err(err).Str("file_id", artwork.FileID).Msg("清理驳回作品的文件失败")
+		}
+	}
+
+	logger.Info().Str("status", artwork.Status).Msg("作品审核完成")
+	return artwork, nil
+}
+
+func (s *moderationService) toItem(artwork *models.Artwork) models.ModerationItem {
+	item := artwork.ToModerationItem()
+	item.TagDetails = s.tags.Describe(item.Tags)
+	return item
+}
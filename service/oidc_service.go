@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"pln/conf"
+	"pln/models"
+)
+
+var (
+	// ErrOIDCState 回调的 state 与发起登录时不一致，或登录流程已过期
+	ErrOIDCState = errors.New("登录状态无效或已过期，请重新登录")
+	// ErrOIDCNoRole 用户组没有映射到任何角色且未配置默认角色
+	ErrOIDCNoRole = errors.New("当前账户没有访问权限")
+	// ErrOIDCProvider 身份提供方返回错误或无法访问
+	ErrOIDCProvider = errors.New("身份提供方请求失败")
+)
+
+// OIDCFlow 一次授权码登录的临时状态，由调用方保存在 Cookie 中直到回调
+type OIDCFlow struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"` // PKCE code_verifier
+	Redirect string `json:"redirect"` // 登录完成后跳转的站内路径
+}
+
+type OIDCService interface {
+	// Begin 生成 state、nonce 与 PKCE 参数，返回身份提供方的授权地址
+	Begin(ctx context.Context, redirect string) (string, *OIDCFlow, error)
+	// Finish 校验回调参数，用授权码换取并校验 ID Token，然后登录对应的用户
+	Finish(ctx context.Context, flow *OIDCFlow, state, code, userAgent, ip string) (*models.LoginResponse, error)
+}
+
+// oidcProvider 通过发现文档得到的端点
+type oidcProvider struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+
+	keys *keySet
+}
+
+type oidcService struct {
+	cfg   conf.OIDCConfig
+	users UserService
+	cli   *http.Client
+
+	mu       sync.Mutex
+	provider *oidcProvider
+}
+
+// NewOIDCService 校验配置并创建服务，发现文档在首次登录时获取，身份提供方暂时不可用不影响启动
+func NewOIDCService(cfg conf.OIDCConfig, users UserService) (OIDCService, error) {
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+	switch {
+	case cfg.Issuer == "":
+		return nil, fmt.Errorf("oidc.issuer 不能为空")
+	case cfg.ClientID == "":
+		return nil, fmt.Errorf("oidc.client_id 不能为空")
+	case cfg.RedirectURL == "":
+		return nil, fmt.Errorf("oidc.redirect_url 不能为空")
+	case cfg.DefaultRole != "" && !models.ValidRole(cfg.DefaultRole):
+		return nil, fmt.Errorf("oidc.default_role 无效: %s", cfg.DefaultRole)
+	}
+	for role := range cfg.RoleGroups {
+		if !models.ValidRole(role) {
+			return nil, fmt.Errorf("oidc.role_groups 中的角色无效: %s", role)
+		}
+	}
+	if !slices.Contains(cfg.Scopes, "openid") {
+		cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+	}
+	if cfg.UsernameClaim == "" {
+		cfg.UsernameClaim = "preferred_username"
+	}
+
+	return &oidcService{
+		cfg:   cfg,
+		users: users,
+		cli:   &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (s *oidcService) Begin(ctx context.Context, redirect string) (string, *OIDCFlow, error) {
+	provider, err := s.discover(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	flow := &OIDCFlow{
+		State:    randomURLString(),
+		Nonce:    randomURLString(),
+		Verifier: randomURLString(),
+		Redirect: safeRedirect(redirect),
+	}
+	challenge := sha256.Sum256([]byte(flow.Verifier))
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {s.cfg.ClientID},
+		"redirect_uri":          {s.cfg.RedirectURL},
+		"scope":                 {strings.Join(s.cfg.Scopes, " ")},
+		"state":                 {flow.State},
+		"nonce":                 {flow.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return provider.AuthorizationEndpoint + sep + query.Encode(), flow, nil
+}
+
+func (s *oidcService) Finish(ctx context.Context, flow *OIDCFlow, state, code, userAgent, ip string) (*models.LoginResponse, error) {
+	if flow == nil || flow.State == "" || state != flow.State || code == "" {
+		return nil, ErrOIDCState
+	}
+
+	provider, err := s.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rawIDToken, err := s.exchange(ctx, provider, code, flow.Verifier)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := verifyIDToken(ctx, provider.keys, rawIDToken, provider.Issuer, s.cfg.ClientID, flow.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	role := s.roleFor(claimStrings(claims.raw[s.cfg.GroupsClaim]))
+	if role == "" {
+		return nil, ErrOIDCNoRole
+	}
+
+	username, _ := claims.raw[s.cfg.UsernameClaim].(string)
+	if username == "" {
+		username, _ = claims.raw["email"].(string)
+	}
+
+	return s.users.LoginExternal(&models.ExternalIdentity{
+		ExternalID: provider.Issuer + "#" + claims.Subject,
+		Username:   username,
+		Role:       role,
+	}, userAgent, ip)
+}
+
+// discover 获取并缓存发现文档，失败时下次登录重试
+func (s *oidcService) discover(ctx context.Context) (*oidcProvider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.provider != nil {
+		return s.provider, nil
+	}
+
+	var provider oidcProvider
+	if err := getJSON(ctx, s.cli, s.cfg.Issuer+"/.well-known/openid-configuration", &provider); err != nil {
+		return nil, fmt.Errorf("%w: 获取发现文档失败: %v", ErrOIDCProvider, err)
+	}
+	if provider.Issuer != s.cfg.Issuer {
+		return nil, fmt.Errorf("%w: 发现文档中的 issuer %q 与配置不一致", ErrOIDCProvider, provider.Issuer)
+	}
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: 发现文档缺少必要的端点", ErrOIDCProvider)
+	}
+	provider.keys = &keySet{url: provider.JWKSURI, cli: s.cli}
+
+	s.provider = &provider
+	return s.provider, nil
+}
+
+// exchange 用授权码与 code_verifier 换取 ID Token
+func (s *oidcService) exchange(ctx context.Context, provider *oidcProvider, code, verifier string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {s.cfg.RedirectURL},
+		"client_id":     {s.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if s.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(s.cfg.ClientID), url.QueryEscape(s.cfg.ClientSecret))
+	}
+
+	resp, err := s.cli.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrOIDCProvider, err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("%w: 令牌响应解析失败 (%s)", ErrOIDCProvider, resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return "", fmt.Errorf("%w: %s %s", ErrOIDCProvider, body.Error, body.ErrorDescription)
+	}
+	if body.IDToken == "" {
+		return "", fmt.Errorf("%w: 令牌响应缺少 id_token", ErrOIDCProvider)
+	}
+	return body.IDToken, nil
+}
+
+// roleFor 取用户组映射到的最高角色，没有匹配时使用默认角色
+func (s *oidcService) roleFor(groups []string) string {
+	best := -1
+	for role, mapped := range s.cfg.RoleGroups {
+		i := slices.Index(models.Roles, role)
+		if i <= best {
+			continue
+		}
+		for _, g := range groups {
+			if slices.Contains(mapped, g) {
+				best = i
+				break
+			}
+		}
+	}
+	if best >= 0 {
+		return models.Roles[best]
+	}
+	return s.cfg.DefaultRole
+}
+
+// claimStrings 用户组声明可以是字符串数组或单个字符串
+func claimStrings(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// safeRedirect 只允许站内路径，避免登录后跳转到外部站点
+func safeRedirect(redirect string) string {
+	if !strings.HasPrefix(redirect, "/") || strings.HasPrefix(redirect, "//") || strings.HasPrefix(redirect, "/\\") {
+		return "/"
+	}
+	return redirect
+}
+
+// randomURLString 32 字节随机数的 base64url 编码，用作 state、nonce 与 code_verifier
+func randomURLString() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
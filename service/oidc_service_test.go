@@ -0,0 +1,407 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"pln/conf"
+	"pln/models"
+	"pln/repo"
+)
+
+const (
+	testClientID    = "pln"
+	testRedirectURL = "http://localhost/api/v1/auth/oidc/callback"
+)
+
+// mockIssuer 本地模拟的身份提供方，提供发现文档、JWKS 与令牌端点。
+// 授权端点不经过浏览器，测试直接调用 authorize 签发授权码。
+type mockIssuer struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	mu    sync.Mutex
+	keys  map[string]crypto.Signer // kid -> 私钥，全部发布在 JWKS 中
+	codes map[string]pendingCode
+
+	jwksHits atomic.Int32
+}
+
+// pendingCode 授权码对应的 PKCE challenge 与要签发的 ID Token
+type pendingCode struct {
+	challenge string
+	header    map[string]any
+	claims    map[string]any
+	signer    crypto.Signer
+}
+
+func newMockIssuer(t *testing.T) *mockIssuer {
+	m := &mockIssuer{t: t, keys: map[string]crypto.Signer{}, codes: map[string]pendingCode{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, http.StatusOK, map[string]any{
+			"issuer":                 m.srv.URL,
+			"authorization_endpoint": m.srv.URL + "/authorize",
+			"token_endpoint":         m.srv.URL + "/token",
+			"jwks_uri":               m.srv.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", m.serveJWKS)
+	mux.HandleFunc("POST /token", m.serveToken)
+	m.srv = httptest.NewServer(mux)
+	t.Cleanup(m.srv.Close)
+
+	return m
+}
+
+func writeTestJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// addKey 生成并发布一个新的签名密钥
+func (m *mockIssuer) addKey(kid, kty string) crypto.Signer {
+	m.t.Helper()
+	var key crypto.Signer
+	var err error
+	if kty == "EC" {
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	} else {
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+	}
+	if err != nil {
+		m.t.Fatal(err)
+	}
+
+	m.mu.Lock()
+	m.keys[kid] = key
+	m.mu.Unlock()
+	return key
+}
+
+func (m *mockIssuer) serveJWKS(w http.ResponseWriter, r *http.Request) {
+	m.jwksHits.Add(1)
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]map[string]string, 0, len(m.keys))
+	for kid, key := range m.keys {
+		switch pub := key.Public().(type) {
+		case *rsa.PublicKey:
+			keys = append(keys, map[string]string{
+				"kty": "RSA", "kid": kid, "use": "sig",
+				"n": b64(pub.N.Bytes()), "e": b64(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case *ecdsa.PublicKey:
+			ecdh, err := pub.ECDH()
+			if err != nil {
+				m.t.Error(err)
+				return
+			}
+			point := ecdh.Bytes()[1:]
+			keys = append(keys, map[string]string{
+				"kty": "EC", "kid": kid, "use": "sig", "crv": "P-256",
+				"x": b64(point[:32]), "y": b64(point[32:]),
+			})
+		}
+	}
+	writeTestJSON(w, http.StatusOK, map[string]any{"keys": keys})
+}
+
+// serveToken 校验授权码、redirect_uri 与 PKCE code_verifier 后签发 ID Token
+func (m *mockIssuer) serveToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeTestJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	m.mu.Lock()
+	pending, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	switch {
+	case !ok,
+		r.PostForm.Get("grant_type") != "authorization_code",
+		r.PostForm.Get("client_id") != testClientID,
+		r.PostForm.Get("redirect_uri") != testRedirectURL,
+		base64.RawURLEncoding.EncodeToString(verifier[:]) != pending.challenge:
+		writeTestJSON(w, http.StatusBadRequest, map[string]string{
+			"error":             "invalid_grant",
+			"error_description": "授权码或 code_verifier 无效",
+		})
+		return
+	}
+
+	writeTestJSON(w, http.StatusOK, map[string]any{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"id_token":     signTestToken(m.t, pending.signer, pending.header, pending.claims),
+	})
+}
+
+// authorize 模拟用户在身份提供方完成登录，返回授权码。
+// 默认使用 kid 签发有效的 ID Token，edit 可修改头部与声明
+func (m *mockIssuer) authorize(authURL, kid string, edit func(header, claims map[string]any)) string {
+	m.t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("client_id") != testClientID {
+		m.t.Fatalf("unexpected authorization request: %s", authURL)
+	}
+
+	now := time.Now()
+	header := map[string]any{"alg": "RS256", "kid": kid, "typ": "JWT"}
+	claims := map[string]any{
+		"iss":                m.srv.URL,
+		"sub":                "user-1",
+		"aud":                testClientID,
+		"exp":                now.Add(5 * time.Minute).Unix(),
+		"iat":                now.Unix(),
+		"nonce":              q.Get("nonce"),
+		"preferred_username": "alice",
+		"groups":             []string{"staff", "mods"},
+	}
+
+	m.mu.Lock()
+	signer := m.keys[kid]
+	m.mu.Unlock()
+	if _, ok := signer.(*ecdsa.PrivateKey); ok {
+		header["alg"] = "ES256"
+	}
+	if edit != nil {
+		edit(header, claims)
+	}
+
+	code := randomURLString()
+	m.mu.Lock()
+	m.codes[code] = pendingCode{challenge: q.Get("code_challenge"), header: header, claims: claims, signer: signer}
+	m.mu.Unlock()
+	return code
+}
+
+// signTestToken 按私钥类型签名：RSA 使用 PKCS#1 v1.5，ECDSA 使用定长的 r || s
+func signTestToken(t *testing.T, key crypto.Signer, header, claims map[string]any) string {
+	t.Helper()
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signing := encode(header) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signing))
+
+	var sig []byte
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+	return signing + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func newTestOIDCService(t *testing.T, issuer *mockIssuer) *oidcService {
+	t.Helper()
+	users := NewUserService(repo.NewUserRepo(newTestDB(t)), time.Hour)
+	svc, err := NewOIDCService(conf.OIDCConfig{
+		Enabled:       true,
+		Issuer:        issuer.srv.URL,
+		ClientID:      testClientID,
+		RedirectURL:   testRedirectURL,
+		Scopes:        []string{"openid", "profile"},
+		UsernameClaim: "preferred_username",
+		GroupsClaim:   "groups",
+		RoleGroups:    map[string][]string{models.RoleModerator: {"mods"}, models.RoleUploader: {"staff"}},
+	}, users)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc.(*oidcService)
+}
+
+// login 走完一次授权码登录
+func login(t *testing.T, svc *oidcService, issuer *mockIssuer, kid string, edit func(header, claims map[string]any)) (*models.LoginResponse, error) {
+	t.Helper()
+	ctx := t.Context()
+	authURL, flow, err := svc.Begin(ctx, "/bookmarks")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := issuer.authorize(authURL, kid, edit)
+	return svc.Finish(ctx, flow, flow.State, code, "test", "127.0.0.1")
+}
+
+func TestOIDCLogin(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.addKey("rsa-1", "RSA")
+	issuer.addKey("ec-1", "EC")
+
+	for _, kid := range []string{"rsa-1", "ec-1"} {
+		t.Run(kid, func(t *testing.T) {
+			svc := newTestOIDCService(t, issuer)
+			resp, err := login(t, svc, issuer, kid, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.Token == "" || resp.User.Username != "alice" {
+				t.Fatalf("got %+v, want a session for alice", resp)
+			}
+			// 同时属于 staff 与 mods，取最高的角色
+			if resp.User.Role != models.RoleModerator {
+				t.Fatalf("role = %q, want %q", resp.User.Role, models.RoleModerator)
+			}
+		})
+	}
+}
+
+func TestOIDCRejectsInvalidIDToken(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.addKey("rsa-1", "RSA")
+	issuer.addKey("rsa-2", "RSA")
+
+	tests := []struct {
+		name string
+		kid  string // 实际签名使用的密钥
+		edit func(header, claims map[string]any)
+	}{
+		{"wrong audience", "rsa-1", func(h, c map[string]any) { c["aud"] = "someone-else" }},
+		{"audience list without azp", "rsa-1", func(h, c map[string]any) { c["aud"] = []string{"someone-else", testClientID} }},
+		{"wrong issuer", "rsa-1", func(h, c map[string]any) { c["iss"] = "https://evil.example.com" }},
+		{"expired", "rsa-1", func(h, c map[string]any) {
+			c["iat"] = time.Now().Add(-2 * time.Hour).Unix()
+			c["exp"] = time.Now().Add(-time.Hour).Unix()
+		}},
+		{"issued in the future", "rsa-1", func(h, c map[string]any) { c["iat"] = time.Now().Add(time.Hour).Unix() }},
+		{"nonce mismatch", "rsa-1", func(h, c map[string]any) { c["nonce"] = "replayed" }},
+		{"missing subject", "rsa-1", func(h, c map[string]any) { delete(c, "sub") }},
+		{"alg does not match key type", "rsa-1", func(h, c map[string]any) { h["alg"] = "ES256" }},
+		{"unsupported alg", "rsa-1", func(h, c map[string]any) { h["alg"] = "none" }},
+		// 头部声明的 kid 与实际签名的密钥不一致，相当于令牌被篡改
+		{"signed by another key", "rsa-2", func(h, c map[string]any) { h["kid"] = "rsa-1" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestOIDCService(t, issuer)
+			if _, err := login(t, svc, issuer, tt.kid, tt.edit); !errors.Is(err, ErrInvalidIDToken) {
+				t.Fatalf("err = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestOIDCUnknownKidRefreshesJWKS(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.addKey("rsa-1", "RSA")
+	svc := newTestOIDCService(t, issuer)
+
+	if _, err := login(t, svc, issuer, "rsa-1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if hits := issuer.jwksHits.Load(); hits != 1 {
+		t.Fatalf("jwks fetched %d times, want 1", hits)
+	}
+
+	// 身份提供方轮换密钥；刚获取过 JWKS 时不会因为未知 kid 立即重新获取
+	issuer.addKey("rsa-2", "RSA")
+	if _, err := login(t, svc, issuer, "rsa-2", nil); !errors.Is(err, ErrInvalidIDToken) {
+		t.Fatalf("err = %v, want ErrInvalidIDToken within refresh interval", err)
+	}
+	if hits := issuer.jwksHits.Load(); hits != 1 {
+		t.Fatalf("jwks fetched %d times within refresh interval, want 1", hits)
+	}
+
+	// 超过最短间隔后，未知 kid 触发重新获取
+	keys := svc.provider.keys
+	keys.mu.Lock()
+	keys.fetched = time.Now().Add(-jwksRefreshInterval)
+	keys.mu.Unlock()
+
+	if _, err := login(t, svc, issuer, "rsa-2", nil); err != nil {
+		t.Fatalf("login with rotated key: %v", err)
+	}
+	if hits := issuer.jwksHits.Load(); hits != 2 {
+		t.Fatalf("jwks fetched %d times, want 2", hits)
+	}
+}
+
+func TestOIDCFailingPKCEVerifier(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.addKey("rsa-1", "RSA")
+	svc := newTestOIDCService(t, issuer)
+
+	ctx := t.Context()
+	authURL, flow, err := svc.Begin(ctx, "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := issuer.authorize(authURL, "rsa-1", nil)
+
+	// 回调时使用的 code_verifier 与授权请求中的 code_challenge 不对应
+	flow.Verifier = randomURLString()
+	if _, err := svc.Finish(ctx, flow, flow.State, code, "test", "127.0.0.1"); !errors.Is(err, ErrOIDCProvider) {
+		t.Fatalf("err = %v, want ErrOIDCProvider", err)
+	}
+}
+
+func TestOIDCStateMismatch(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.addKey("rsa-1", "RSA")
+	svc := newTestOIDCService(t, issuer)
+
+	ctx := t.Context()
+	authURL, flow, err := svc.Begin(ctx, "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := issuer.authorize(authURL, "rsa-1", nil)
+
+	if _, err := svc.Finish(ctx, flow, "forged", code, "test", "127.0.0.1"); !errors.Is(err, ErrOIDCState) {
+		t.Fatalf("err = %v, want ErrOIDCState", err)
+	}
+}
+
+func TestOIDCNoRole(t *testing.T) {
+	issuer := newMockIssuer(t)
+	issuer.addKey("rsa-1", "RSA")
+	svc := newTestOIDCService(t, issuer)
+
+	_, err := login(t, svc, issuer, "rsa-1", func(h, c map[string]any) { c["groups"] = []string{"guests"} })
+	if !errors.Is(err, ErrOIDCNoRole) {
+		t.Fatalf("err = %v, want ErrOIDCNoRole", err)
+	}
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256" // 注册 crypto.SHA256
+	_ "crypto/sha512" // 注册 crypto.SHA384 / crypto.SHA512
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrInvalidIDToken ID Token 格式、签名或声明校验失败
+var ErrInvalidIDToken = errors.New("ID Token 无效")
+
+// idTokenLeeway 校验 exp / iat 时允许的时钟偏差
+const idTokenLeeway = time.Minute
+
+// jwksRefreshInterval 遇到未知 kid 时重新获取 JWKS 的最短间隔，避免被伪造的令牌驱动频繁请求
+const jwksRefreshInterval = time.Minute
+
+// idTokenAlgs 支持的签名算法
+var idTokenAlgs = map[string]crypto.Hash{
+	"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512,
+	"ES256": crypto.SHA256, "ES384": crypto.SHA384, "ES512": crypto.SHA512,
+}
+
+// jwk JSON Web Key 中用到的字段
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKey 解析为 *rsa.PublicKey 或 *ecdsa.PublicKey
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exp := new(big.Int).SetBytes(e)
+		if !exp.IsInt64() || exp.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("RSA 公钥指数过大")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("不支持的曲线 %s", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) > size || len(y) > size {
+			return nil, fmt.Errorf("EC 公钥坐标长度无效")
+		}
+		point := make([]byte, 1+2*size)
+		point[0] = 4
+		copy(point[1+size-len(x):1+size], x)
+		copy(point[1+2*size-len(y):], y)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+
+	default:
+		return nil, fmt.Errorf("不支持的密钥类型 %s", k.Kty)
+	}
+}
+
+// keySet 缓存身份提供方的签名公钥
+type keySet struct {
+	url string
+	cli *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]crypto.PublicKey
+	fetched time.Time
+}
+
+// key 按 kid 查找公钥，找不到或 stale 为 true（缓存的公钥校验失败）时重新获取 JWKS；
+// kid 为空且只有一个公钥时使用该公钥
+func (s *keySet) key(ctx context.Context, kid string, stale bool) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key := s.lookup(kid); key != nil && !stale {
+		return key, nil
+	}
+	if time.Since(s.fetched) < jwksRefreshInterval {
+		return nil, fmt.Errorf("%w: 未知的签名密钥 %q", ErrInvalidIDToken, kid)
+	}
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if key := s.lookup(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: 未知的签名密钥 %q", ErrInvalidIDToken, kid)
+}
+
+func (s *keySet) lookup(kid string) crypto.PublicKey {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key
+		}
+	}
+	return s.keys[kid]
+}
+
+func (s *keySet) refresh(ctx context.Context) error {
+	s.fetched = time.Now()
+
+	var body struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := getJSON(ctx, s.cli, s.url, &body); err != nil {
+		return fmt.Errorf("获取 JWKS 失败: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(body.Keys))
+	for _, k := range body.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			// 跳过无法解析的密钥，其余密钥仍可使用
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	s.keys = keys
+	return nil
+}
+
+// idTokenClaims ID Token 中需要校验的标准声明，其余声明保留在 raw 中
+type idTokenClaims struct {
+	Issuer   string   `json:"iss"`
+	Subject  string   `json:"sub"`
+	Audience audience `json:"aud"`
+	Azp      string   `json:"azp"`
+	Expiry   float64  `json:"exp"`
+	IssuedAt float64  `json:"iat"`
+	Nonce    string   `json:"nonce"`
+
+	raw map[string]any
+}
+
+// audience aud 可以是字符串或字符串数组
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// verifyIDToken 校验签名、issuer、audience、有效期与 nonce，返回全部声明
+func verifyIDToken(ctx context.Context, keys *keySet, raw, issuer, clientID, nonce string) (*idTokenClaims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: 格式错误", ErrInvalidIDToken)
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w: 头部解码失败", ErrInvalidIDToken)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, fmt.Errorf("%w: 头部解析失败", ErrInvalidIDToken)
+	}
+	hash, ok := idTokenAlgs[header.Alg]
+	if !ok {
+		return nil, fmt.Errorf("%w: 不支持的签名算法 %q", ErrInvalidIDToken, header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: 签名解码失败", ErrInvalidIDToken)
+	}
+	h := hash.New()
+	h.Write([]byte(parts[0] + "." + parts[1]))
+	digest := h.Sum(nil)
+
+	key, err := keys.key(ctx, header.Kid, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(key, header.Alg, hash, digest, sig); err != nil {
+		// 身份提供方可能以相同的 kid 轮换了密钥，重新获取后再试一次
+		if key, err = keys.key(ctx, header.Kid, true); err != nil {
+			return nil, err
+		}
+		if err := verifySignature(key, header.Alg, hash, digest, sig); err != nil {
+			return nil, err
+		}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: 声明解码失败", ErrInvalidIDToken)
+	}
+	var claims idTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("%w: 声明解析失败", ErrInvalidIDToken)
+	}
+	if err := json.Unmarshal(payload, &claims.raw); err != nil {
+		return nil, fmt.Errorf("%w: 声明解析失败", ErrInvalidIDToken)
+	}
+
+	now := time.Now()
+	switch {
+	case claims.Issuer != issuer:
+		return nil, fmt.Errorf("%w: issuer 不匹配", ErrInvalidIDToken)
+	case !slices.Contains(claims.Audience, clientID):
+		return nil, fmt.Errorf("%w: audience 不包含 client_id", ErrInvalidIDToken)
+	case len(claims.Audience) > 1 && claims.Azp != clientID:
+		return nil, fmt.Errorf("%w: azp 不匹配", ErrInvalidIDToken)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: 缺少 sub", ErrInvalidIDToken)
+	case now.After(time.Unix(int64(claims.Expiry), 0).Add(idTokenLeeway)):
+		return nil, fmt.Errorf("%w: 已过期", ErrInvalidIDToken)
+	case claims.IssuedAt > 0 && time.Unix(int64(claims.IssuedAt), 0).After(now.Add(idTokenLeeway)):
+		return nil, fmt.Errorf("%w: 签发时间晚于当前时间", ErrInvalidIDToken)
+	case claims.Nonce != nonce:
+		return nil, fmt.Errorf("%w: nonce 不匹配", ErrInvalidIDToken)
+	}
+	return &claims, nil
+}
+
+// verifySignature 按算法校验签名，ES 系列签名为定长的 r || s
+func verifySignature(key crypto.PublicKey, alg string, hash crypto.Hash, digest, sig []byte) error {
+	switch pub := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") || rsa.VerifyPKCS1v15(pub, hash, digest, sig) != nil {
+			return fmt.Errorf("%w: 签名校验失败", ErrInvalidIDToken)
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(alg, "ES") || len(sig) != 2*size {
+			return fmt.Errorf("%w: 签名校验失败", ErrInvalidIDToken)
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return fmt.Errorf("%w: 签名校验失败", ErrInvalidIDToken)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: 不支持的密钥类型", ErrInvalidIDToken)
+	}
+}
+
+// getJSON 请求地址并解析 JSON 响应
+func getJSON(ctx context.Context, cli *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := cli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s 返回 %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"pln/conf"
+	"pln/models"
+	"pln/repo"
+)
+
+func TestRatingDefaultsAndTagCounts(t *testing.T) {
+	db := newTestDB(t)
+	artworkRepo := repo.NewArtworkRepo(db)
+	tags := NewTagService(repo.NewTagRepo(db), artworkRepo)
+	artworks := NewArtworkService(artworkRepo, tags, repo.NewSmartCollectionRepo(db),
+		conf.ContentConfig{DefaultRating: models.RatingQuestionable})
+
+	// 未指定分级时使用配置的默认值
+	created, err := artworks.CreateArtwork(&models.ArtworkCreateRequest{FileID: "f0", Hash: "h0", Tags: []string{"cat", "outdoor"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Rating != models.RatingQuestionable {
+		t.Fatalf("default rating %q, want %q", created.Rating, models.RatingQuestionable)
+	}
+	for i, rating := range []string{models.RatingSafe, models.RatingExplicit} {
+		_, err := artworks.CreateArtwork(&models.ArtworkCreateRequest{
+			FileID: fmt.Sprintf("f%d", i+1), Hash: fmt.Sprintf("h%d", i+1), Rating: rating,
+			Tags: []string{"cat", rating + "_only"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	safe := []string{models.RatingSafe}
+	counts := func(usages []models.TagUsage) map[string]int64 {
+		m := map[string]int64{}
+		for _, u := range usages {
+			m[u.Name] = u.Count
+		}
+		return m
+	}
+
+	listed, _, err := tags.ListTags(models.TagListQuery{Ratings: safe, Limit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := counts(listed); len(got) != 2 || got["cat"] != 1 || got["safe_only"] != 1 {
+		t.Errorf("safe tag list %v", got)
+	}
+	all, _, err := tags.ListTags(models.TagListQuery{Limit: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := counts(all); got["cat"] != 3 || got["explicit_only"] != 1 {
+		t.Errorf("unrestricted tag list %v", got)
+	}
+
+	suggestions, err := tags.Autocomplete("ex", 10, safe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) != 0 {
+		t.Errorf("autocomplete leaked %v", suggestions)
+	}
+
+	related, err := tags.RelatedTags("cat", 10, safe)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := counts(related); len(got) != 1 || got["safe_only"] != 1 {
+		t.Errorf("safe related tags %v", got)
+	}
+}
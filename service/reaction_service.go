@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"pln/models"
+	"pln/repo"
+
+	"gorm.io/gorm"
+)
+
+// 单次查询互动状态的作品数上限
+const maxReactionQuery = 200
+
+var (
+	ErrInvalidReaction = errors.New("无效的互动查询")
+	// ErrNoClient 请求没有可识别的客户端标识
+	ErrNoClient = errors.New("无法识别客户端")
+)
+
+type ReactionService interface {
+	// React 设置 client 对作品的点赞或收藏状态，重复操作不会改变计数
+	React(id uint, client, kind string, on bool) (*models.ReactionResult, error)
+	// States 按 ids 的顺序返回 client 的互动状态，client 为空时全部为未互动
+	States(client string, ids []uint) ([]models.ReactionState, error)
+}
+
+type reactionService struct {
+	repo repo.ArtworkRepo
+}
+
+func NewReactionService(repo repo.ArtworkRepo) ReactionService {
+	return &reactionService{repo: repo}
+}
+
+func (s *reactionService) React(id uint, client, kind string, on bool) (*models.ReactionResult, error) {
+	if client == "" {
+		return nil, ErrNoClient
+	}
+
+	result := &models.ReactionResult{}
+	err := s.repo.Transaction(func(r repo.ArtworkRepo) error {
+		artwork, err := r.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if artwork.Status != models.StatusApproved {
+			return gorm.ErrRecordNotFound
+		}
+
+		if on {
+			result.Changed, err = r.AddReaction(&models.Reaction{ArtworkID: id, Client: client, Kind: kind})
+		} else {
+			result.Changed, err = r.RemoveReaction(id, client, kind)
+		}
+		if err != nil || !result.Changed {
+			return err
+		}
+
+		switch {
+		case kind == models.ReactionLike && on:
+			return r.IncrementLikes(id)
+		case kind == models.ReactionLike:
+			return r.DecrementLikes(id)
+		case on:
+			return r.IncrementBookmarks(id)
+		default:
+			return r.DecrementBookmarks(id)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	artwork, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	states, err := s.States(client, []uint{id})
+	if err != nil {
+		return nil, err
+	}
+
+	result.ReactionState = states[0]
+	result.Likes = artwork.Likes
+	result.Bookmarks = artwork.Bookmarks
+	return result, nil
+}
+
+func (s *reactionService) States(client string, ids []uint) ([]models.ReactionState, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) > maxReactionQuery {
+		return nil, fmt.Errorf("%w: 单次最多查询 %d 个作品", ErrInvalidReaction, maxReactionQuery)
+	}
+
+	states := make([]models.ReactionState, len(ids))
+	for i, id := range ids {
+		states[i].ArtworkID = id
+	}
+	if client == "" {
+		return states, nil
+	}
+
+	reactions, err := s.repo.ListReactions(client, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, reaction := range reactions {
+		i := slices.Index(ids, reaction.ArtworkID)
+		switch reaction.Kind {
+		case models.ReactionLike:
+			states[i].Liked = true
+		case models.ReactionBookmark:
+			states[i].Bookmarked = true
+		}
+	}
+	return states, nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"pln/models"
+	"pln/repo"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidSmartCollection = errors.New("无效的智能作品集")
+	ErrSmartCollectionExists  = errors.New("智能作品集名称已存在")
+)
+
+// 名称以小写字母开头，只包含小写字母、数字、- 与 _，避免与手动作品集的数字 ID 混淆
+var smartCollectionName = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
+
+type SmartCollectionService interface {
+	ListSmartCollections(page, pageSize int) ([]models.SmartCollectionResponse, int64, error)
+	GetSmartCollection(name string) (*models.SmartCollectionResponse, error)
+	CreateSmartCollection(req *models.SmartCollectionRequest) (*models.SmartCollectionResponse, error)
+	UpdateSmartCollection(name string, req *models.SmartCollectionUpdateRequest) (*models.SmartCollectionResponse, error)
+	DeleteSmartCollection(name string) error
+
+	// ListArtworks 分页列出当前匹配的作品
+	ListArtworks(name string, page, pageSize int, ratings []string) ([]models.ArtworkResponse, int64, error)
+}
+
+type smartCollectionService struct {
+	repo     repo.SmartCollectionRepo
+	artworks ArtworkService
+}
+
+func NewSmartCollectionService(repo repo.SmartCollectionRepo, artworks ArtworkService) SmartCollectionService {
+	return &smartCollectionService{repo: repo, artworks: artworks}
+}
+
+func (s *smartCollectionService) ListSmartCollections(page, pageSize int) ([]models.SmartCollectionResponse, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	collections, total, err := s.repo.List((page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]models.SmartCollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		resp, err := s.toResponse(&collection)
+		if err != nil {
+			return nil, 0, err
+		}
+		responses = append(responses, *resp)
+	}
+	return responses, total, nil
+}
+
+func (s *smartCollectionService) GetSmartCollection(name string) (*models.SmartCollectionResponse, error) {
+	collection, err := s.repo.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(collection)
+}
+
+func (s *smartCollectionService) CreateSmartCollection(req *models.SmartCollectionRequest) (*models.SmartCollectionResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if !smartCollectionName.MatchString(name) {
+		return nil, fmt.Errorf("%w: 名称需以小写字母开头，只能包含小写字母、数字、- 与 _", ErrInvalidSmartCollection)
+	}
+	if _, err := s.repo.GetByName(name); err == nil {
+		return nil, ErrSmartCollectionExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	query, err := s.checkQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &models.SmartCollection{
+		Name:        name,
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		Query:       query,
+	}
+	if err := s.repo.Create(collection); err != nil {
+		return nil, err
+	}
+	return s.toResponse(collection)
+}
+
+func (s *smartCollectionService) UpdateSmartCollection(name string, req *models.SmartCollectionUpdateRequest) (*models.SmartCollectionResponse, error) {
+	collection, err := s.repo.GetByName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		collection.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Description != nil {
+		collection.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.Query != nil {
+		if collection.Query, err = s.checkQuery(*req.Query); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Update(collection); err != nil {
+		return nil, err
+	}
+	return s.toResponse(collection)
+}
+
+func (s *smartCollectionService) DeleteSmartCollection(name string) error {
+	return s.repo.Delete(name)
+}
+
+func (s *smartCollectionService) ListArtworks(name string, page, pageSize int, ratings []string) ([]models.ArtworkResponse, int64, error) {
+	if _, err := s.repo.GetByName(name); err != nil {
+		return nil, 0, err
+	}
+
+	filters := map[string]any{"collection": name}
+	if len(ratings) > 0 {
+		filters["ratings"] = ratings
+	}
+	return s.artworks.GetArtworks(page, pageSize, filters)
+}
+
+// checkQuery 整理检索条件，条件不能为空。
+// 标签按原样保存，查询时再应用别名规则，规则变化后无需修改作品集。
+func (s *smartCollectionService) checkQuery(query models.ArtworkQuery) (models.ArtworkQuery, error) {
+	q := models.ArtworkQuery{
+		Artist:  strings.TrimSpace(query.Artist),
+		License: strings.TrimSpace(query.License),
+		Q:       strings.TrimSpace(query.Q),
+	}
+	for _, tag := range query.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			q.Tags = append(q.Tags, tag)
+		}
+	}
+
+	if len(q.Filters()) == 0 {
+		return q, fmt.Errorf("%w: 检索条件不能为空", ErrInvalidSmartCollection)
+	}
+	return q, nil
+}
+
+func (s *smartCollectionService) toResponse(collection *models.SmartCollection) (*models.SmartCollectionResponse, error) {
+	count, err := s.artworks.CountArtworks(collection.Query.Filters())
+	if err != nil {
+		return nil, err
+	}
+	return &models.SmartCollectionResponse{SmartCollection: *collection, ArtworkCount: count}, nil
+}
@@ -0,0 +1,639 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"pln/models"
+	"pln/repo"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrInvalidTagRule   = errors.New("无效的标签规则")
+	ErrTagRuleExists    = errors.New("标签规则已存在")
+	ErrTagRuleConflict  = errors.New("标签规则冲突")
+	ErrTagCycle         = errors.New("标签蕴含存在循环")
+	ErrInvalidNamespace = errors.New("无效的命名空间")
+	ErrNamespaceExists  = errors.New("命名空间已存在")
+)
+
+var (
+	namespacePattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+	colorPattern     = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+type TagService interface {
+	ListAliases() ([]models.TagAlias, error)
+	CreateAlias(req *models.TagAliasRequest) (*models.TagAlias, error)
+	DeleteAlias(id uint) error
+
+	ListImplications() ([]models.TagImplication, error)
+	CreateImplication(req *models.TagImplicationRequest) (*models.TagImplication, error)
+	DeleteImplication(id uint) error
+
+	ListNamespaces() ([]models.TagNamespace, error)
+	CreateNamespace(req *models.TagNamespaceRequest) (*models.TagNamespace, error)
+	UpdateNamespace(id uint, req *models.TagNamespaceUpdateRequest) (*models.TagNamespace, error)
+	DeleteNamespace(id uint) error
+
+	// Canonicalize 替换别名并补充蕴含标签，用于写入
+	Canonicalize(tags []string) ([]string, error)
+	// CanonicalTag 仅替换别名，用于检索
+	CanonicalTag(tag string) (string, error)
+	// ReapplyRules 对已有作品重新应用别名与蕴含规则
+	ReapplyRules(ctx context.Context) (*models.TagReapplyResult, error)
+	// ListTags 标签目录：标签及其使用次数
+	ListTags(query models.TagListQuery) ([]models.TagUsage, int64, error)
+	// Autocomplete 按前缀补全标签，包含通过别名匹配的标签；只统计 ratings 内的作品，nil 表示不限制
+	Autocomplete(q string, limit int, ratings []string) ([]models.TagSuggestion, error)
+	// RelatedTags 与指定标签同时出现次数最多的标签；只统计 ratings 内的作品，nil 表示不限制
+	RelatedTags(tag string, limit int, ratings []string) ([]models.TagUsage, error)
+	// Describe 将标签转换为结构化标签，按命名空间顺序排列
+	Describe(tags []string) []models.TagDetail
+	// SplitQuery 从关键词中提取 ns:value 形式的命名空间标签
+	SplitQuery(q string) (tags []string, text string, err error)
+}
+
+type tagService struct {
+	repo        repo.TagRepo
+	artworkRepo repo.ArtworkRepo
+
+	mu    sync.Mutex
+	rules *tagRules // 规则缓存，变更后置空
+}
+
+func NewTagService(repo repo.TagRepo, artworkRepo repo.ArtworkRepo) TagService {
+	return &tagService{repo: repo, artworkRepo: artworkRepo}
+}
+
+// tagRules 内存中的别名、蕴含规则与命名空间
+type tagRules struct {
+	aliases    map[string]string              // 别名 -> 规范标签
+	implies    map[string][]string            // 标签 -> 直接蕴含的标签
+	namespaces map[string]models.TagNamespace // 名称 -> 命名空间
+}
+
+// split 拆分已注册命名空间的标签，未注册的前缀视为普通标签的一部分
+func (r *tagRules) split(tag string) (namespace, value string, ok bool) {
+	i := strings.Index(tag, ":")
+	if i <= 0 {
+		return "", tag, false
+	}
+	namespace = strings.ToLower(strings.TrimSpace(tag[:i]))
+	if _, registered := r.namespaces[namespace]; !registered {
+		return "", tag, false
+	}
+	return namespace, strings.TrimSpace(tag[i+1:]), true
+}
+
+// usages 为标签计数附加结构化信息
+func (r *tagRules) usages(counts []models.TagCount) []models.TagUsage {
+	usages := make([]models.TagUsage, 0, len(counts))
+	for _, c := range counts {
+		usages = append(usages, models.TagUsage{TagDetail: r.detail(c.Name), Count: c.Count})
+	}
+	return usages
+}
+
+// detail 生成结构化标签
+func (r *tagRules) detail(tag string) models.TagDetail {
+	detail := models.TagDetail{Name: tag, Value: tag}
+	if namespace, value, ok := r.split(tag); ok {
+		ns := r.namespaces[namespace]
+		detail.Namespace = namespace
+		detail.Value = value
+		detail.Color = ns.Color
+		detail.SortOrder = ns.SortOrder
+	}
+	return detail
+}
+
+func (r *tagRules) canonical(tag string) string {
+	tag = strings.TrimSpace(tag)
+	// 规范命名空间写法：Artist: foo -> artist:foo
+	if namespace, value, ok := r.split(tag); ok {
+		if value == "" {
+			return ""
+		}
+		tag = namespace + ":" + value
+	}
+	if target, ok := r.aliases[tag]; ok {
+		return target
+	}
+	return tag
+}
+
+// apply 替换别名、补充蕴含标签并去重，保持原有顺序
+func (r *tagRules) apply(tags []string) []string {
+	result := []string{}
+	seen := make(map[string]bool)
+
+	var add func(tag string)
+	add = func(tag string) {
+		tag = r.canonical(tag)
+		if tag == "" || seen[tag] {
+			return
+		}
+		seen[tag] = true
+		result = append(result, tag)
+		for _, implied := range r.implies[tag] {
+			add(implied)
+		}
+	}
+
+	for _, tag := range tags {
+		add(tag)
+	}
+	return result
+}
+
+// path 查找 from 经蕴含到达 to 的路径，不存在时返回 nil
+func (r *tagRules) path(from, to string) []string {
+	visited := make(map[string]bool)
+
+	var walk func(tag string) []string
+	walk = func(tag string) []string {
+		if tag == to {
+			return []string{tag}
+		}
+		if visited[tag] {
+			return nil
+		}
+		visited[tag] = true
+		for _, next := range r.implies[tag] {
+			if p := walk(next); p != nil {
+				return append([]string{tag}, p...)
+			}
+		}
+		return nil
+	}
+
+	return walk(from)
+}
+
+// loadRules 读取规则，调用方需持有 s.mu
+func (s *tagService) loadRules() (*tagRules, error) {
+	if s.rules != nil {
+		return s.rules, nil
+	}
+
+	aliases, err := s.repo.ListAliases()
+	if err != nil {
+		return nil, err
+	}
+	implications, err := s.repo.ListImplications()
+	if err != nil {
+		return nil, err
+	}
+	namespaces, err := s.repo.ListNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := &tagRules{
+		aliases:    make(map[string]string, len(aliases)),
+		implies:    make(map[string][]string),
+		namespaces: make(map[string]models.TagNamespace, len(namespaces)),
+	}
+	for _, ns := range namespaces {
+		rules.namespaces[ns.Name] = ns
+	}
+	for _, a := range aliases {
+		rules.aliases[a.Alias] = a.Tag
+	}
+	for _, i := range implications {
+		rules.implies[i.Tag] = append(rules.implies[i.Tag], i.Implies)
+	}
+
+	s.rules = rules
+	return rules, nil
+}
+
+func (s *tagService) currentRules() (*tagRules, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loadRules()
+}
+
+func (s *tagService) ListAliases() ([]models.TagAlias, error) {
+	return s.repo.ListAliases()
+}
+
+func (s *tagService) CreateAlias(req *models.TagAliasRequest) (*models.TagAlias, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules, err := s.loadRules()
+	if err != nil {
+		return nil, err
+	}
+
+	alias := strings.TrimSpace(req.Alias)
+	// 目标本身是别名时指向其规范标签
+	tag := rules.canonical(req.Tag)
+	if alias == "" || tag == "" || alias == tag {
+		return nil, ErrInvalidTagRule
+	}
+
+	if _, ok := rules.aliases[alias]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrTagRuleExists, alias)
+	}
+	for a, t := range rules.aliases {
+		if t == alias {
+			return nil, fmt.Errorf("%w: %s 是别名 %s 的规范标签", ErrTagRuleConflict, alias, a)
+		}
+	}
+	for t, implied := range rules.implies {
+		for _, i := range implied {
+			if t == alias || i == alias {
+				return nil, fmt.Errorf("%w: %s 已用于蕴含规则 %s → %s", ErrTagRuleConflict, alias, t, i)
+			}
+		}
+	}
+
+	record := &models.TagAlias{Alias: alias, Tag: tag}
+	if err := s.repo.CreateAlias(record); err != nil {
+		return nil, err
+	}
+	s.rules = nil
+
+	return record, nil
+}
+
+func (s *tagService) DeleteAlias(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.repo.DeleteAlias(id); err != nil {
+		return err
+	}
+	s.rules = nil
+	return nil
+}
+
+func (s *tagService) ListImplications() ([]models.TagImplication, error) {
+	return s.repo.ListImplications()
+}
+
+func (s *tagService) CreateImplication(req *models.TagImplicationRequest) (*models.TagImplication, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules, err := s.loadRules()
+	if err != nil {
+		return nil, err
+	}
+
+	// 规则只作用于规范标签
+	tag := rules.canonical(req.Tag)
+	implies := rules.canonical(req.Implies)
+	if tag == "" || implies == "" {
+		return nil, ErrInvalidTagRule
+	}
+	if tag == implies {
+		return nil, fmt.Errorf("%w: %s → %s", ErrTagCycle, tag, implies)
+	}
+
+	for _, i := range rules.implies[tag] {
+		if i == implies {
+			return nil, fmt.Errorf("%w: %s → %s", ErrTagRuleExists, tag, implies)
+		}
+	}
+
+	// 新规则 tag → implies 与已有的 implies → … → tag 构成循环
+	if p := rules.path(implies, tag); p != nil {
+		return nil, fmt.Errorf("%w: %s → %s", ErrTagCycle, tag, strings.Join(p, " → "))
+	}
+
+	record := &models.TagImplication{Tag: tag, Implies: implies}
+	if err := s.repo.CreateImplication(record); err != nil {
+		return nil, err
+	}
+	s.rules = nil
+
+	return record, nil
+}
+
+func (s *tagService) DeleteImplication(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.repo.DeleteImplication(id); err != nil {
+		return err
+	}
+	s.rules = nil
+	return nil
+}
+
+func (s *tagService) ListNamespaces() ([]models.TagNamespace, error) {
+	return s.repo.ListNamespaces()
+}
+
+func (s *tagService) CreateNamespace(req *models.TagNamespaceRequest) (*models.TagNamespace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rules, err := s.loadRules()
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.ToLower(strings.TrimSpace(req.Name))
+	if !namespacePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: 名称只能包含小写字母、数字或下划线", ErrInvalidNamespace)
+	}
+	if req.Color != "" && !colorPattern.MatchString(req.Color) {
+		return nil, fmt.Errorf("%w: 颜色格式应为 #RRGGBB", ErrInvalidNamespace)
+	}
+	if _, ok := rules.namespaces[name]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrNamespaceExists, name)
+	}
+
+	namespace := &models.TagNamespace{
+		Name:      name,
+		Title:     strings.TrimSpace(req.Title),
+		Color:     req.Color,
+		SortOrder: req.SortOrder,
+	}
+	if err := s.repo.CreateNamespace(namespace); err != nil {
+		return nil, err
+	}
+	s.rules = nil
+
+	return namespace, nil
+}
+
+func (s *tagService) UpdateNamespace(id uint, req *models.TagNamespaceUpdateRequest) (*models.TagNamespace, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	namespace, err := s.repo.GetNamespace(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		namespace.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Color != nil {
+		if *req.Color != "" && !colorPattern.MatchString(*req.Color) {
+			return nil, fmt.Errorf("%w: 颜色格式应为 #RRGGBB", ErrInvalidNamespace)
+		}
+		namespace.Color = *req.Color
+	}
+	if req.SortOrder != nil {
+		namespace.SortOrder = *req.SortOrder
+	}
+
+	if err := s.repo.UpdateNamespace(namespace); err != nil {
+		return nil, err
+	}
+	s.rules = nil
+
+	return namespace, nil
+}
+
+// DeleteNamespace 删除命名空间，已有的 ns:value 标签保留为普通标签
+func (s *tagService) DeleteNamespace(id uint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.repo.DeleteNamespace(id); err != nil {
+		return err
+	}
+	s.rules = nil
+	return nil
+}
+
+func (s *tagService) Canonicalize(tags []string) ([]string, error) {
+	rules, err := s.currentRules()
+	if err != nil {
+		return nil, err
+	}
+	return rules.apply(tags), nil
+}
+
+func (s *tagService) CanonicalTag(tag string) (string, error) {
+	rules, err := s.currentRules()
+	if err != nil {
+		return "", err
+	}
+	return rules.canonical(tag), nil
+}
+
+func (s *tagService) ReapplyRules(ctx context.Context) (*models.TagReapplyResult, error) {
+	logger := log.Ctx(ctx).With().Str("component", "TagService").Logger()
+
+	rules, err := s.currentRules()
+	if err != nil {
+		return nil, err
+	}
+
+	const pageSize = 200
+	result := &models.TagReapplyResult{}
+	// 待审核的作品同样需要应用规则
+	filters := map[string]any{"status": models.StatusAll}
+
+	for offset := 0; ; offset += pageSize {
+		artworks, _, err := s.artworkRepo.GetAll(offset, pageSize, filters)
+		if err != nil {
+			return result, err
+		}
+
+		for _, artwork := range artworks {
+			if err := ctx.Err(); err != nil {
+				return result, err
+			}
+			result.Scanned++
+
+			before := artwork.ToResponse().Tags
+			after := rules.apply(before)
+			if slices.Equal(before, after) {
+				continue
+			}
+
+			revision, err := models.NewTagRevision(artwork.ID, models.TagRevisionReapply, ActorFrom(ctx), before, after)
+			if err != nil {
+				return result, err
+			}
+			if err := artwork.SetTags(after); err != nil {
+				return result, err
+			}
+			err = s.artworkRepo.Transaction(func(r repo.ArtworkRepo) error {
+				if err := r.UpdateFields(artwork.ID, map[string]any{"tags": artwork.Tags}); err != nil {
+					return err
+				}
+				if err := r.CreateTagRevision(revision); err != nil {
+					return err
+				}
+				return recordAudit(ctx, r, artwork.ID, models.AuditArtworkTags,
+					map[string]any{"tags": before}, map[string]any{"tags": after})
+			})
+			if err != nil {
+				return result, err
+			}
+			result.Updated++
+		}
+
+		if len(artworks) < pageSize {
+			break
+		}
+	}
+
+	logger.Info().Int("scanned", result.Scanned).Int("updated", result.Updated).Msg("标签规则已重新应用")
+
+	return result, nil
+}
+
+func (s *tagService) Describe(tags []string) []models.TagDetail {
+	details := make([]models.TagDetail, 0, len(tags))
+
+	rules, err := s.currentRules()
+	if err != nil {
+		log.Warn().Err(err).Msg("读取标签命名空间失败")
+		for _, tag := range tags {
+			details = append(details, models.TagDetail{Name: tag, Value: tag})
+		}
+		return details
+	}
+
+	for _, tag := range tags {
+		details = append(details, rules.detail(tag))
+	}
+
+	// 命名空间标签按顺序在前，普通标签在后，同组内保持原有顺序
+	slices.SortStableFunc(details, func(a, b models.TagDetail) int {
+		if (a.Namespace == "") != (b.Namespace == "") {
+			if a.Namespace == "" {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(a.SortOrder, b.SortOrder)
+	})
+	return details
+}
+
+func (s *tagService) SplitQuery(q string) ([]string, string, error) {
+	rules, err := s.currentRules()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var tags, words []string
+	for _, term := range strings.Fields(q) {
+		if _, value, ok := rules.split(term); ok && value != "" {
+			tags = append(tags, term)
+			continue
+		}
+		words = append(words, term)
+	}
+	return tags, strings.Join(words, " "), nil
+}
+
+func (s *tagService) ListTags(query models.TagListQuery) ([]models.TagUsage, int64, error) {
+	rules, err := s.currentRules()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query.Namespace = strings.ToLower(strings.TrimSpace(query.Namespace))
+	query.Prefix = strings.TrimSpace(query.Prefix)
+
+	counts, total, err := s.repo.ListTagCounts(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rules.usages(counts), total, nil
+}
+
+func (s *tagService) Autocomplete(q string, limit int, ratings []string) ([]models.TagSuggestion, error) {
+	suggestions := []models.TagSuggestion{}
+
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return suggestions, nil
+	}
+
+	rules, err := s.currentRules()
+	if err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.SearchTagCounts(q, limit, ratings)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(counts))
+	for _, usage := range rules.usages(counts) {
+		seen[usage.Name] = true
+		suggestions = append(suggestions, models.TagSuggestion{TagUsage: usage})
+	}
+
+	// 通过别名匹配：返回规范标签并注明别名
+	lower := strings.ToLower(q)
+	matched := make(map[string]string)
+	var targets []string
+	for alias, tag := range rules.aliases {
+		if seen[tag] || !strings.HasPrefix(strings.ToLower(alias), lower) {
+			continue
+		}
+		if _, ok := matched[tag]; !ok {
+			targets = append(targets, tag)
+		}
+		matched[tag] = alias
+	}
+
+	if len(targets) > 0 {
+		aliasCounts, err := s.repo.CountTags(targets, ratings)
+		if err != nil {
+			return nil, err
+		}
+		usage := make(map[string]int64, len(aliasCounts))
+		for _, c := range aliasCounts {
+			usage[c.Name] = c.Count
+		}
+		for _, tag := range targets {
+			suggestions = append(suggestions, models.TagSuggestion{
+				TagUsage: models.TagUsage{TagDetail: rules.detail(tag), Count: usage[tag]},
+				Alias:    matched[tag],
+			})
+		}
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b models.TagSuggestion) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+func (s *tagService) RelatedTags(tag string, limit int, ratings []string) ([]models.TagUsage, error) {
+	rules, err := s.currentRules()
+	if err != nil {
+		return nil, err
+	}
+
+	tag = rules.canonical(tag)
+	if tag == "" {
+		return []models.TagUsage{}, nil
+	}
+
+	counts, err := s.repo.RelatedTagCounts(tag, limit, ratings)
+	if err != nil {
+		return nil, err
+	}
+	return rules.usages(counts), nil
+}
@@ -177,6 +177,11 @@ func (fs *FileService) GetFileInfo(fileID string) (*models.FileInfo, error) {
 
 }
 
+// OpenFile 打开原图内容，调用方负责关闭
+func (fs *FileService) OpenFile(ctx context.Context, fileID string) (io.ReadCloser, *models.FileInfo, error) {
+	return fs.uploader.Open(ctx, fileID)
+}
+
 //  查询上传进度
 func (fs *FileService) GetJobProgress(ctx context.Context, jobID string) (*storage.JobProgressResponse, error) {
 	logger := log.Ctx(ctx).With().
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"pln/conf"
+	"pln/models"
+
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrInvalidURL       = errors.New("无效的图片地址")
+	ErrForbiddenAddress = errors.New("不允许访问内网或回环地址")
+	ErrNotImage         = errors.New("地址返回的不是支持的图片格式")
+	ErrTooLarge         = errors.New("图片超过大小限制")
+)
+
+// 允许导入的图片类型及对应扩展名
+var importContentTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// URLImportService 从网络地址下载图片并走标准入库流程
+type URLImportService struct {
+	cfg    conf.ImportConfig
+	cli    *http.Client
+	ingest *IngestService
+}
+
+func NewURLImportService(cfg conf.ImportConfig, ingest *IngestService) *URLImportService {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = 20 << 20
+	}
+
+	var forbidden func(net.IP) bool
+	if !cfg.AllowPrivate {
+		forbidden = isPrivateIP
+	}
+
+	return &URLImportService{
+		cfg:    cfg,
+		cli:    newImportClient(cfg, forbidden),
+		ingest: ingest,
+	}
+}
+
+// newImportClient 创建下载用的 HTTP 客户端，forbidden 不为空时拒绝连接其判定为禁止的 IP
+func newImportClient(cfg conf.ImportConfig, forbidden func(net.IP) bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if forbidden != nil {
+		// 在建立连接时检查解析后的 IP，重定向与 DNS 重绑定同样会被拦截
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || forbidden(ip) {
+				return ErrForbiddenAddress
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			// 不走代理，否则地址检查针对的是代理本身
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: cfg.Timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("重定向次数过多")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrInvalidURL
+			}
+			return nil
+		},
+	}
+}
+
+// Import 下载图片并入库，作品会记录来源链接
+func (s *URLImportService) Import(ctx context.Context, req *models.ArtworkImportURLRequest) (*models.ArtworkResponse, error) {
+	rawURL := req.URL
+
+	logger := log.Ctx(ctx).With().
+		Str("component", "URLImportService").
+		Str("url", rawURL).
+		Logger()
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrInvalidURL
+	}
+
+	data, ext, err := s.fetch(ctx, u.String())
+	if err != nil {
+		logger.Warn().Err(err).Msg("下载图片失败")
+		return nil, err
+	}
+
+	filename := strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path))
+	if filename == "" || filename == "." || filename == "/" {
+		filename = "image"
+	}
+
+	return s.ingest.Ingest(ctx, &IngestRequest{
+		Filename:    filename + ext,
+		Data:        data,
+		Tags:        req.Tags,
+		SourceURL:   u.String(),
+		Artist:      req.Artist,
+		Title:       req.Title,
+		Description: req.Description,
+		License:     req.License,
+		Rating:      req.Rating,
+	})
+}
+
+// fetch 下载图片内容，返回数据与根据类型推断的扩展名
+func (s *URLImportService) fetch(ctx context.Context, rawURL string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", ErrInvalidURL
+	}
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := s.cli.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrForbiddenAddress) {
+			return nil, "", ErrForbiddenAddress
+		}
+		return nil, "", fmt.Errorf("下载图片失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("下载图片失败，状态码: %d", resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	ext, ok := importContentTypes[mediaType]
+	if !ok {
+		return nil, "", ErrNotImage
+	}
+
+	if resp.ContentLength > s.cfg.MaxSize {
+		return nil, "", ErrTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, s.cfg.MaxSize+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("读取图片失败: %w", err)
+	}
+	if int64(len(data)) > s.cfg.MaxSize {
+		return nil, "", ErrTooLarge
+	}
+
+	// 以内容嗅探结果为准，防止伪造的 Content-Type
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	if sniffedExt, ok := importContentTypes[sniffed]; ok {
+		ext = sniffedExt
+	} else {
+		return nil, "", ErrNotImage
+	}
+
+	return data, ext, nil
+}
+
+// isPrivateIP 判断是否为内网、回环、链路本地等不允许访问的地址
+func isPrivateIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+
+	// 运营商级 NAT 地址 100.64.0.0/10
+	if ip4 := ip.To4(); ip4 != nil && ip4[0] == 100 && ip4[1]&0xc0 == 64 {
+		return true
+	}
+	return false
+}
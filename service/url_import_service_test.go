@@ -0,0 +1,183 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"pln/conf"
+)
+
+// testPNG 生成一张可被内容嗅探识别的 PNG
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func serveBytes(contentType string, data []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write(data)
+	}
+}
+
+func newTestImporter(cfg conf.ImportConfig) *URLImportService {
+	if cfg.MaxSize == 0 {
+		cfg.MaxSize = 1 << 20
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	return NewURLImportService(cfg, nil)
+}
+
+func TestURLImportFetchPrivateAddress(t *testing.T) {
+	img := testPNG(t)
+	srv := httptest.NewServer(serveBytes("image/png", img))
+	defer srv.Close()
+
+	t.Run("blocked", func(t *testing.T) {
+		s := newTestImporter(conf.ImportConfig{AllowPrivate: false})
+		if _, _, err := s.fetch(t.Context(), srv.URL+"/a.png"); !errors.Is(err, ErrForbiddenAddress) {
+			t.Fatalf("err = %v, want ErrForbiddenAddress", err)
+		}
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		s := newTestImporter(conf.ImportConfig{AllowPrivate: true})
+		data, ext, err := s.fetch(t.Context(), srv.URL+"/a.png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ext != ".png" || !bytes.Equal(data, img) {
+			t.Fatalf("got ext %q and %d bytes, want .png and %d bytes", ext, len(data), len(img))
+		}
+	})
+}
+
+func TestURLImportFetchRedirectToPrivate(t *testing.T) {
+	srv := httptest.NewServer(http.RedirectHandler("http://169.254.169.254/latest/meta-data", http.StatusFound))
+	defer srv.Close()
+
+	// 放行测试服务器所在的回环地址，检查重定向后的连接仍会被拦截
+	s := newTestImporter(conf.ImportConfig{})
+	s.cli = newImportClient(s.cfg, func(ip net.IP) bool {
+		return !ip.IsLoopback() && isPrivateIP(ip)
+	})
+
+	if _, _, err := s.fetch(t.Context(), srv.URL); !errors.Is(err, ErrForbiddenAddress) {
+		t.Fatalf("err = %v, want ErrForbiddenAddress", err)
+	}
+}
+
+func TestURLImportFetchTooManyRedirects(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, srv.URL, http.StatusFound)
+	}))
+	defer srv.Close()
+
+	s := newTestImporter(conf.ImportConfig{AllowPrivate: true})
+	if _, _, err := s.fetch(t.Context(), srv.URL); err == nil {
+		t.Fatal("expected redirect loop to fail")
+	}
+}
+
+func TestURLImportFetchTooLarge(t *testing.T) {
+	img := testPNG(t)
+	large := append(img, make([]byte, 4096)...)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"content length", serveBytes("image/png", large)},
+		{"chunked", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			for i := 0; i < len(large); i += 512 {
+				w.Write(large[i:min(i+512, len(large))])
+				w.(http.Flusher).Flush()
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			s := newTestImporter(conf.ImportConfig{AllowPrivate: true, MaxSize: int64(len(img)) + 100})
+			if _, _, err := s.fetch(t.Context(), srv.URL); !errors.Is(err, ErrTooLarge) {
+				t.Fatalf("err = %v, want ErrTooLarge", err)
+			}
+		})
+	}
+}
+
+func TestURLImportFetchNotImage(t *testing.T) {
+	html := []byte("<!DOCTYPE html><html><body>not an image</body></html>")
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+	}{
+		{"html content type", "text/html; charset=utf-8", html},
+		{"unsupported image type", "image/svg+xml", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)},
+		{"spoofed content type", "image/png", html},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(serveBytes(tt.contentType, tt.body))
+			defer srv.Close()
+
+			s := newTestImporter(conf.ImportConfig{AllowPrivate: true})
+			if _, _, err := s.fetch(t.Context(), srv.URL); !errors.Is(err, ErrNotImage) {
+				t.Fatalf("err = %v, want ErrNotImage", err)
+			}
+		})
+	}
+}
+
+func TestURLImportFetchTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	s := newTestImporter(conf.ImportConfig{AllowPrivate: true, Timeout: 200 * time.Millisecond})
+	start := time.Now()
+	if _, _, err := s.fetch(t.Context(), srv.URL); err == nil {
+		t.Fatal("expected timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("fetch took %s, want it to stop near the configured timeout", elapsed)
+	}
+}
+
+func TestURLImportFetchStatus(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	s := newTestImporter(conf.ImportConfig{AllowPrivate: true})
+	_, _, err := s.fetch(t.Context(), srv.URL)
+	if err == nil || !strings.Contains(err.Error(), strconv.Itoa(http.StatusNotFound)) {
+		t.Fatalf("err = %v, want status 404 error", err)
+	}
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"pln/models"
+	"pln/repo"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("用户名或密码错误")
+	ErrInvalidUser        = errors.New("无效的用户信息")
+	ErrUserExists         = errors.New("用户名已存在")
+	// ErrInvalidSession 会话不存在、已过期或用户已被禁用
+	ErrInvalidSession = errors.New("会话无效或已过期")
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.@-]{0,63}$`)
+
+// 密码长度限制，bcrypt 只使用前 72 字节
+const (
+	minPasswordLen = 8
+	maxPasswordLen = 72
+)
+
+// dummyHash 用户不存在时同样执行一次比较，避免通过响应时间判断用户名是否存在
+var dummyHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("pln-dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
+
+type UserService interface {
+	// Login 校验用户名密码并创建会话，返回的令牌只在此时出现
+	Login(req *models.LoginRequest, userAgent, ip string) (*models.LoginResponse, error)
+	// LoginExternal 单点登录：按外部 ID 查找或创建用户，同步角色后创建会话
+	LoginExternal(identity *models.ExternalIdentity, userAgent, ip string) (*models.LoginResponse, error)
+	Logout(token string) error
+	// Authenticate 根据会话令牌查找用户
+	Authenticate(token string) (*models.User, error)
+
+	ListUsers(page, pageSize int) ([]models.User, int64, error)
+	GetUser(id uint) (*models.User, error)
+	CountUsers() (int64, error)
+	CreateUser(ctx context.Context, req *models.UserCreateRequest) (*models.User, error)
+	UpdateUser(ctx context.Context, id uint, req *models.UserUpdateRequest) (*models.User, error)
+	DeleteUser(ctx context.Context, id uint) error
+}
+
+type userService struct {
+	repo       repo.UserRepo
+	sessionTTL time.Duration
+}
+
+func NewUserService(repo repo.UserRepo, sessionTTL time.Duration) UserService {
+	return &userService{repo: repo, sessionTTL: sessionTTL}
+}
+
+func (s *userService) Login(req *models.LoginRequest, userAgent, ip string) (*models.LoginResponse, error) {
+	user, err := s.repo.GetByUsername(strings.TrimSpace(req.Username))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(req.Password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil || user.Disabled {
+		return nil, ErrInvalidCredentials
+	}
+
+	return s.startSession(user, userAgent, ip, nil)
+}
+
+func (s *userService) LoginExternal(identity *models.ExternalIdentity, userAgent, ip string) (*models.LoginResponse, error) {
+	if !usernamePattern.MatchString(identity.Username) {
+		return nil, fmt.Errorf("%w: 身份提供方返回的用户名 %q 无效", ErrInvalidUser, identity.Username)
+	}
+	if !models.ValidRole(identity.Role) {
+		return nil, fmt.Errorf("%w: 无效的角色 %q", ErrInvalidUser, identity.Role)
+	}
+
+	user, err := s.repo.GetByExternalID(identity.ExternalID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 首次登录时创建用户，用户名被本地账户占用时拒绝，避免接管已有账户
+		user = &models.User{Username: identity.Username, ExternalID: &identity.ExternalID}
+	} else if err != nil {
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, ErrInvalidCredentials
+	}
+	before := userAudit(user)
+	user.Role = identity.Role
+
+	// 角色由身份提供方同步，审计记录的操作者为 system
+	ctx := WithRequest(WithActor(context.Background(), SystemActor), RequestInfo{IP: ip})
+	return s.startSession(user, userAgent, ip, func(r repo.UserRepo) error {
+		if user.ID != 0 {
+			return recordTargetAudit(ctx, r, userTarget(user.ID), models.AuditUserUpdate, before, userAudit(user))
+		}
+		if _, err := r.GetByUsername(user.Username); err == nil {
+			return ErrUserExists
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := r.Create(user); err != nil {
+			return err
+		}
+		return recordTargetAudit(ctx, r, userTarget(user.ID), models.AuditUserCreate, nil, userAudit(user))
+	})
+}
+
+// startSession 在事务中执行 prepare 后为用户创建会话，并更新最后登录时间
+func (s *userService) startSession(user *models.User, userAgent, ip string, prepare func(r repo.UserRepo) error) (*models.LoginResponse, error) {
+	token, err := newSessionToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		TokenHash: hashToken(token),
+		UserAgent: userAgent,
+		IP:        ip,
+		ExpiresAt: now.Add(s.sessionTTL),
+	}
+	err = s.repo.Transaction(func(r repo.UserRepo) error {
+		if prepare != nil {
+			if err := prepare(r); err != nil {
+				return err
+			}
+		}
+		// 顺带清理过期会话
+		if err := r.DeleteExpiredSessions(now); err != nil {
+			return err
+		}
+		session.UserID = user.ID
+		if err := r.CreateSession(session); err != nil {
+			return err
+		}
+		user.LastLoginAt = &now
+		return r.Update(user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoginResponse{Token: token, ExpiresAt: session.ExpiresAt, User: *user}, nil
+}
+
+func (s *userService) Logout(token string) error {
+	if token == "" {
+		return nil
+	}
+	return s.repo.DeleteSession(hashToken(token))
+}
+
+func (s *userService) Authenticate(token string) (*models.User, error) {
+	if token == "" {
+		return nil, ErrInvalidSession
+	}
+	session, err := s.repo.GetSession(hashToken(token), time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidSession
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetByID(session.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidSession
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled {
+		return nil, ErrInvalidSession
+	}
+	return user, nil
+}
+
+func (s *userService) ListUsers(page, pageSize int) ([]models.User, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+	return s.repo.List((page-1)*pageSize, pageSize)
+}
+
+func (s *userService) GetUser(id uint) (*models.User, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *userService) CountUsers() (int64, error) {
+	return s.repo.Count()
+}
+
+func (s *userService) CreateUser(ctx context.Context, req *models.UserCreateRequest) (*models.User, error) {
+	username := strings.TrimSpace(req.Username)
+	if !usernamePattern.MatchString(username) {
+		return nil, fmt.Errorf("%w: 用户名只能包含字母、数字与 _ . @ -，且不超过 64 个字符", ErrInvalidUser)
+	}
+	role := req.Role
+	if role == "" {
+		role = models.RoleViewer
+	}
+	if !models.ValidRole(role) {
+		return nil, fmt.Errorf("%w: 角色可选值为 %s", ErrInvalidUser, strings.Join(models.Roles, ", "))
+	}
+	hash, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{Username: username, PasswordHash: hash, Role: role}
+	err = s.repo.Transaction(func(r repo.UserRepo) error {
+		if _, err := r.GetByUsername(username); err == nil {
+			return ErrUserExists
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := r.Create(user); err != nil {
+			return err
+		}
+		return recordTargetAudit(ctx, r, userTarget(user.ID), models.AuditUserCreate, nil, userAudit(user))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *userService) UpdateUser(ctx context.Context, id uint, req *models.UserUpdateRequest) (*models.User, error) {
+	var user *models.User
+	err := s.repo.Transaction(func(r repo.UserRepo) error {
+		var err error
+		user, err = r.GetByID(id)
+		if err != nil {
+			return err
+		}
+		before := userAudit(user)
+		after := userAudit(user)
+
+		revoke := false
+		if req.Password != nil {
+			hash, err := hashPassword(*req.Password)
+			if err != nil {
+				return err
+			}
+			user.PasswordHash = hash
+			revoke = true
+			after["password_reset"] = true
+		}
+		if req.Role != nil {
+			if !models.ValidRole(*req.Role) {
+				return fmt.Errorf("%w: 角色可选值为 %s", ErrInvalidUser, strings.Join(models.Roles, ", "))
+			}
+			user.Role = *req.Role
+			after["role"] = user.Role
+		}
+		if req.Disabled != nil {
+			user.Disabled = *req.Disabled
+			revoke = revoke || user.Disabled
+			after["disabled"] = user.Disabled
+		}
+
+		if err := r.Update(user); err != nil {
+			return err
+		}
+		if revoke {
+			if err := r.DeleteUserSessions(id); err != nil {
+				return err
+			}
+		}
+		return recordTargetAudit(ctx, r, userTarget(id), models.AuditUserUpdate, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *userService) DeleteUser(ctx context.Context, id uint) error {
+	return s.repo.Transaction(func(r repo.UserRepo) error {
+		user, err := r.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if err := r.Delete(id); err != nil {
+			return err
+		}
+		return recordTargetAudit(ctx, r, userTarget(id), models.AuditUserDelete, userAudit(user), nil)
+	})
+}
+
+// userTarget 审计记录中用户的对象标识
+func userTarget(id uint) string {
+	return fmt.Sprintf("user:%d", id)
+}
+
+// userAudit 用户审计记录中的字段，不含密码哈希等凭据
+func userAudit(user *models.User) map[string]any {
+	return map[string]any{"username": user.Username, "role": user.Role, "disabled": user.Disabled}
+}
+
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLen || len(password) > maxPasswordLen {
+		return "", fmt.Errorf("%w: 密码长度需为 %d 到 %d 字节", ErrInvalidUser, minPasswordLen, maxPasswordLen)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// newSessionToken 生成 32 字节的随机会话令牌
+func newSessionToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hashToken 数据库中只保存令牌的 SHA-256
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"pln/conf"
+	"pln/metrics"
 	"pln/models"
 
 	"github.com/nfnt/resize"
@@ -106,7 +107,10 @@ func (l *LocalUploader) ensureVariants(id, ext string, data []byte) {
 			continue
 		}
 
-		if err := generateResized(bytes.NewReader(data), variantPath, v.width, v.height, v.quality, outExt); err != nil {
+		start := time.Now()
+		err := generateResized(bytes.NewReader(data), variantPath, v.width, v.height, v.quality, outExt)
+		metrics.ObserveVariant(strings.TrimPrefix(v.suffix, "_"), time.Since(start), err)
+		if err != nil {
 			log.Warn().Err(err).Str("variant", v.suffix).Str("file_id", id).Msg("生成变体失败，跳过")
 		}
 	}
@@ -205,6 +209,20 @@ func (l *LocalUploader) GetFileInfo(fileID string) (*models.FileInfo, error) {
 	return info, nil
 }
 
+// Open opens the original file for reading
+func (l *LocalUploader) Open(ctx context.Context, fileID string) (io.ReadCloser, *models.FileInfo, error) {
+	info, err := l.GetFileInfo(fileID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(filepath.Join(l.storagePath, info.Path))
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, info, nil
+}
+
 // findOriginal finds the original file for a fileID (excluding variant files)
 func (l *LocalUploader) findOriginal(fileID string) string {
 	matches, _ := filepath.Glob(filepath.Join(l.storagePath, fileID+".*"))
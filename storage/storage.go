@@ -10,6 +10,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"pln/models"
+	"strings"
 	"time"
 
 	"github.com/Yuelioi/gkit/web/response"
@@ -47,6 +48,7 @@ type Uploader interface {
 	Delete(ctx context.Context, fileID string) error
 	GetJobProgress(ctx context.Context, jobID string) (*JobProgressResponse, error)
 	GetFileInfo(fileID string) (*models.FileInfo, error)
+	Open(ctx context.Context, fileID string) (io.ReadCloser, *models.FileInfo, error)
 }
 
 // ThirdPartyUploader implements Uploader interface
@@ -222,6 +224,36 @@ func (t *ThirdPartyUploader) GetFileInfo(path string) (*models.FileInfo, error)
 
 }
 
+// Open 打开原图内容（通过文件信息中的访问地址下载）
+func (t *ThirdPartyUploader) Open(ctx context.Context, fileID string) (io.ReadCloser, *models.FileInfo, error) {
+	info, err := t.GetFileInfo(fileID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	url := info.AccessURL
+	if strings.HasPrefix(url, "/") {
+		url = t.baseURL + url
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建下载请求失败: %w", err)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("下载文件失败: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, nil, fmt.Errorf("下载文件失败，状态码: %d", resp.StatusCode)
+	}
+
+	return resp.Body, info, nil
+}
+
 // Helper function to convert map to options for easy usage
 
 func DecodeResponseBody[T any](body io.Reader) (*T, error) {